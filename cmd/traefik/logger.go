@@ -35,7 +35,9 @@ func setupLogger(staticConfiguration *static.Configuration) {
 
 	log.Logger = logCtx.Logger().Level(logLevel)
 	zerolog.DefaultContextLogger = &log.Logger
-	zerolog.SetGlobalLevel(logLevel)
+	// The level is enforced by the loggers, all derived from the global one, rather than globally,
+	// so that the loggers of some objects can override it (e.g. the traefik.io/log-level annotation).
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
 
 	// Global logrus replacement (related to lib like go-rancher-metadata, docker, etc.)
 	logrus.StandardLogger().Out = logs.NoLevel(log.Logger, zerolog.DebugLevel)
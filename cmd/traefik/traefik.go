@@ -278,6 +278,22 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	routerFactory := server.NewRouterFactory(*staticConfiguration, managerFactory, tlsManager, observabilityMgr, pluginBuilder, dialerManager)
 
+	if staticConfiguration.TCPFlowExport != nil {
+		flowExporter, err := tcp.NewFlowExporter(
+			staticConfiguration.TCPFlowExport.Address,
+			staticConfiguration.TCPFlowExport.Template,
+			staticConfiguration.TCPFlowExport.EnterpriseNumber,
+			staticConfiguration.TCPFlowExport.BufferSize,
+			time.Duration(staticConfiguration.TCPFlowExport.FlushInterval),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the TCP flow exporter: %w", err)
+		}
+
+		routinesPool.GoCtx(flowExporter.Run)
+		routerFactory.SetFlowRecorder(flowExporter)
+	}
+
 	// Watcher
 
 	watcher := server.NewConfigurationWatcher(
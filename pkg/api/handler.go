@@ -51,12 +51,23 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// tcpStream outlives the handler, which is built for each configuration.
+	tcpStream *TCPConfigurationStream
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
+// Each built handler publishes its configuration to the subscribers of the TCP configuration stream.
 func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+	tcpStream := NewTCPConfigurationStream()
+
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		tcpStream.update(configuration)
+
+		handler := New(staticConfig, configuration)
+		handler.tcpStream = tcpStream
+
+		return handler.createRouter()
 	}
 }
 
@@ -80,6 +91,7 @@ func (h Handler) createRouter() *mux.Router {
 
 	if h.staticConfig.API.Debug {
 		DebugHandler{}.Append(router)
+		router.Methods(http.MethodGet).Path("/debug/tcp/tls").HandlerFunc(h.getTCPRoutersTLS)
 	}
 
 	router.Methods(http.MethodGet).Path("/api/rawdata").HandlerFunc(h.getRuntimeConfiguration)
@@ -109,6 +121,10 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
 
+	if h.tcpStream != nil {
+		router.Methods(http.MethodPost).Path(TCPConfigurationWatchPath).Handler(h.tcpStream.server)
+	}
+
 	version.Handler{}.Append(router)
 
 	return router
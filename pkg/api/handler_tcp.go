@@ -116,6 +116,24 @@ func (h Handler) getTCPRouter(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+// getTCPRoutersTLS serves the effective TLS options of the TCP routers terminating TLS, by router.
+func (h Handler) getTCPRoutersTLS(rw http.ResponseWriter, request *http.Request) {
+	results := make(map[string]*runtime.TCPRouterTLSInfo)
+	for name, rt := range h.runtimeConfiguration.TCPRouters {
+		if rt.ResolvedTLS != nil {
+			results[name] = rt.ResolvedTLS
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(rw).Encode(results)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func (h Handler) getTCPServices(rw http.ResponseWriter, request *http.Request) {
 	results := make([]tcpServiceRepresentation, 0, len(h.runtimeConfiguration.TCPServices))
 
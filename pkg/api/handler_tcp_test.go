@@ -922,3 +922,67 @@ func TestHandler_TCP(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_TCPRoutersTLS(t *testing.T) {
+	rtConf := &runtime.Configuration{
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"terminated@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service@myprovider",
+					Rule:        "HostSNI(`foo.bar`)",
+					TLS:         &dynamic.RouterTCPTLSConfig{Options: "foo"},
+				},
+				ResolvedTLS: &runtime.TCPRouterTLSInfo{
+					Options:        "foo@myprovider",
+					MinVersion:     "VersionTLS12",
+					CipherSuites:   []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+					ClientAuthType: "RequireAndVerifyClientCert",
+				},
+			},
+			"passthrough@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service@myprovider",
+					Rule:        "HostSNI(`bar.foo`)",
+					TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: true},
+				},
+			},
+		},
+	}
+
+	handler := New(static.Configuration{API: &static.API{Debug: true}, Global: &static.Global{}}, rtConf)
+	server := httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	resp, err := http.DefaultClient.Get(server.URL + "/debug/tcp/tls")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	contents, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// Only the routers terminating TLS are listed, with their resolved TLS options.
+	assert.JSONEq(t, `{
+		"terminated@myprovider": {
+			"options": "foo@myprovider",
+			"minVersion": "VersionTLS12",
+			"cipherSuites": ["TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"],
+			"clientAuthType": "RequireAndVerifyClientCert"
+		}
+	}`, string(contents))
+
+	// The resolved TLS options are not exposed when the debug API is disabled.
+	handler = New(static.Configuration{API: &static.API{}, Global: &static.Global{}}, rtConf)
+	server = httptest.NewServer(handler.createRouter())
+	t.Cleanup(server.Close)
+
+	resp, err = http.DefaultClient.Get(server.URL + "/debug/tcp/tls")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
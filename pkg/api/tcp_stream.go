@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TCPConfigurationWatchPath is the path of the gRPC method streaming the TCP configuration.
+// The service name starts with api, for the method to be routed, and secured, as the other API endpoints.
+const TCPConfigurationWatchPath = "/api.TCPConfiguration/Watch"
+
+// tcpConfigurationSnapshot is the TCP configuration streamed to the subscribers.
+type tcpConfigurationSnapshot struct {
+	// Version is incremented on each configuration update,
+	// for the subscribers to skip the snapshots they already applied.
+	Version  uint64                         `json:"version"`
+	Routers  map[string]*dynamic.TCPRouter  `json:"routers"`
+	Services map[string]*dynamic.TCPService `json:"services"`
+}
+
+// tcpConfigurationWatcher is the handler type of the gRPC service streaming the TCP configuration.
+type tcpConfigurationWatcher interface {
+	watch(stream grpc.ServerStream) error
+}
+
+// TCPConfigurationStream streams the TCP routers and services, with their servers, to gRPC subscribers,
+// the current configuration being sent on subscription, and then each configuration update.
+type TCPConfigurationStream struct {
+	server *grpc.Server
+
+	mu      sync.Mutex
+	version uint64
+	current *structpb.Struct
+	// subscribers only hold the last snapshot not yet sent,
+	// so that a slow subscriber skips the intermediate versions instead of blocking the updates.
+	subscribers map[chan *structpb.Struct]struct{}
+}
+
+// NewTCPConfigurationStream creates a new TCPConfigurationStream.
+func NewTCPConfigurationStream() *TCPConfigurationStream {
+	s := &TCPConfigurationStream{
+		server:      grpc.NewServer(),
+		subscribers: make(map[chan *structpb.Struct]struct{}),
+	}
+
+	s.server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "api.TCPConfiguration",
+		HandlerType: (*tcpConfigurationWatcher)(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName: "Watch",
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				if err := stream.RecvMsg(&emptypb.Empty{}); err != nil {
+					return err
+				}
+
+				return srv.(tcpConfigurationWatcher).watch(stream)
+			},
+			ServerStreams: true,
+		}},
+	}, s)
+
+	return s
+}
+
+// update publishes the TCP configuration of the given runtime configuration to the subscribers, as a new version.
+func (s *TCPConfigurationStream) update(configuration *runtime.Configuration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := tcpConfigurationSnapshot{
+		Version:  s.version + 1,
+		Routers:  make(map[string]*dynamic.TCPRouter, len(configuration.TCPRouters)),
+		Services: make(map[string]*dynamic.TCPService, len(configuration.TCPServices)),
+	}
+	for name, router := range configuration.TCPRouters {
+		snapshot.Routers[name] = router.TCPRouter
+	}
+	for name, service := range configuration.TCPServices {
+		// The hash seed is a secret, which must not be streamed to the subscribers.
+		tcpService := service.TCPService.DeepCopy()
+		if tcpService != nil && tcpService.LoadBalancer != nil {
+			tcpService.LoadBalancer.HashSeed = ""
+		}
+		snapshot.Services[name] = tcpService
+	}
+
+	message, err := toStruct(snapshot)
+	if err != nil {
+		log.Error().Err(err).Msg("Cannot stream the TCP configuration")
+		return
+	}
+
+	s.version = snapshot.Version
+	s.current = message
+
+	for subscriber := range s.subscribers {
+		// The snapshot not yet sent to the subscriber, if any, is replaced by this one.
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- message
+	}
+}
+
+func (s *TCPConfigurationStream) watch(stream grpc.ServerStream) error {
+	subscriber := make(chan *structpb.Struct, 1)
+
+	s.mu.Lock()
+	if s.current != nil {
+		subscriber <- s.current
+	}
+	s.subscribers[subscriber] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, subscriber)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case message := <-subscriber:
+			if err := stream.SendMsg(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toStruct converts the given value to a protobuf Struct, through its JSON representation.
+func toStruct(value any) (*structpb.Struct, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling: %w", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshaling: %w", err)
+	}
+
+	return structpb.NewStruct(fields)
+}
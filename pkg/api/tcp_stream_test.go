@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func tcpConfiguration(address string) *runtime.Configuration {
+	return &runtime.Configuration{
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"foo@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "foo@myprovider",
+					Rule:        "HostSNI(`foo.bar`)",
+				},
+			},
+		},
+		TCPServices: map[string]*runtime.TCPServiceInfo{
+			"foo@myprovider": {
+				TCPService: &dynamic.TCPService{
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers:  []dynamic.TCPServer{{Address: address}},
+						HashSeed: "secret",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTCPConfigurationStream(t *testing.T) {
+	builder := NewBuilder(static.Configuration{API: &static.API{}})
+
+	server := httptest.NewUnstartedServer(builder(tcpConfiguration("10.0.0.1:5432")))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	conn, err := grpc.Dial(server.Listener.Addr().String(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, TCPConfigurationWatchPath)
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&emptypb.Empty{}))
+	require.NoError(t, stream.CloseSend())
+
+	// The current configuration is sent on subscription.
+	snapshot := &structpb.Struct{}
+	require.NoError(t, stream.RecvMsg(snapshot))
+
+	assert.InDelta(t, 1, snapshot.Fields["version"].GetNumberValue(), 0)
+	assert.Equal(t, map[string]any{
+		"foo@myprovider": map[string]any{
+			"entryPoints": []any{"web"},
+			"service":     "foo@myprovider",
+			"rule":        "HostSNI(`foo.bar`)",
+		},
+	}, snapshot.Fields["routers"].GetStructValue().AsMap())
+
+	// The configuration changes.
+	builder(tcpConfiguration("10.0.0.2:5432"))
+
+	snapshot = &structpb.Struct{}
+	require.NoError(t, stream.RecvMsg(snapshot))
+
+	assert.InDelta(t, 2, snapshot.Fields["version"].GetNumberValue(), 0)
+	assert.Equal(t, map[string]any{
+		"foo@myprovider": map[string]any{
+			"loadBalancer": map[string]any{
+				"servers": []any{map[string]any{"address": "10.0.0.2:5432"}},
+			},
+		},
+	}, snapshot.Fields["services"].GetStructValue().AsMap())
+	assert.NotContains(t, snapshot.String(), "secret")
+}
@@ -33,6 +33,7 @@ type TCPModel struct {
 type TCPService struct {
 	LoadBalancer *TCPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *TCPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Mirroring    *TCPMirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -58,6 +59,27 @@ func (w *TCPWRRService) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// TCPMirroring is a tcp service forwarding the connections to a service, and mirroring a fraction of them to a mirror service,
+// whose responses are compared to the ones of the service, for regression testing.
+// The responses of the service are the only ones sent to the clients.
+type TCPMirroring struct {
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Mirror  string `json:"mirror,omitempty" toml:"mirror,omitempty" yaml:"mirror,omitempty" export:"true"`
+	// Percent defines the percentage of the connections mirrored to the mirror service.
+	Percent int `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// MaxCompareSize defines the maximum number of bytes of the responses compared,
+	// which also bounds the bytes of a client buffered for the mirror service,
+	// the connection not being compared when the mirror service does not read them fast enough.
+	MaxCompareSize int64 `json:"maxCompareSize,omitempty" toml:"maxCompareSize,omitempty" yaml:"maxCompareSize,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPMirroring.
+func (m *TCPMirroring) SetDefaults() {
+	m.MaxCompareSize = 64 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPRouter holds the router configuration.
 type TCPRouter struct {
 	EntryPoints []string            `json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
@@ -73,19 +95,81 @@ type TCPRouter struct {
 
 // RouterTCPTLSConfig holds the TLS configuration for a router.
 type RouterTCPTLSConfig struct {
-	Passthrough  bool           `json:"passthrough" toml:"passthrough" yaml:"passthrough" export:"true"`
-	Options      string         `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
-	CertResolver string         `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
-	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
+	Passthrough              bool           `json:"passthrough" toml:"passthrough" yaml:"passthrough" export:"true"`
+	Options                  string         `json:"options,omitempty" toml:"options,omitempty" yaml:"options,omitempty" export:"true"`
+	CertResolver             string         `json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
+	Domains                  []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
+	CloseOnCertificateChange bool           `json:"closeOnCertificateChange,omitempty" toml:"closeOnCertificateChange,omitempty" yaml:"closeOnCertificateChange,omitempty" export:"true"`
+	HandshakeFailureService  string         `json:"handshakeFailureService,omitempty" toml:"handshakeFailureService,omitempty" yaml:"handshakeFailureService,omitempty" export:"true"`
 }
 
+// Load balancing strategies of the TCPServersLoadBalancer.
+const (
+	TCPBalancerStrategyRoundRobin        = "roundRobin"
+	TCPBalancerStrategyConsistentHashing = "consistentHashing"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPServersLoadBalancer holds the LoadBalancerService configuration.
 type TCPServersLoadBalancer struct {
-	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
-	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
-	ServersTransport string         `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	ProxyProtocol    *ProxyProtocol        `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Servers          []TCPServer           `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	ServersTransport string                `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	Sticky           *TCPSticky            `json:"sticky,omitempty" toml:"sticky,omitempty" yaml:"sticky,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	HealthCheck      *TCPServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// HalfClose defines whether the proxy propagates the half-close of a connection by one of its peers to the other peer,
+	// which keeps on being able to write, instead of fully terminating the connection after the termination delay.
+	HalfClose bool `json:"halfClose,omitempty" toml:"halfClose,omitempty" yaml:"halfClose,omitempty" export:"true"`
+	// NoDelay defines whether Nagle's algorithm is disabled (TCP_NODELAY) on both the client and the server connections.
+	// Disabling it sends the small writes right away, at the cost of more packets for the same throughput.
+	// By default, the option of the connections is left to the platform default, which disables Nagle's algorithm.
+	NoDelay *bool `json:"noDelay,omitempty" toml:"noDelay,omitempty" yaml:"noDelay,omitempty" export:"true"`
+	// Strategy defines the load balancing strategy between the servers:
+	// roundRobin (the default), or consistentHashing, which forwards the connections of a client IP to the same server,
+	// only remapping the clients of the servers which are added or removed.
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	// HashSeed defines a seed mixed into the hashes assigning the clients to the servers,
+	// with the consistentHashing strategy, and with the client certificate stickiness,
+	// so that the assignment cannot be inferred externally, and differs across the deployments using different seeds.
+	// Changing the seed reshuffles the assignment of the clients to the servers.
+	HashSeed string `json:"hashSeed,omitempty" toml:"hashSeed,omitempty" yaml:"hashSeed,omitempty" loggable:"false"`
+	// PerAttemptDialTimeout defines the timeout of each attempt to dial a server.
+	// When set, the connections fail over to the next servers when a server cannot be dialed.
+	PerAttemptDialTimeout ptypes.Duration `json:"perAttemptDialTimeout,omitempty" toml:"perAttemptDialTimeout,omitempty" yaml:"perAttemptDialTimeout,omitempty" export:"true"`
+	// ConnectTimeout defines the overall time budget to connect to a server, across all the dial attempts of a connection.
+	// It requires PerAttemptDialTimeout, and cannot be lower than it.
+	ConnectTimeout ptypes.Duration `json:"connectTimeout,omitempty" toml:"connectTimeout,omitempty" yaml:"connectTimeout,omitempty" export:"true"`
+	// IdleTimeout defines the duration after which the connections on which no data is transferred in either direction are closed.
+	// By default, the connections are never closed for idleness.
+	IdleTimeout ptypes.Duration `json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	// MaxConnectionDuration defines the duration after which the connections are closed, whether data is transferred or not.
+	// Unlike IdleTimeout, it is not postponed by the activity of the connections.
+	// By default, the connections are not limited in duration.
+	MaxConnectionDuration ptypes.Duration `json:"maxConnectionDuration,omitempty" toml:"maxConnectionDuration,omitempty" yaml:"maxConnectionDuration,omitempty" export:"true"`
+	// MaxConnectionsPerServer defines the maximum number of connections forwarded concurrently to each server.
+	// The servers at their maximum are skipped by the load balancing, and the connections are only rejected when all the servers are at their maximum.
+	// By default, the connections per server are not limited.
+	MaxConnectionsPerServer int `json:"maxConnectionsPerServer,omitempty" toml:"maxConnectionsPerServer,omitempty" yaml:"maxConnectionsPerServer,omitempty" export:"true"`
+	// Audit defines whether an audit entry is logged for each connection forwarded to a server, before connecting to it.
+	// The audit entries are logged regardless of the log level.
+	Audit bool `json:"audit,omitempty" toml:"audit,omitempty" yaml:"audit,omitempty" export:"true"`
+	// PrefixFrame defines the Go template of a metadata frame written to the server connections before the data of the client,
+	// for the servers to know the routing context of the connections.
+	// The template data are the attributes of the connection: router, and sni for the TLS connections.
+	// By default, no frame is written.
+	PrefixFrame string `json:"prefixFrame,omitempty" toml:"prefixFrame,omitempty" yaml:"prefixFrame,omitempty" export:"true"`
+	// CloseReasonFrames defines the frames written to the clients before closing their connections, by close reason:
+	// limitExceeded, when the connection is rejected by a connection limit of its router,
+	// backendDown, when the connection cannot be forwarded to any server of the service,
+	// and draining, when the router of the connection is removed, or its entry point is shut down.
+	// By default, the connections are closed without frame.
+	CloseReasonFrames map[string]string `json:"closeReasonFrames,omitempty" toml:"closeReasonFrames,omitempty" yaml:"closeReasonFrames,omitempty" export:"true"`
+	// Priority defines the priority of the connections of the service under connection pressure, from -10 to 10,
+	// when the TCP admission is enabled in the static configuration:
+	// the connections of the lowest priority services are rejected first as the number of connections approaches its maximum.
+	// By default, the priority is 0.
+	Priority int `json:"priority,omitempty" toml:"priority,omitempty" yaml:"priority,omitempty" export:"true"`
 
 	// TerminationDelay, corresponds to the deadline that the proxy sets, after one
 	// of its connected peers indicates it has closed the writing capability of its
@@ -124,6 +208,40 @@ type TCPServer struct {
 
 // +k8s:deepcopy-gen=true
 
+// TCPServerHealthCheck holds the TCP HealthCheck configuration.
+type TCPServerHealthCheck struct {
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Timeout  ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// Jitter defines the maximum random deviation of each interval between two health checks, as a percentage of the interval.
+	// It spreads the health checks of the services sharing the same interval over time.
+	Jitter int `json:"jitter,omitempty" toml:"jitter,omitempty" yaml:"jitter,omitempty" export:"true"`
+	// Send defines the payload sent to the server once connected.
+	// If empty, but Expect is set, nothing is sent.
+	Send string `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty"`
+	// Expect defines the payload the server response must start with.
+	// If both Send and Expect are empty, the health check only dials the server.
+	Expect string `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty"`
+	// LocalAddress defines the local address the health check connections originate from, instead of the one of the servers transport.
+	// It is an IP address assigned to an interface of the host, optionally with a port, e.g. 10.0.0.1 or 10.0.0.1:4000.
+	LocalAddress string `json:"localAddress,omitempty" toml:"localAddress,omitempty" yaml:"localAddress,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCP HealthCheck.
+func (h *TCPServerHealthCheck) SetDefaults() {
+	h.Interval = DefaultHealthCheckInterval
+	h.Timeout = DefaultHealthCheckTimeout
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPSticky holds the TCP sticky configuration.
+type TCPSticky struct {
+	// ClientCertificate defines whether the connections presenting the same verified client certificate are forwarded to the same server.
+	ClientCertificate bool `json:"clientCertificate,omitempty" toml:"clientCertificate,omitempty" yaml:"clientCertificate,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // ProxyProtocol holds the PROXY Protocol configuration.
 // More info: https://doc.traefik.io/traefik/v3.0/routing/services/#proxy-protocol
 type ProxyProtocol struct {
@@ -142,6 +260,7 @@ func (p *ProxyProtocol) SetDefaults() {
 type TCPServersTransport struct {
 	DialKeepAlive ptypes.Duration `description:"Defines the interval between keep-alive probes for an active network connection. If zero, keep-alive probes are sent with a default value (currently 15 seconds), if supported by the protocol and operating system. Network protocols or operating systems that do not support keep-alives ignore this field. If negative, keep-alive probes are disabled" json:"dialKeepAlive,omitempty" toml:"dialKeepAlive,omitempty" yaml:"dialKeepAlive,omitempty" export:"true"`
 	DialTimeout   ptypes.Duration `description:"Defines the amount of time to wait until a connection to a backend server can be established. If zero, no timeout exists." json:"dialTimeout,omitempty" toml:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" export:"true"`
+	LocalAddress  string          `description:"Defines the local IP address the connections to the backend servers originate from. It must be assigned to an interface of the host." json:"localAddress,omitempty" toml:"localAddress,omitempty" yaml:"localAddress,omitempty" export:"true"`
 	// TerminationDelay, corresponds to the deadline that the proxy sets, after one
 	// of its connected peers indicates it has closed the writing capability of its
 	// connection, to close the reading capability as well, hence fully terminating the
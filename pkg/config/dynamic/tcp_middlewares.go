@@ -1,5 +1,9 @@
 package dynamic
 
+import (
+	ptypes "github.com/traefik/paerser/types"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPMiddleware holds the TCPMiddleware configuration.
@@ -8,6 +12,7 @@ type TCPMiddleware struct {
 	// Deprecated: please use IPAllowList instead.
 	IPWhiteList *TCPIPWhiteList `json:"ipWhiteList,omitempty" toml:"ipWhiteList,omitempty" yaml:"ipWhiteList,omitempty" export:"true"`
 	IPAllowList *TCPIPAllowList `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	ConnAuth    *TCPConnAuth    `json:"connAuth,omitempty" toml:"connAuth,omitempty" yaml:"connAuth,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -20,6 +25,18 @@ type TCPInFlightConn struct {
 	// Amount defines the maximum amount of allowed simultaneous connections.
 	// The middleware closes the connection if there are already amount connections opened.
 	Amount int64 `json:"amount,omitempty" toml:"amount,omitempty" yaml:"amount,omitempty" export:"true"`
+	// TotalAmount defines the maximum amount of allowed simultaneous connections, across all the clients.
+	// Once it is reached, the connections are queued until a connection terminates,
+	// the freed connections being granted to the clients with queued connections in turn,
+	// so that a single client cannot starve the others. The queued connections count in the amount of their client.
+	TotalAmount int64 `json:"totalAmount,omitempty" toml:"totalAmount,omitempty" yaml:"totalAmount,omitempty" export:"true"`
+	// QueueTimeout defines how long a connection is queued, when the total amount is reached, before being closed.
+	// When zero, the connections are closed right away.
+	// +kubebuilder:validation:XIntOrString
+	QueueTimeout ptypes.Duration `json:"queueTimeout,omitempty" toml:"queueTimeout,omitempty" yaml:"queueTimeout,omitempty" export:"true"`
+	// ByClientCert defines whether the connections are grouped by the subject of the client certificate,
+	// instead of by IP, for the TLS terminated connections presenting one.
+	ByClientCert bool `json:"byClientCert,omitempty" toml:"byClientCert,omitempty" yaml:"byClientCert,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -40,3 +57,25 @@ type TCPIPAllowList struct {
 	// SourceRange defines the allowed IPs (or ranges of allowed IPs by using CIDR notation).
 	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPConnAuth holds the TCP ConnAuth middleware configuration.
+// This middleware delegates the authorization of the connections to a webhook,
+// consulted when they are established, before they are proxied.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/connauth/
+type TCPConnAuth struct {
+	// Address defines the URL of the webhook, which is sent a POST request with the source IP, the SNI and the router of each connection.
+	// The connection is allowed when the webhook answers with a 2XX status code, and denied with a 401 or 403 one.
+	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	// Timeout defines the maximum duration of the webhook requests, the webhook being considered unavailable beyond.
+	// +kubebuilder:validation:XIntOrString
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+	// FailOpen defines whether the connections are allowed when the webhook is unavailable, or answers with another status code.
+	// By default, they are denied.
+	FailOpen bool `json:"failOpen,omitempty" toml:"failOpen,omitempty" yaml:"failOpen,omitempty" export:"true"`
+	// CacheDuration defines how long the decisions of the webhook are cached, by source IP, SNI and router.
+	// A negative duration disables the cache.
+	// +kubebuilder:validation:XIntOrString
+	CacheDuration ptypes.Duration `json:"cacheDuration,omitempty" toml:"cacheDuration,omitempty" yaml:"cacheDuration,omitempty" export:"true"`
+}
@@ -1581,6 +1581,22 @@ func (in *TCPConfiguration) DeepCopy() *TCPConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPConnAuth) DeepCopyInto(out *TCPConnAuth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPConnAuth.
+func (in *TCPConnAuth) DeepCopy() *TCPConnAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPConnAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPIPAllowList) DeepCopyInto(out *TCPIPAllowList) {
 	*out = *in
@@ -1657,6 +1673,11 @@ func (in *TCPMiddleware) DeepCopyInto(out *TCPMiddleware) {
 		*out = new(TCPIPAllowList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConnAuth != nil {
+		in, out := &in.ConnAuth, &out.ConnAuth
+		*out = new(TCPConnAuth)
+		**out = **in
+	}
 	return
 }
 
@@ -1670,6 +1691,22 @@ func (in *TCPMiddleware) DeepCopy() *TCPMiddleware {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPMirroring) DeepCopyInto(out *TCPMirroring) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPMirroring.
+func (in *TCPMirroring) DeepCopy() *TCPMirroring {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPMirroring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPModel) DeepCopyInto(out *TCPModel) {
 	*out = *in
@@ -1733,6 +1770,22 @@ func (in *TCPServer) DeepCopy() *TCPServer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPServerHealthCheck) DeepCopyInto(out *TCPServerHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPServerHealthCheck.
+func (in *TCPServerHealthCheck) DeepCopy() *TCPServerHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPServerHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 	*out = *in
@@ -1746,6 +1799,28 @@ func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 		*out = make([]TCPServer, len(*in))
 		copy(*out, *in)
 	}
+	if in.Sticky != nil {
+		in, out := &in.Sticky, &out.Sticky
+		*out = new(TCPSticky)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(TCPServerHealthCheck)
+		**out = **in
+	}
+	if in.NoDelay != nil {
+		in, out := &in.NoDelay, &out.NoDelay
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CloseReasonFrames != nil {
+		in, out := &in.CloseReasonFrames, &out.CloseReasonFrames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.TerminationDelay != nil {
 		in, out := &in.TerminationDelay, &out.TerminationDelay
 		*out = new(int)
@@ -1798,6 +1873,11 @@ func (in *TCPService) DeepCopyInto(out *TCPService) {
 		*out = new(TCPWeightedRoundRobin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(TCPMirroring)
+		**out = **in
+	}
 	return
 }
 
@@ -1811,6 +1891,22 @@ func (in *TCPService) DeepCopy() *TCPService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPSticky) DeepCopyInto(out *TCPSticky) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSticky.
+func (in *TCPSticky) DeepCopy() *TCPSticky {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPSticky)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPWRRService) DeepCopyInto(out *TCPWRRService) {
 	*out = *in
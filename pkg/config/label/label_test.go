@@ -192,6 +192,9 @@ func TestDecodeConfiguration(t *testing.T) {
 
 		"traefik.tcp.middlewares.Middleware0.ipallowlist.sourcerange":      "foobar, fiibar",
 		"traefik.tcp.middlewares.Middleware2.inflightconn.amount":          "42",
+		"traefik.tcp.middlewares.Middleware2.inflightconn.totalamount":     "42",
+		"traefik.tcp.middlewares.Middleware2.inflightconn.queuetimeout":    "1s",
+		"traefik.tcp.middlewares.Middleware2.inflightconn.byclientcert":    "true",
 		"traefik.tcp.routers.Router0.rule":                                 "foobar",
 		"traefik.tcp.routers.Router0.priority":                             "42",
 		"traefik.tcp.routers.Router0.entrypoints":                          "foobar, fiibar",
@@ -266,7 +269,10 @@ func TestDecodeConfiguration(t *testing.T) {
 				},
 				"Middleware2": {
 					InFlightConn: &dynamic.TCPInFlightConn{
-						Amount: 42,
+						Amount:       42,
+						TotalAmount:  42,
+						QueueTimeout: ptypes.Duration(time.Second),
+						ByClientCert: true,
 					},
 				},
 			},
@@ -784,7 +790,10 @@ func TestEncodeConfiguration(t *testing.T) {
 				},
 				"Middleware2": {
 					InFlightConn: &dynamic.TCPInFlightConn{
-						Amount: 42,
+						Amount:       42,
+						TotalAmount:  42,
+						QueueTimeout: ptypes.Duration(time.Second),
+						ByClientCert: true,
 					},
 				},
 			},
@@ -1424,28 +1433,49 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
 		"traefik.HTTP.Services.Service1.LoadBalancer.ServersTransport":                 "foobar",
 
-		"traefik.TCP.Middlewares.Middleware0.IPAllowList.SourceRange": "foobar, fiibar",
-		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":     "42",
-		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router0.Priority":                        "42",
-		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                         "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                     "foo",
-		"traefik.TCP.Routers.Router1.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router1.Priority":                        "42",
-		"traefik.TCP.Routers.Router1.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                         "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                     "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.TLS":       "false",
-		"traefik.TCP.Services.Service0.LoadBalancer.ServersTransport": "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay": "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.TLS":       "false",
-		"traefik.TCP.Services.Service1.LoadBalancer.ServersTransport": "foo",
-		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay": "42",
+		"traefik.TCP.Middlewares.Middleware0.IPAllowList.SourceRange":        "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":            "42",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.TotalAmount":       "42",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.QueueTimeout":      "1000000000",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.ByClientCert":      "true",
+		"traefik.TCP.Routers.Router0.Rule":                                   "foobar",
+		"traefik.TCP.Routers.Router0.Priority":                               "42",
+		"traefik.TCP.Routers.Router0.EntryPoints":                            "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                                "foobar",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                        "false",
+		"traefik.TCP.Routers.Router0.TLS.CloseOnCertificateChange":           "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                            "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                   "foobar",
+		"traefik.TCP.Routers.Router1.Priority":                               "42",
+		"traefik.TCP.Routers.Router1.EntryPoints":                            "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                                "foobar",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                        "false",
+		"traefik.TCP.Routers.Router1.TLS.CloseOnCertificateChange":           "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                            "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":             "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.TLS":              "false",
+		"traefik.TCP.Services.Service0.LoadBalancer.Audit":                   "false",
+		"traefik.TCP.Services.Service0.LoadBalancer.ConnectTimeout":          "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.HalfClose":               "false",
+		"traefik.TCP.Services.Service0.LoadBalancer.IdleTimeout":             "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.MaxConnectionDuration":   "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.MaxConnectionsPerServer": "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.PerAttemptDialTimeout":   "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.Priority":                "0",
+		"traefik.TCP.Services.Service0.LoadBalancer.ServersTransport":        "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.TerminationDelay":        "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":             "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.TLS":              "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.Audit":                   "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.ConnectTimeout":          "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.HalfClose":               "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.IdleTimeout":             "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.MaxConnectionDuration":   "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.MaxConnectionsPerServer": "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.PerAttemptDialTimeout":   "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.Priority":                "0",
+		"traefik.TCP.Services.Service1.LoadBalancer.ServersTransport":        "foo",
+		"traefik.TCP.Services.Service1.LoadBalancer.TerminationDelay":        "42",
 
 		"traefik.TLS.Stores.default.DefaultGeneratedCert.Resolver":    "foobar",
 		"traefik.TLS.Stores.default.DefaultGeneratedCert.Domain.Main": "foobar",
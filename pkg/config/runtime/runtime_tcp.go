@@ -55,6 +55,19 @@ type TCPRouterInfo struct {
 	// It is the caller's responsibility to set the initial status.
 	Status string   `json:"status,omitempty"`
 	Using  []string `json:"using,omitempty"` // Effective entry points used by that router.
+	// ResolvedTLS holds the TLS options the router resolved to, when it terminates TLS.
+	// It is exposed by the debug API.
+	ResolvedTLS *TCPRouterTLSInfo `json:"-"`
+}
+
+// TCPRouterTLSInfo holds the effective TLS options of a TCP router terminating TLS.
+// The unset versions and cipher suites are the defaults of crypto/tls.
+type TCPRouterTLSInfo struct {
+	Options        string   `json:"options"` // Qualified name of the TLS options.
+	MinVersion     string   `json:"minVersion,omitempty"`
+	MaxVersion     string   `json:"maxVersion,omitempty"`
+	CipherSuites   []string `json:"cipherSuites,omitempty"`
+	ClientAuthType string   `json:"clientAuthType"`
 }
 
 // AddError adds err to r.Err, if it does not already exist.
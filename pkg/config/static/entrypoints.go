@@ -124,10 +124,17 @@ type EntryPoints map[string]*EntryPoint
 
 // EntryPointsTransport configures communication between clients and Traefik.
 type EntryPointsTransport struct {
-	LifeCycle            *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
-	RespondingTimeouts   *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
-	KeepAliveMaxTime     ptypes.Duration     `description:"Maximum duration before closing a keep-alive connection." json:"keepAliveMaxTime,omitempty" toml:"keepAliveMaxTime,omitempty" yaml:"keepAliveMaxTime,omitempty" export:"true"`
-	KeepAliveMaxRequests int                 `description:"Maximum number of requests before closing a keep-alive connection." json:"keepAliveMaxRequests,omitempty" toml:"keepAliveMaxRequests,omitempty" yaml:"keepAliveMaxRequests,omitempty" export:"true"`
+	LifeCycle              *LifeCycle          `description:"Timeouts influencing the server life cycle." json:"lifeCycle,omitempty" toml:"lifeCycle,omitempty" yaml:"lifeCycle,omitempty" export:"true"`
+	RespondingTimeouts     *RespondingTimeouts `description:"Timeouts for incoming requests to the Traefik instance." json:"respondingTimeouts,omitempty" toml:"respondingTimeouts,omitempty" yaml:"respondingTimeouts,omitempty" export:"true"`
+	KeepAliveMaxTime       ptypes.Duration     `description:"Maximum duration before closing a keep-alive connection." json:"keepAliveMaxTime,omitempty" toml:"keepAliveMaxTime,omitempty" yaml:"keepAliveMaxTime,omitempty" export:"true"`
+	KeepAliveMaxRequests   int                 `description:"Maximum number of requests before closing a keep-alive connection." json:"keepAliveMaxRequests,omitempty" toml:"keepAliveMaxRequests,omitempty" yaml:"keepAliveMaxRequests,omitempty" export:"true"`
+	TLSHandshakes          *TLSHandshakes      `description:"Limits the concurrent TLS handshakes of the TCP routers terminating TLS." json:"tlsHandshakes,omitempty" toml:"tlsHandshakes,omitempty" yaml:"tlsHandshakes,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	MaxSNILength           int                 `description:"Maximum length of the SNI of the TLS ClientHellos, the connections exceeding it are closed, zero means no limit." json:"maxSNILength,omitempty" toml:"maxSNILength,omitempty" yaml:"maxSNILength,omitempty" export:"true"`
+	SNICacheSize           int                 `description:"Maximum number of SNIs whose routing decision is cached, when the TCP TLS routers only match on the SNI, zero disables the cache." json:"sniCacheSize,omitempty" toml:"sniCacheSize,omitempty" yaml:"sniCacheSize,omitempty" export:"true"`
+	MaxClientHelloSize     int                 `description:"Maximum size in bytes of the TLS records read to get the ClientHello of the connections before routing them, when it is fragmented across several records." json:"maxClientHelloSize,omitempty" toml:"maxClientHelloSize,omitempty" yaml:"maxClientHelloSize,omitempty" export:"true"`
+	SignaturePeekTimeout   ptypes.Duration     `description:"Maximum duration spent peeking the first bytes of the non-TLS connections, when TCP routers with a Signature rule may match them." json:"signaturePeekTimeout,omitempty" toml:"signaturePeekTimeout,omitempty" yaml:"signaturePeekTimeout,omitempty" export:"true"`
+	RoutingSummaryInterval ptypes.Duration     `description:"Interval of the logged summaries of the connections routed by the TCP routers, zero disables them." json:"routingSummaryInterval,omitempty" toml:"routingSummaryInterval,omitempty" yaml:"routingSummaryInterval,omitempty" export:"true"`
+	RemovedRouters         *RemovedRouters     `description:"Closes the connections of the TCP routers removed from the configuration." json:"removedRouters,omitempty" toml:"removedRouters,omitempty" yaml:"removedRouters,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values.
@@ -136,6 +143,40 @@ func (t *EntryPointsTransport) SetDefaults() {
 	t.LifeCycle.SetDefaults()
 	t.RespondingTimeouts = &RespondingTimeouts{}
 	t.RespondingTimeouts.SetDefaults()
+	t.MaxSNILength = DefaultMaxSNILength
+	t.SNICacheSize = DefaultSNICacheSize
+	t.MaxClientHelloSize = DefaultMaxClientHelloSize
+	t.SignaturePeekTimeout = ptypes.Duration(DefaultSignaturePeekTimeout)
+}
+
+// TLSHandshakes configures the limit of concurrent TLS handshakes of an entry point.
+type TLSHandshakes struct {
+	MaxConcurrent int             `description:"Maximum number of concurrent TLS handshakes." json:"maxConcurrent,omitempty" toml:"maxConcurrent,omitempty" yaml:"maxConcurrent,omitempty" export:"true"`
+	QueueTimeout  ptypes.Duration `description:"Maximum duration a TLS handshake waits for the others to complete when the limit is reached." json:"queueTimeout,omitempty" toml:"queueTimeout,omitempty" yaml:"queueTimeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (t *TLSHandshakes) SetDefaults() {
+	t.MaxConcurrent = DefaultTLSHandshakesMaxConcurrent
+	t.QueueTimeout = ptypes.Duration(DefaultTLSHandshakesQueueTimeout)
+}
+
+// Close modes of the connections of the removed TCP routers.
+const (
+	// RemovedRoutersGracefulClose closes the connections with a FIN, once their pending data is sent.
+	RemovedRoutersGracefulClose = "graceful"
+	// RemovedRoutersReset aborts the connections with a RST, discarding their pending data.
+	RemovedRoutersReset = "reset"
+)
+
+// RemovedRouters configures the closing of the connections of the TCP routers removed from the configuration.
+type RemovedRouters struct {
+	CloseMode string `description:"How the connections are closed: graceful, with a FIN once their pending data is sent, or reset, with a RST." json:"closeMode,omitempty" toml:"closeMode,omitempty" yaml:"closeMode,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *RemovedRouters) SetDefaults() {
+	r.CloseMode = RemovedRoutersGracefulClose
 }
 
 // UDPConfig is the UDP configuration of an entry point.
@@ -51,6 +51,30 @@ const (
 	// DefaultUDPTimeout defines how long to wait by default on an idle session,
 	// before releasing all resources related to that session.
 	DefaultUDPTimeout = 3 * time.Second
+
+	// DefaultTLSHandshakesMaxConcurrent defines the default maximum number of concurrent TLS handshakes of an entry point,
+	// when the limit is enabled.
+	DefaultTLSHandshakesMaxConcurrent = 100
+
+	// DefaultTLSHandshakesQueueTimeout defines how long a TLS handshake waits by default for the others to complete,
+	// when the concurrent TLS handshakes limit is reached.
+	DefaultTLSHandshakesQueueTimeout = 5 * time.Second
+
+	// DefaultMaxSNILength defines the default maximum length of the SNI of the TLS ClientHellos,
+	// which is the maximum length of a domain name (RFC 1035).
+	DefaultMaxSNILength = 255
+
+	// DefaultSNICacheSize defines the default maximum number of SNIs whose TCP TLS routing decision is cached by an entry point,
+	// bounding the memory of the cache when the clients send many distinct SNIs.
+	DefaultSNICacheSize = 1000
+
+	// DefaultMaxClientHelloSize defines the default maximum size in bytes of the TLS records read to get the ClientHello of a connection,
+	// which is well above the size of the ClientHellos of the usual clients, even when fragmented.
+	DefaultMaxClientHelloSize = 64 * 1024
+
+	// DefaultSignaturePeekTimeout defines how long the first bytes of a non-TLS connection are peeked by default,
+	// when TCP routers with a Signature rule may match it.
+	DefaultSignaturePeekTimeout = time.Second
 )
 
 // Configuration is the static configuration.
@@ -59,6 +83,8 @@ type Configuration struct {
 
 	ServersTransport    *ServersTransport    `description:"Servers default transport." json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
 	TCPServersTransport *TCPServersTransport `description:"TCP servers default transport." json:"tcpServersTransport,omitempty" toml:"tcpServersTransport,omitempty" yaml:"tcpServersTransport,omitempty" export:"true"`
+	TCPAdmission        *TCPAdmission        `description:"Admission of the TCP connections by service priority under connection pressure." json:"tcpAdmission,omitempty" toml:"tcpAdmission,omitempty" yaml:"tcpAdmission,omitempty" export:"true"`
+	TCPFlowExport       *TCPFlowExport       `description:"Export of the flow records of the TCP connections to an IPFIX collector." json:"tcpFlowExport,omitempty" toml:"tcpFlowExport,omitempty" yaml:"tcpFlowExport,omitempty" export:"true"`
 	EntryPoints         EntryPoints          `description:"Entry points definition." json:"entryPoints,omitempty" toml:"entryPoints,omitempty" yaml:"entryPoints,omitempty" export:"true"`
 	Providers           *Providers           `description:"Providers configuration." json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty" export:"true"`
 
@@ -136,6 +162,35 @@ type TCPServersTransport struct {
 	TLS              *TLSClientConfig `description:"Defines the TLS configuration." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
+// TCPAdmission configures the admission of the connections to the TCP services, across the entry points,
+// up to a maximum number of connections.
+// Above the pressure threshold, the connections of the lowest priority services are rejected first.
+type TCPAdmission struct {
+	MaxConnections    int     `description:"Maximum number of connections to the TCP services, across the entry points." json:"maxConnections,omitempty" toml:"maxConnections,omitempty" yaml:"maxConnections,omitempty" export:"true"`
+	PressureThreshold float64 `description:"Ratio of the maximum number of connections above which the connections are admitted by priority of their TCP service, the lowest priorities being rejected first." json:"pressureThreshold,omitempty" toml:"pressureThreshold,omitempty" yaml:"pressureThreshold,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (a *TCPAdmission) SetDefaults() {
+	a.PressureThreshold = 0.8
+}
+
+// TCPFlowExport configures the export of the flow records of the connections forwarded to the TCP services,
+// in the IPFIX format, to a collector over UDP.
+type TCPFlowExport struct {
+	Address          string          `description:"UDP address of the IPFIX collector." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Template         []string        `description:"Fields of the flow records (sourceAddress, sourcePort, destinationAddress, destinationPort, protocol, bytesIn, bytesOut, flowStart, flowEnd, router, sni), defaults to all the fields but router and sni." json:"template,omitempty" toml:"template,omitempty" yaml:"template,omitempty" export:"true"`
+	EnterpriseNumber uint32          `description:"Private Enterprise Number of the information elements of the router and sni fields, which are enterprise-specific." json:"enterpriseNumber,omitempty" toml:"enterpriseNumber,omitempty" yaml:"enterpriseNumber,omitempty" export:"true"`
+	BufferSize       int             `description:"Maximum number of flow records buffered until they are sent, the next ones being dropped." json:"bufferSize,omitempty" toml:"bufferSize,omitempty" yaml:"bufferSize,omitempty" export:"true"`
+	FlushInterval    ptypes.Duration `description:"Interval at which the buffered flow records are sent." json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (e *TCPFlowExport) SetDefaults() {
+	e.BufferSize = 1024
+	e.FlushInterval = ptypes.Duration(time.Second)
+}
+
 // TLSClientConfig options to configure TLS communication between Traefik and the servers.
 type TLSClientConfig struct {
 	InsecureSkipVerify bool                  `description:"Disables SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
@@ -350,6 +405,20 @@ func (c *Configuration) ValidateConfiguration() error {
 		}
 	}
 
+	if c.TCPAdmission != nil {
+		if c.TCPAdmission.MaxConnections < 1 {
+			return fmt.Errorf("invalid TCP admission max connections %d: must be at least 1", c.TCPAdmission.MaxConnections)
+		}
+
+		if c.TCPAdmission.PressureThreshold < 0 || c.TCPAdmission.PressureThreshold > 1 {
+			return fmt.Errorf("invalid TCP admission pressure threshold %v: must be between 0 and 1", c.TCPAdmission.PressureThreshold)
+		}
+	}
+
+	if c.TCPFlowExport != nil && c.TCPFlowExport.Address == "" {
+		return errors.New("TCP flow export: the address of the collector is required")
+	}
+
 	if c.Tracing != nil && c.Tracing.OTLP != nil {
 		if c.Tracing.OTLP.GRPC != nil && c.Tracing.OTLP.GRPC.TLS != nil && c.Tracing.OTLP.GRPC.Insecure {
 			return errors.New("tracing OTLP GRPC: TLS and Insecure options are mutually exclusive")
@@ -0,0 +1,155 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// Dialer dials the TCP servers to check.
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// TCPTarget is a TCP server to check.
+type TCPTarget struct {
+	Address string
+	Dialer  Dialer
+}
+
+// ServiceTCPHealthChecker checks the health of the servers of a TCP service.
+type ServiceTCPHealthChecker struct {
+	balancer StatusSetter
+
+	config   *dynamic.TCPServerHealthCheck
+	interval time.Duration
+	jitter   int
+	timeout  time.Duration
+
+	targets map[string]TCPTarget
+}
+
+// NewServiceTCPHealthChecker creates a new ServiceTCPHealthChecker.
+// The targets are indexed by the server names known by the balancer.
+func NewServiceTCPHealthChecker(ctx context.Context, config *dynamic.TCPServerHealthCheck, service StatusSetter, targets map[string]TCPTarget) *ServiceTCPHealthChecker {
+	logger := log.Ctx(ctx)
+
+	interval := time.Duration(config.Interval)
+	if interval <= 0 {
+		logger.Error().Msg("Health check interval smaller than zero")
+		interval = time.Duration(dynamic.DefaultHealthCheckInterval)
+	}
+
+	jitter := config.Jitter
+	if jitter < 0 || jitter >= 100 {
+		logger.Error().Msgf("Health check jitter %d%% not between 0 and 99%%, disabling it", jitter)
+		jitter = 0
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		logger.Error().Msg("Health check timeout smaller than zero")
+		timeout = time.Duration(dynamic.DefaultHealthCheckTimeout)
+	}
+
+	return &ServiceTCPHealthChecker{
+		balancer: service,
+		config:   config,
+		interval: interval,
+		jitter:   jitter,
+		timeout:  timeout,
+		targets:  targets,
+	}
+}
+
+// Launch runs the health checks until the context is canceled.
+func (shc *ServiceTCPHealthChecker) Launch(ctx context.Context) {
+	timer := time.NewTimer(shc.nextInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-timer.C:
+			timer.Reset(shc.nextInterval())
+
+			for proxyName, target := range shc.targets {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				up := true
+
+				if err := shc.executeHealthCheck(target); err != nil {
+					log.Ctx(ctx).Warn().
+						Str("targetAddress", target.Address).
+						Err(err).
+						Msg("Health check failed.")
+
+					up = false
+				}
+
+				shc.balancer.SetStatus(ctx, proxyName, up)
+			}
+		}
+	}
+}
+
+// nextInterval returns the duration until the next health checks,
+// which is the interval randomly deviated by up to the jitter percentage.
+func (shc *ServiceTCPHealthChecker) nextInterval() time.Duration {
+	if shc.jitter == 0 {
+		return shc.interval
+	}
+
+	deviation := (2*rand.Float64() - 1) * float64(shc.jitter) / 100
+	return time.Duration(float64(shc.interval) * (1 + deviation))
+}
+
+// executeHealthCheck returns an error with a meaningful description if the health check failed.
+// Without Send and Expect payloads, a successful dial is enough to consider the server healthy.
+func (shc *ServiceTCPHealthChecker) executeHealthCheck(target TCPTarget) error {
+	conn, err := target.Dialer.Dial("tcp", target.Address)
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetDeadline(time.Now().Add(shc.timeout)); err != nil {
+		return fmt.Errorf("setting deadline: %w", err)
+	}
+
+	if shc.config.Send != "" {
+		if _, err := conn.Write([]byte(shc.config.Send)); err != nil {
+			return fmt.Errorf("sending payload: %w", err)
+		}
+	}
+
+	if shc.config.Expect == "" {
+		return nil
+	}
+
+	resp := make([]byte, len(shc.config.Expect))
+	n, err := io.ReadFull(conn, resp)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading response: %w", err)
+	}
+
+	if !bytes.Equal(resp[:n], []byte(shc.config.Expect)) {
+		return fmt.Errorf("received unexpected response %q, expected %q", resp[:n], shc.config.Expect)
+	}
+
+	return nil
+}
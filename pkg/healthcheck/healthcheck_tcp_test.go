@@ -0,0 +1,248 @@
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// startRedisLikeServer starts a TCP server replying to each received line with the given response.
+func startRedisLikeServer(t *testing.T, response string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+					return
+				}
+				_, _ = conn.Write([]byte(response))
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestServiceTCPHealthChecker_executeHealthCheck(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		response    string
+		send        string
+		expect      string
+		expectError bool
+	}{
+		{
+			desc:     "dial only",
+			response: "+PONG\r\n",
+		},
+		{
+			desc:     "send and expected response",
+			response: "+PONG\r\n",
+			send:     "PING\r\n",
+			expect:   "+PONG",
+		},
+		{
+			desc:        "send and unexpected response",
+			response:    "-ERR loading\r\n",
+			send:        "PING\r\n",
+			expect:      "+PONG",
+			expectError: true,
+		},
+		{
+			desc:        "send and truncated response",
+			response:    "+PO",
+			send:        "PING\r\n",
+			expect:      "+PONG",
+			expectError: true,
+		},
+		{
+			desc:        "send without response",
+			send:        "PING\r\n",
+			expect:      "+PONG",
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			address := startRedisLikeServer(t, test.response)
+
+			config := &dynamic.TCPServerHealthCheck{
+				Interval: ptypes.Duration(time.Second),
+				Timeout:  ptypes.Duration(time.Second),
+				Send:     test.send,
+				Expect:   test.expect,
+			}
+			hc := NewServiceTCPHealthChecker(context.Background(), config, nil, nil)
+
+			err := hc.executeHealthCheck(TCPTarget{Address: address, Dialer: &net.Dialer{}})
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceTCPHealthChecker_Launch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	config := &dynamic.TCPServerHealthCheck{
+		Interval: ptypes.Duration(50 * time.Millisecond),
+		Timeout:  ptypes.Duration(time.Second),
+		Send:     "PING\r\n",
+		Expect:   "+PONG",
+	}
+
+	var statusesMu sync.RWMutex
+	targets := map[string]TCPTarget{
+		"healthy": {Address: startRedisLikeServer(t, "+PONG\r\n"), Dialer: &net.Dialer{}},
+		"sick":    {Address: startRedisLikeServer(t, "-ERR\r\n"), Dialer: &net.Dialer{}},
+	}
+
+	statuses := make(map[string]bool)
+	setter := statusSetterFunc(func(childName string, up bool) {
+		statusesMu.Lock()
+		defer statusesMu.Unlock()
+
+		statuses[childName] = up
+	})
+
+	hc := NewServiceTCPHealthChecker(ctx, config, setter, targets)
+	go hc.Launch(ctx)
+
+	assert.Eventually(t, func() bool {
+		statusesMu.RLock()
+		defer statusesMu.RUnlock()
+
+		return len(statuses) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	statusesMu.RLock()
+	defer statusesMu.RUnlock()
+
+	assert.Equal(t, map[string]bool{"healthy": true, "sick": false}, statuses)
+}
+
+func TestServiceTCPHealthChecker_nextInterval(t *testing.T) {
+	config := &dynamic.TCPServerHealthCheck{
+		Interval: ptypes.Duration(10 * time.Second),
+		Timeout:  ptypes.Duration(time.Second),
+		Jitter:   20,
+	}
+	hc := NewServiceTCPHealthChecker(context.Background(), config, nil, nil)
+
+	intervals := make(map[time.Duration]struct{})
+	for range 100 {
+		interval := hc.nextInterval()
+		assert.GreaterOrEqual(t, interval, 8*time.Second)
+		assert.LessOrEqual(t, interval, 12*time.Second)
+
+		intervals[interval] = struct{}{}
+	}
+	assert.Greater(t, len(intervals), 1)
+
+	config.Jitter = 0
+	hc = NewServiceTCPHealthChecker(context.Background(), config, nil, nil)
+	assert.Equal(t, 10*time.Second, hc.nextInterval())
+
+	// An out of range jitter is disabled.
+	config.Jitter = 100
+	hc = NewServiceTCPHealthChecker(context.Background(), config, nil, nil)
+	assert.Equal(t, 10*time.Second, hc.nextInterval())
+}
+
+func TestServiceTCPHealthChecker_LaunchWithJitter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	config := &dynamic.TCPServerHealthCheck{
+		Interval: ptypes.Duration(200 * time.Millisecond),
+		Timeout:  ptypes.Duration(time.Second),
+		Jitter:   50,
+	}
+
+	var probesMu sync.Mutex
+	var probes []time.Time
+
+	// The services share the same interval, and are launched at the same time.
+	start := time.Now()
+	for range 5 {
+		var probed sync.Once
+		dialer := dialerFunc(func(string, string) (net.Conn, error) {
+			probed.Do(func() {
+				probesMu.Lock()
+				defer probesMu.Unlock()
+
+				probes = append(probes, time.Now())
+			})
+			return nil, errors.New("unreachable")
+		})
+
+		targets := map[string]TCPTarget{"server": {Address: "10.0.0.1:6379", Dialer: dialer}}
+		setter := statusSetterFunc(func(string, bool) {})
+
+		go NewServiceTCPHealthChecker(ctx, config, setter, targets).Launch(ctx)
+	}
+
+	assert.Eventually(t, func() bool {
+		probesMu.Lock()
+		defer probesMu.Unlock()
+
+		return len(probes) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	probesMu.Lock()
+	defer probesMu.Unlock()
+
+	// The first probes are spread over the jittered interval, instead of happening all at once.
+	first, last := probes[0], probes[0]
+	for _, probe := range probes {
+		assert.GreaterOrEqual(t, probe.Sub(start), 100*time.Millisecond)
+
+		if probe.Before(first) {
+			first = probe
+		}
+		if probe.After(last) {
+			last = probe
+		}
+	}
+	assert.Greater(t, last.Sub(first), 10*time.Millisecond)
+}
+
+type dialerFunc func(network, addr string) (net.Conn, error)
+
+func (f dialerFunc) Dial(network, addr string) (net.Conn, error) {
+	return f(network, addr)
+}
+
+type statusSetterFunc func(childName string, up bool)
+
+func (f statusSetterFunc) SetStatus(_ context.Context, childName string, up bool) {
+	f(childName, up)
+}
@@ -0,0 +1,127 @@
+package ip
+
+import (
+	"container/list"
+	"fmt"
+	"net"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+)
+
+// DefaultDecisionCacheSize is the default maximum number of IPs whose decision is cached by a DecisionCache.
+const DefaultDecisionCacheSize = 256
+
+// DecisionCache is a bounded LRU cache of the decisions of a Checker by IP,
+// sparing the matching of the IPs against the trusted IPs for the repeated connections of the same source.
+// As the decisions depend on the trusted IPs only, a cache lives as long as the Checker it is created for,
+// which is rebuilt, with an empty cache, on configuration change.
+type DecisionCache struct {
+	checker *Checker
+	maxSize int
+	hits    gokitmetrics.Counter
+	misses  gokitmetrics.Counter
+
+	mu sync.Mutex
+	// entries index the elements of the recency list by IP, the most recently used element being at the front.
+	entries map[string]*list.Element
+	recency *list.List
+}
+
+type decisionCacheEntry struct {
+	ip      string
+	allowed bool
+}
+
+// NewDecisionCache creates a DecisionCache of the decisions of the given Checker, caching up to maxSize IPs.
+// The given counter, when set, counts the lookups in the cache, labeled by result: hit or miss.
+func NewDecisionCache(checker *Checker, maxSize int, lookups gokitmetrics.Counter) *DecisionCache {
+	if lookups == nil {
+		lookups = discard.NewCounter()
+	}
+
+	return &DecisionCache{
+		checker: checker,
+		maxSize: maxSize,
+		hits:    lookups.With("result", "hit"),
+		misses:  lookups.With("result", "miss"),
+		entries: make(map[string]*list.Element),
+		recency: list.New(),
+	}
+}
+
+// IsAuthorized checks if provided request is authorized by the trusted IPs, as Checker.IsAuthorized does.
+func (c *DecisionCache) IsAuthorized(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ok, err := c.Contains(host)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("%q matched none of the trusted IPs", addr)
+	}
+
+	return nil
+}
+
+// Contains checks if provided address is in the trusted IPs, as Checker.Contains does.
+// The invalid addresses are not cached.
+func (c *DecisionCache) Contains(addr string) (bool, error) {
+	if allowed, ok := c.get(addr); ok {
+		return allowed, nil
+	}
+
+	allowed, err := c.checker.Contains(addr)
+	if err != nil {
+		return false, err
+	}
+
+	c.add(addr, allowed)
+
+	return allowed, nil
+}
+
+func (c *DecisionCache) get(addr string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[addr]
+	if !ok {
+		c.misses.Add(1)
+		return false, false
+	}
+
+	c.hits.Add(1)
+	c.recency.MoveToFront(element)
+
+	return element.Value.(*decisionCacheEntry).allowed, true
+}
+
+// add caches the decision for the given IP, evicting the least recently used one when the cache is full.
+func (c *DecisionCache) add(addr string, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The decision may have been cached concurrently, it is the same.
+	if _, ok := c.entries[addr]; ok {
+		return
+	}
+
+	if c.maxSize < 1 {
+		return
+	}
+
+	if c.recency.Len() >= c.maxSize {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*decisionCacheEntry).ip)
+	}
+
+	c.entries[addr] = c.recency.PushFront(&decisionCacheEntry{ip: addr, allowed: allowed})
+}
@@ -0,0 +1,104 @@
+package ip
+
+import (
+	"fmt"
+	"testing"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resultsCounter counts the added values by result.
+type resultsCounter struct {
+	results map[string]float64
+	result  string
+}
+
+func (c resultsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "result" {
+			c.result = labelValues[i+1]
+		}
+	}
+
+	return c
+}
+
+func (c resultsCounter) Add(delta float64) {
+	c.results[c.result] += delta
+}
+
+func TestDecisionCache(t *testing.T) {
+	checker, err := NewChecker([]string{"10.0.0.0/24"})
+	require.NoError(t, err)
+
+	lookups := resultsCounter{results: make(map[string]float64)}
+	cache := NewDecisionCache(checker, 2, lookups)
+
+	assert.NoError(t, cache.IsAuthorized("10.0.0.1:1234"))
+	assert.NoError(t, cache.IsAuthorized("10.0.0.1:5678"))
+	assert.EqualError(t, cache.IsAuthorized("10.0.1.1:1234"), `"10.0.1.1:1234" matched none of the trusted IPs`)
+	assert.EqualError(t, cache.IsAuthorized("10.0.1.1:5678"), `"10.0.1.1:5678" matched none of the trusted IPs`)
+
+	// The invalid addresses are not cached.
+	_, err = cache.Contains("invalid")
+	assert.Error(t, err)
+	assert.Len(t, cache.entries, 2)
+
+	// The cache is full, the least recently used IP is evicted.
+	allowed, err := cache.Contains("10.0.0.2")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "10.0.0.1")
+
+	allowed, err = cache.Contains("10.0.1.1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	assert.Equal(t, map[string]float64{"hit": 3, "miss": 4}, lookups.results)
+}
+
+func BenchmarkChecker_Contains(b *testing.B) {
+	checker := benchmarkChecker(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range b.N {
+		_, _ = checker.Contains(benchmarkSource(i))
+	}
+}
+
+func BenchmarkDecisionCache_Contains(b *testing.B) {
+	cache := NewDecisionCache(benchmarkChecker(b), DefaultDecisionCacheSize, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range b.N {
+		_, _ = cache.Contains(benchmarkSource(i))
+	}
+}
+
+// benchmarkChecker returns a Checker of 100 CIDRs, the last one matching the benchmark sources.
+func benchmarkChecker(b *testing.B) *Checker {
+	b.Helper()
+
+	var trustedIPs []string
+	for i := range 99 {
+		trustedIPs = append(trustedIPs, fmt.Sprintf("10.%d.0.0/16", i))
+	}
+	trustedIPs = append(trustedIPs, "192.168.0.0/24")
+
+	checker, err := NewChecker(trustedIPs)
+	require.NoError(b, err)
+
+	return checker
+}
+
+// benchmarkSource returns the source IP of the i-th connection, the connections being repeated from 64 sources.
+func benchmarkSource(i int) string {
+	return fmt.Sprintf("192.168.0.%d", i%64)
+}
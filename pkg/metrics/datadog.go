@@ -26,6 +26,22 @@ const (
 	ddOpenConnsName               = "open.connections"
 
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	ddTLSHandshakesInProgressName   = "tls.handshakes.inProgress"
+	ddTLSHandshakesQueuedName       = "tls.handshakes.queued"
+	ddTLSSNIRejectsName             = "tls.sni.rejects.total"
+	ddTLSSNICacheLookupsName        = "tls.sni.cache.lookups.total"
+
+	ddTCPRouterIdleReapedConnsName = "tcp.router.connections.idleReaped.total"
+	ddTCPRouterConcurrentConnsName = "tcp.router.connections.concurrent"
+
+	ddTCPInFlightClientsName           = "tcp.middleware.inflight.clients"
+	ddTCPInFlightQueueWaitDurationName = "tcp.middleware.inflight.queue.wait.duration"
+	ddTCPInFlightQueueTimeoutsName     = "tcp.middleware.inflight.queue.timeouts.total"
+
+	ddTCPAdmissionConnsName   = "tcp.admission.connections"
+	ddTCPAdmissionRejectsName = "tcp.admission.rejects.total"
+
+	ddTCPIPDecisionCacheLookupsName = "tcp.ip.decision.cache.lookups.total"
 
 	ddEntryPointReqsName        = "entrypoint.request.total"
 	ddEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -46,6 +62,9 @@ const (
 	ddServiceServerUpName     = "service.server.up"
 	ddServiceReqsBytesName    = "service.requests.bytes.total"
 	ddServiceRespsBytesName   = "service.responses.bytes.total"
+	ddServiceDialDurationName = "service.tcp.dial.duration"
+	ddServiceComparisonsName  = "service.tcp.mirror.comparisons.total"
+	ddServiceServerConnsName  = "service.tcp.server.connections"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -63,12 +82,23 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 	initDatadogClient(ctx, config)
 
 	registry := &standardRegistry{
-		configReloadsCounter:           datadogClient.NewCounter(ddConfigReloadsName, 1.0),
-		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
-		openConnectionsGauge:           datadogClient.NewGauge(ddOpenConnsName),
-		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		configReloadsCounter:             datadogClient.NewCounter(ddConfigReloadsName, 1.0),
+		lastConfigReloadSuccessGauge:     datadogClient.NewGauge(ddLastConfigReloadSuccessName),
+		openConnectionsGauge:             datadogClient.NewGauge(ddOpenConnsName),
+		tlsCertsNotAfterTimestampGauge:   datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		tlsHandshakesInProgressGauge:     datadogClient.NewGauge(ddTLSHandshakesInProgressName),
+		tlsHandshakesQueuedGauge:         datadogClient.NewGauge(ddTLSHandshakesQueuedName),
+		tlsSNIRejectsCounter:             datadogClient.NewCounter(ddTLSSNIRejectsName, 1.0),
+		tlsSNICacheLookupsCounter:        datadogClient.NewCounter(ddTLSSNICacheLookupsName, 1.0),
+		tcpInFlightClientsGauge:          datadogClient.NewGauge(ddTCPInFlightClientsName),
+		tcpInFlightQueueTimeoutsCounter:  datadogClient.NewCounter(ddTCPInFlightQueueTimeoutsName, 1.0),
+		tcpAdmissionConnsGauge:           datadogClient.NewGauge(ddTCPAdmissionConnsName),
+		tcpAdmissionRejectsCounter:       datadogClient.NewCounter(ddTCPAdmissionRejectsName, 1.0),
+		tcpIPDecisionCacheLookupsCounter: datadogClient.NewCounter(ddTCPIPDecisionCacheLookupsName, 1.0),
 	}
 
+	registry.tcpInFlightQueueWaitHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddTCPInFlightQueueWaitDurationName, 1.0), time.Second)
+
 	if config.AddEntryPointsLabels {
 		registry.epEnabled = config.AddEntryPointsLabels
 		registry.entryPointReqsCounter = NewCounterWithNoopHeaders(datadogClient.NewCounter(ddEntryPointReqsName, 1.0))
@@ -85,6 +115,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddRouterReqsDurationName, 1.0), time.Second)
 		registry.routerReqsBytesCounter = datadogClient.NewCounter(ddRouterReqsBytesName, 1.0)
 		registry.routerRespsBytesCounter = datadogClient.NewCounter(ddRouterRespsBytesName, 1.0)
+		registry.tcpRouterIdleReapedConnsCounter = datadogClient.NewCounter(ddTCPRouterIdleReapedConnsName, 1.0)
+		registry.tcpRouterConcurrencyHistogram = datadogClient.NewHistogram(ddTCPRouterConcurrentConnsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -96,6 +128,9 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServiceServerUpName)
 		registry.serviceReqsBytesCounter = datadogClient.NewCounter(ddServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = datadogClient.NewCounter(ddServiceRespsBytesName, 1.0)
+		registry.serviceTCPDialDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddServiceDialDurationName, 1.0), time.Second)
+		registry.serviceTCPComparisonsCounter = datadogClient.NewCounter(ddServiceComparisonsName, 1.0)
+		registry.serviceTCPServerConnsGauge = datadogClient.NewGauge(ddServiceServerConnsName)
 	}
 
 	return registry
@@ -29,6 +29,22 @@ const (
 	influxDBOpenConnsName               = "traefik.open.connections"
 
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
+	influxDBTLSHandshakesInProgressName   = "traefik.tls.handshakes.inProgress"
+	influxDBTLSHandshakesQueuedName       = "traefik.tls.handshakes.queued"
+	influxDBTLSSNIRejectsName             = "traefik.tls.sni.rejects.total"
+	influxDBTLSSNICacheLookupsName        = "traefik.tls.sni.cache.lookups.total"
+
+	influxDBTCPRouterIdleReapedConnsName = "traefik.tcp.router.connections.idleReaped.total"
+	influxDBTCPRouterConcurrentConnsName = "traefik.tcp.router.connections.concurrent"
+
+	influxDBTCPInFlightClientsName           = "traefik.tcp.middleware.inflight.clients"
+	influxDBTCPInFlightQueueWaitDurationName = "traefik.tcp.middleware.inflight.queue.wait.duration"
+	influxDBTCPInFlightQueueTimeoutsName     = "traefik.tcp.middleware.inflight.queue.timeouts.total"
+
+	influxDBTCPAdmissionConnsName   = "traefik.tcp.admission.connections"
+	influxDBTCPAdmissionRejectsName = "traefik.tcp.admission.rejects.total"
+
+	influxDBTCPIPDecisionCacheLookupsName = "traefik.tcp.ip.decision.cache.lookups.total"
 
 	influxDBEntryPointReqsName        = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqsTLSName     = "traefik.entrypoint.requests.tls.total"
@@ -49,6 +65,9 @@ const (
 	influxDBServiceServerUpName     = "traefik.service.server.up"
 	influxDBServiceReqsBytesName    = "traefik.service.requests.bytes.total"
 	influxDBServiceRespsBytesName   = "traefik.service.responses.bytes.total"
+	influxDBServiceDialDurationName = "traefik.service.tcp.dial.duration"
+	influxDBServiceComparisonsName  = "traefik.service.tcp.mirror.comparisons.total"
+	influxDBServiceServerConnsName  = "traefik.service.tcp.server.connections"
 )
 
 // RegisterInfluxDB2 creates metrics exporter for InfluxDB2.
@@ -79,12 +98,23 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           influxDB2Store.NewCounter(influxDBConfigReloadsName),
-		lastConfigReloadSuccessGauge:   influxDB2Store.NewGauge(influxDBLastConfigReloadSuccessName),
-		openConnectionsGauge:           influxDB2Store.NewGauge(influxDBOpenConnsName),
-		tlsCertsNotAfterTimestampGauge: influxDB2Store.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		configReloadsCounter:             influxDB2Store.NewCounter(influxDBConfigReloadsName),
+		lastConfigReloadSuccessGauge:     influxDB2Store.NewGauge(influxDBLastConfigReloadSuccessName),
+		openConnectionsGauge:             influxDB2Store.NewGauge(influxDBOpenConnsName),
+		tlsCertsNotAfterTimestampGauge:   influxDB2Store.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		tlsHandshakesInProgressGauge:     influxDB2Store.NewGauge(influxDBTLSHandshakesInProgressName),
+		tlsHandshakesQueuedGauge:         influxDB2Store.NewGauge(influxDBTLSHandshakesQueuedName),
+		tlsSNIRejectsCounter:             influxDB2Store.NewCounter(influxDBTLSSNIRejectsName),
+		tlsSNICacheLookupsCounter:        influxDB2Store.NewCounter(influxDBTLSSNICacheLookupsName),
+		tcpInFlightClientsGauge:          influxDB2Store.NewGauge(influxDBTCPInFlightClientsName),
+		tcpInFlightQueueTimeoutsCounter:  influxDB2Store.NewCounter(influxDBTCPInFlightQueueTimeoutsName),
+		tcpAdmissionConnsGauge:           influxDB2Store.NewGauge(influxDBTCPAdmissionConnsName),
+		tcpAdmissionRejectsCounter:       influxDB2Store.NewCounter(influxDBTCPAdmissionRejectsName),
+		tcpIPDecisionCacheLookupsCounter: influxDB2Store.NewCounter(influxDBTCPIPDecisionCacheLookupsName),
 	}
 
+	registry.tcpInFlightQueueWaitHistogram, _ = NewHistogramWithScale(influxDB2Store.NewHistogram(influxDBTCPInFlightQueueWaitDurationName), time.Second)
+
 	if config.AddEntryPointsLabels {
 		registry.epEnabled = config.AddEntryPointsLabels
 		registry.entryPointReqsCounter = NewCounterWithNoopHeaders(influxDB2Store.NewCounter(influxDBEntryPointReqsName))
@@ -101,6 +131,8 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(influxDB2Store.NewHistogram(influxDBRouterReqsDurationName), time.Second)
 		registry.routerReqsBytesCounter = influxDB2Store.NewCounter(influxDBRouterReqsBytesName)
 		registry.routerRespsBytesCounter = influxDB2Store.NewCounter(influxDBRouterRespsBytesName)
+		registry.tcpRouterIdleReapedConnsCounter = influxDB2Store.NewCounter(influxDBTCPRouterIdleReapedConnsName)
+		registry.tcpRouterConcurrencyHistogram = influxDB2Store.NewHistogram(influxDBTCPRouterConcurrentConnsName)
 	}
 
 	if config.AddServicesLabels {
@@ -112,6 +144,9 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 		registry.serviceServerUpGauge = influxDB2Store.NewGauge(influxDBServiceServerUpName)
 		registry.serviceReqsBytesCounter = influxDB2Store.NewCounter(influxDBServiceReqsBytesName)
 		registry.serviceRespsBytesCounter = influxDB2Store.NewCounter(influxDBServiceRespsBytesName)
+		registry.serviceTCPDialDurationHistogram, _ = NewHistogramWithScale(influxDB2Store.NewHistogram(influxDBServiceDialDurationName), time.Second)
+		registry.serviceTCPComparisonsCounter = influxDB2Store.NewCounter(influxDBServiceComparisonsName)
+		registry.serviceTCPServerConnsGauge = influxDB2Store.NewGauge(influxDBServiceServerConnsName)
 	}
 
 	return registry
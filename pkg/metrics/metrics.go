@@ -28,6 +28,30 @@ type Registry interface {
 	// TLS
 
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+	TLSHandshakesInProgressGauge() metrics.Gauge
+	TLSHandshakesQueuedGauge() metrics.Gauge
+	TLSSNIRejectsCounter() metrics.Counter
+	TLSSNICacheLookupsCounter() metrics.Counter
+
+	// TCP router metrics
+
+	TCPRouterIdleReapedConnsCounter() metrics.Counter
+	TCPRouterConcurrencyHistogram() metrics.Histogram
+
+	// TCP middleware metrics
+
+	TCPInFlightClientsGauge() metrics.Gauge
+	TCPInFlightQueueWaitHistogram() ScalableHistogram
+	TCPInFlightQueueTimeoutsCounter() metrics.Counter
+
+	// TCP admission metrics
+
+	TCPAdmissionConnsGauge() metrics.Gauge
+	TCPAdmissionRejectsCounter() metrics.Counter
+
+	// TCP IP decision cache metrics
+
+	TCPIPDecisionCacheLookupsCounter() metrics.Counter
 
 	// entry point metrics
 
@@ -54,6 +78,9 @@ type Registry interface {
 	ServiceServerUpGauge() metrics.Gauge
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
+	ServiceTCPDialDurationHistogram() ScalableHistogram
+	ServiceTCPComparisonsCounter() metrics.Counter
+	ServiceTCPServerConnsGauge() metrics.Gauge
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -70,6 +97,18 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var openConnectionsGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var tlsHandshakesInProgressGauge []metrics.Gauge
+	var tlsHandshakesQueuedGauge []metrics.Gauge
+	var tlsSNIRejectsCounter []metrics.Counter
+	var tlsSNICacheLookupsCounter []metrics.Counter
+	var tcpRouterIdleReapedConnsCounter []metrics.Counter
+	var tcpRouterConcurrencyHistogram []metrics.Histogram
+	var tcpInFlightClientsGauge []metrics.Gauge
+	var tcpInFlightQueueWaitHistogram []ScalableHistogram
+	var tcpInFlightQueueTimeoutsCounter []metrics.Counter
+	var tcpAdmissionConnsGauge []metrics.Gauge
+	var tcpAdmissionRejectsCounter []metrics.Counter
+	var tcpIPDecisionCacheLookupsCounter []metrics.Counter
 	var entryPointReqsCounter []CounterWithHeaders
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
@@ -87,6 +126,9 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceServerUpGauge []metrics.Gauge
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
+	var serviceTCPDialDurationHistogram []ScalableHistogram
+	var serviceTCPComparisonsCounter []metrics.Counter
+	var serviceTCPServerConnsGauge []metrics.Gauge
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -101,6 +143,42 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.TLSHandshakesInProgressGauge() != nil {
+			tlsHandshakesInProgressGauge = append(tlsHandshakesInProgressGauge, r.TLSHandshakesInProgressGauge())
+		}
+		if r.TLSHandshakesQueuedGauge() != nil {
+			tlsHandshakesQueuedGauge = append(tlsHandshakesQueuedGauge, r.TLSHandshakesQueuedGauge())
+		}
+		if r.TLSSNIRejectsCounter() != nil {
+			tlsSNIRejectsCounter = append(tlsSNIRejectsCounter, r.TLSSNIRejectsCounter())
+		}
+		if r.TLSSNICacheLookupsCounter() != nil {
+			tlsSNICacheLookupsCounter = append(tlsSNICacheLookupsCounter, r.TLSSNICacheLookupsCounter())
+		}
+		if r.TCPRouterIdleReapedConnsCounter() != nil {
+			tcpRouterIdleReapedConnsCounter = append(tcpRouterIdleReapedConnsCounter, r.TCPRouterIdleReapedConnsCounter())
+		}
+		if r.TCPRouterConcurrencyHistogram() != nil {
+			tcpRouterConcurrencyHistogram = append(tcpRouterConcurrencyHistogram, r.TCPRouterConcurrencyHistogram())
+		}
+		if r.TCPInFlightClientsGauge() != nil {
+			tcpInFlightClientsGauge = append(tcpInFlightClientsGauge, r.TCPInFlightClientsGauge())
+		}
+		if r.TCPInFlightQueueWaitHistogram() != nil {
+			tcpInFlightQueueWaitHistogram = append(tcpInFlightQueueWaitHistogram, r.TCPInFlightQueueWaitHistogram())
+		}
+		if r.TCPInFlightQueueTimeoutsCounter() != nil {
+			tcpInFlightQueueTimeoutsCounter = append(tcpInFlightQueueTimeoutsCounter, r.TCPInFlightQueueTimeoutsCounter())
+		}
+		if r.TCPAdmissionConnsGauge() != nil {
+			tcpAdmissionConnsGauge = append(tcpAdmissionConnsGauge, r.TCPAdmissionConnsGauge())
+		}
+		if r.TCPAdmissionRejectsCounter() != nil {
+			tcpAdmissionRejectsCounter = append(tcpAdmissionRejectsCounter, r.TCPAdmissionRejectsCounter())
+		}
+		if r.TCPIPDecisionCacheLookupsCounter() != nil {
+			tcpIPDecisionCacheLookupsCounter = append(tcpIPDecisionCacheLookupsCounter, r.TCPIPDecisionCacheLookupsCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -152,61 +230,100 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRespsBytesCounter() != nil {
 			serviceRespsBytesCounter = append(serviceRespsBytesCounter, r.ServiceRespsBytesCounter())
 		}
+		if r.ServiceTCPDialDurationHistogram() != nil {
+			serviceTCPDialDurationHistogram = append(serviceTCPDialDurationHistogram, r.ServiceTCPDialDurationHistogram())
+		}
+		if r.ServiceTCPComparisonsCounter() != nil {
+			serviceTCPComparisonsCounter = append(serviceTCPComparisonsCounter, r.ServiceTCPComparisonsCounter())
+		}
+		if r.ServiceTCPServerConnsGauge() != nil {
+			serviceTCPServerConnsGauge = append(serviceTCPServerConnsGauge, r.ServiceTCPServerConnsGauge())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		openConnectionsGauge:           multi.NewGauge(openConnectionsGauge...),
-		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
-		entryPointReqsCounter:          NewMultiCounterWithHeaders(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: MultiHistogram(entryPointReqDurationHistogram),
-		entryPointReqsBytesCounter:     multi.NewCounter(entryPointReqsBytesCounter...),
-		entryPointRespsBytesCounter:    multi.NewCounter(entryPointRespsBytesCounter...),
-		routerReqsCounter:              NewMultiCounterWithHeaders(routerReqsCounter...),
-		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
-		routerReqDurationHistogram:     MultiHistogram(routerReqDurationHistogram),
-		routerReqsBytesCounter:         multi.NewCounter(routerReqsBytesCounter...),
-		routerRespsBytesCounter:        multi.NewCounter(routerRespsBytesCounter...),
-		serviceReqsCounter:             NewMultiCounterWithHeaders(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    MultiHistogram(serviceReqDurationHistogram),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
-		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
-		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
+		epEnabled:                        len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0,
+		svcEnabled:                       len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		routerEnabled:                    len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0,
+		configReloadsCounter:             multi.NewCounter(configReloadsCounter...),
+		lastConfigReloadSuccessGauge:     multi.NewGauge(lastConfigReloadSuccessGauge...),
+		openConnectionsGauge:             multi.NewGauge(openConnectionsGauge...),
+		tlsCertsNotAfterTimestampGauge:   multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		tlsHandshakesInProgressGauge:     multi.NewGauge(tlsHandshakesInProgressGauge...),
+		tlsHandshakesQueuedGauge:         multi.NewGauge(tlsHandshakesQueuedGauge...),
+		tlsSNIRejectsCounter:             multi.NewCounter(tlsSNIRejectsCounter...),
+		tlsSNICacheLookupsCounter:        multi.NewCounter(tlsSNICacheLookupsCounter...),
+		tcpRouterIdleReapedConnsCounter:  multi.NewCounter(tcpRouterIdleReapedConnsCounter...),
+		tcpRouterConcurrencyHistogram:    multi.NewHistogram(tcpRouterConcurrencyHistogram...),
+		tcpInFlightClientsGauge:          multi.NewGauge(tcpInFlightClientsGauge...),
+		tcpInFlightQueueWaitHistogram:    MultiHistogram(tcpInFlightQueueWaitHistogram),
+		tcpInFlightQueueTimeoutsCounter:  multi.NewCounter(tcpInFlightQueueTimeoutsCounter...),
+		tcpAdmissionConnsGauge:           multi.NewGauge(tcpAdmissionConnsGauge...),
+		tcpAdmissionRejectsCounter:       multi.NewCounter(tcpAdmissionRejectsCounter...),
+		tcpIPDecisionCacheLookupsCounter: multi.NewCounter(tcpIPDecisionCacheLookupsCounter...),
+		entryPointReqsCounter:            NewMultiCounterWithHeaders(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:         multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:   MultiHistogram(entryPointReqDurationHistogram),
+		entryPointReqsBytesCounter:       multi.NewCounter(entryPointReqsBytesCounter...),
+		entryPointRespsBytesCounter:      multi.NewCounter(entryPointRespsBytesCounter...),
+		routerReqsCounter:                NewMultiCounterWithHeaders(routerReqsCounter...),
+		routerReqsTLSCounter:             multi.NewCounter(routerReqsTLSCounter...),
+		routerReqDurationHistogram:       MultiHistogram(routerReqDurationHistogram),
+		routerReqsBytesCounter:           multi.NewCounter(routerReqsBytesCounter...),
+		routerRespsBytesCounter:          multi.NewCounter(routerRespsBytesCounter...),
+		serviceReqsCounter:               NewMultiCounterWithHeaders(serviceReqsCounter...),
+		serviceReqsTLSCounter:            multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:      MultiHistogram(serviceReqDurationHistogram),
+		serviceRetriesCounter:            multi.NewCounter(serviceRetriesCounter...),
+		serviceServerUpGauge:             multi.NewGauge(serviceServerUpGauge...),
+		serviceReqsBytesCounter:          multi.NewCounter(serviceReqsBytesCounter...),
+		serviceRespsBytesCounter:         multi.NewCounter(serviceRespsBytesCounter...),
+		serviceTCPDialDurationHistogram:  MultiHistogram(serviceTCPDialDurationHistogram),
+		serviceTCPComparisonsCounter:     multi.NewCounter(serviceTCPComparisonsCounter...),
+		serviceTCPServerConnsGauge:       multi.NewGauge(serviceTCPServerConnsGauge...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	routerEnabled                  bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	openConnectionsGauge           metrics.Gauge
-	tlsCertsNotAfterTimestampGauge metrics.Gauge
-	entryPointReqsCounter          CounterWithHeaders
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointReqsBytesCounter     metrics.Counter
-	entryPointRespsBytesCounter    metrics.Counter
-	routerReqsCounter              CounterWithHeaders
-	routerReqsTLSCounter           metrics.Counter
-	routerReqDurationHistogram     ScalableHistogram
-	routerReqsBytesCounter         metrics.Counter
-	routerRespsBytesCounter        metrics.Counter
-	serviceReqsCounter             CounterWithHeaders
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
-	serviceReqsBytesCounter        metrics.Counter
-	serviceRespsBytesCounter       metrics.Counter
+	epEnabled                        bool
+	routerEnabled                    bool
+	svcEnabled                       bool
+	configReloadsCounter             metrics.Counter
+	lastConfigReloadSuccessGauge     metrics.Gauge
+	openConnectionsGauge             metrics.Gauge
+	tlsCertsNotAfterTimestampGauge   metrics.Gauge
+	tlsHandshakesInProgressGauge     metrics.Gauge
+	tlsHandshakesQueuedGauge         metrics.Gauge
+	tlsSNIRejectsCounter             metrics.Counter
+	tlsSNICacheLookupsCounter        metrics.Counter
+	tcpRouterIdleReapedConnsCounter  metrics.Counter
+	tcpRouterConcurrencyHistogram    metrics.Histogram
+	tcpInFlightClientsGauge          metrics.Gauge
+	tcpInFlightQueueWaitHistogram    ScalableHistogram
+	tcpInFlightQueueTimeoutsCounter  metrics.Counter
+	tcpAdmissionConnsGauge           metrics.Gauge
+	tcpAdmissionRejectsCounter       metrics.Counter
+	tcpIPDecisionCacheLookupsCounter metrics.Counter
+	entryPointReqsCounter            CounterWithHeaders
+	entryPointReqsTLSCounter         metrics.Counter
+	entryPointReqDurationHistogram   ScalableHistogram
+	entryPointReqsBytesCounter       metrics.Counter
+	entryPointRespsBytesCounter      metrics.Counter
+	routerReqsCounter                CounterWithHeaders
+	routerReqsTLSCounter             metrics.Counter
+	routerReqDurationHistogram       ScalableHistogram
+	routerReqsBytesCounter           metrics.Counter
+	routerRespsBytesCounter          metrics.Counter
+	serviceReqsCounter               CounterWithHeaders
+	serviceReqsTLSCounter            metrics.Counter
+	serviceReqDurationHistogram      ScalableHistogram
+	serviceRetriesCounter            metrics.Counter
+	serviceServerUpGauge             metrics.Gauge
+	serviceReqsBytesCounter          metrics.Counter
+	serviceRespsBytesCounter         metrics.Counter
+	serviceTCPDialDurationHistogram  ScalableHistogram
+	serviceTCPComparisonsCounter     metrics.Counter
+	serviceTCPServerConnsGauge       metrics.Gauge
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -237,6 +354,54 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) TLSHandshakesInProgressGauge() metrics.Gauge {
+	return r.tlsHandshakesInProgressGauge
+}
+
+func (r *standardRegistry) TLSHandshakesQueuedGauge() metrics.Gauge {
+	return r.tlsHandshakesQueuedGauge
+}
+
+func (r *standardRegistry) TLSSNIRejectsCounter() metrics.Counter {
+	return r.tlsSNIRejectsCounter
+}
+
+func (r *standardRegistry) TLSSNICacheLookupsCounter() metrics.Counter {
+	return r.tlsSNICacheLookupsCounter
+}
+
+func (r *standardRegistry) TCPRouterIdleReapedConnsCounter() metrics.Counter {
+	return r.tcpRouterIdleReapedConnsCounter
+}
+
+func (r *standardRegistry) TCPRouterConcurrencyHistogram() metrics.Histogram {
+	return r.tcpRouterConcurrencyHistogram
+}
+
+func (r *standardRegistry) TCPInFlightClientsGauge() metrics.Gauge {
+	return r.tcpInFlightClientsGauge
+}
+
+func (r *standardRegistry) TCPInFlightQueueWaitHistogram() ScalableHistogram {
+	return r.tcpInFlightQueueWaitHistogram
+}
+
+func (r *standardRegistry) TCPInFlightQueueTimeoutsCounter() metrics.Counter {
+	return r.tcpInFlightQueueTimeoutsCounter
+}
+
+func (r *standardRegistry) TCPAdmissionConnsGauge() metrics.Gauge {
+	return r.tcpAdmissionConnsGauge
+}
+
+func (r *standardRegistry) TCPAdmissionRejectsCounter() metrics.Counter {
+	return r.tcpAdmissionRejectsCounter
+}
+
+func (r *standardRegistry) TCPIPDecisionCacheLookupsCounter() metrics.Counter {
+	return r.tcpIPDecisionCacheLookupsCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() CounterWithHeaders {
 	return r.entryPointReqsCounter
 }
@@ -305,6 +470,18 @@ func (r *standardRegistry) ServiceRespsBytesCounter() metrics.Counter {
 	return r.serviceRespsBytesCounter
 }
 
+func (r *standardRegistry) ServiceTCPDialDurationHistogram() ScalableHistogram {
+	return r.serviceTCPDialDurationHistogram
+}
+
+func (r *standardRegistry) ServiceTCPComparisonsCounter() metrics.Counter {
+	return r.serviceTCPComparisonsCounter
+}
+
+func (r *standardRegistry) ServiceTCPServerConnsGauge() metrics.Gauge {
+	return r.serviceTCPServerConnsGauge
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
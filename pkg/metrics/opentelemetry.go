@@ -116,14 +116,27 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OTLP) Registry {
 		metric.WithInstrumentationVersion(version.Version))
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           newOTLPCounterFrom(meter, configReloadsTotalName, "Config reloads"),
-		lastConfigReloadSuccessGauge:   newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", "ms"),
-		openConnectionsGauge:           newOTLPGaugeFrom(meter, openConnectionsName, "How many open connections exist, by entryPoint and protocol", "1"),
-		tlsCertsNotAfterTimestampGauge: newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestampName, "Certificate expiration timestamp", "ms"),
-	}
+		epEnabled:                        config.AddEntryPointsLabels,
+		routerEnabled:                    config.AddRoutersLabels,
+		svcEnabled:                       config.AddServicesLabels,
+		configReloadsCounter:             newOTLPCounterFrom(meter, configReloadsTotalName, "Config reloads"),
+		lastConfigReloadSuccessGauge:     newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", "ms"),
+		openConnectionsGauge:             newOTLPGaugeFrom(meter, openConnectionsName, "How many open connections exist, by entryPoint and protocol", "1"),
+		tlsCertsNotAfterTimestampGauge:   newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestampName, "Certificate expiration timestamp", "ms"),
+		tlsHandshakesInProgressGauge:     newOTLPGaugeFrom(meter, tlsHandshakesInProgressName, "How many TLS handshakes of TCP routers are in progress, by entryPoint", "1"),
+		tlsHandshakesQueuedGauge:         newOTLPGaugeFrom(meter, tlsHandshakesQueuedName, "How many TLS handshakes of TCP routers are waiting for the handshakes concurrency limit, by entryPoint", "1"),
+		tlsSNIRejectsCounter:             newOTLPCounterFrom(meter, tlsSNIRejectsTotalName, "How many TLS connections were rejected for an SNI exceeding the maximum length, by entryPoint"),
+		tlsSNICacheLookupsCounter:        newOTLPCounterFrom(meter, tlsSNICacheLookupsTotalName, "How many TCP TLS routing decisions were looked up in the SNI cache, by entryPoint and result"),
+		tcpInFlightClientsGauge:          newOTLPGaugeFrom(meter, tcpInFlightClientsName, "How many clients hold connections of an InFlightConn TCP middleware, queued ones included, by middleware", "1"),
+		tcpInFlightQueueTimeoutsCounter:  newOTLPCounterFrom(meter, tcpInFlightQueueTimeoutsTotalName, "How many queued connections of an InFlightConn TCP middleware were closed for exceeding the queue timeout, by middleware and service"),
+		tcpAdmissionConnsGauge:           newOTLPGaugeFrom(meter, tcpAdmissionConnsName, "How many TCP connections the TCP admission holds, by priority", "1"),
+		tcpAdmissionRejectsCounter:       newOTLPCounterFrom(meter, tcpAdmissionRejectsTotalName, "How many TCP connections were rejected by the TCP admission under connection pressure, by priority"),
+		tcpIPDecisionCacheLookupsCounter: newOTLPCounterFrom(meter, tcpIPDecisionCacheLookupsTotalName, "How many TCP connections source IPs were looked up in the caches of the ClientIP and SourceRange decisions, by type and result"),
+	}
+
+	reg.tcpInFlightQueueWaitHistogram, _ = NewHistogramWithScale(newOTLPHistogramFrom(meter, tcpInFlightQueueWaitDurationName,
+		"How long the queued connections of an InFlightConn TCP middleware waited to be granted, by middleware and service",
+		"ms"), time.Second)
 
 	if config.AddEntryPointsLabels {
 		reg.entryPointReqsCounter = NewCounterWithNoopHeaders(newOTLPCounterFrom(meter, entryPointReqsTotalName,
@@ -151,6 +164,10 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OTLP) Registry {
 			"The total size of requests in bytes handled by a router, partitioned by status code, protocol, and method.")
 		reg.routerRespsBytesCounter = newOTLPCounterFrom(meter, routerRespsBytesTotalName,
 			"The total size of responses in bytes handled by a router, partitioned by status code, protocol, and method.")
+		reg.tcpRouterIdleReapedConnsCounter = newOTLPCounterFrom(meter, tcpRouterIdleReapedConnsTotalName,
+			"How many TCP connections were closed for exceeding the idle timeout of their service, by router")
+		reg.tcpRouterConcurrencyHistogram = newOTLPHistogramFrom(meter, tcpRouterConcurrentConnsName,
+			"How many concurrent connections a TCP router had, sampled periodically, by router", "1")
 	}
 
 	if config.AddServicesLabels {
@@ -170,6 +187,14 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OTLP) Registry {
 			"The total size of requests in bytes received by a service, partitioned by status code, protocol, and method.")
 		reg.serviceRespsBytesCounter = newOTLPCounterFrom(meter, serviceRespsBytesTotalName,
 			"The total size of responses in bytes returned by a service, partitioned by status code, protocol, and method.")
+		reg.serviceTCPDialDurationHistogram, _ = NewHistogramWithScale(newOTLPHistogramFrom(meter, serviceTCPDialDurationName,
+			"How long it took to establish the connections to the servers of a TCP service, partitioned by server.",
+			"ms"), time.Second)
+		reg.serviceTCPComparisonsCounter = newOTLPCounterFrom(meter, serviceTCPComparisonsName,
+			"How many responses of the mirror of a TCP service were compared to the ones of the service, partitioned by result.")
+		reg.serviceTCPServerConnsGauge = newOTLPGaugeFrom(meter, serviceTCPServerConnsName,
+			"How many connections are open to each server of a TCP service limiting the connections per server, partitioned by server.",
+			"1")
 	}
 
 	return reg
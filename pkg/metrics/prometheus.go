@@ -29,6 +29,29 @@ const (
 	// TLS.
 	metricsTLSPrefix              = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestampName = metricsTLSPrefix + "certs_not_after"
+	tlsHandshakesInProgressName   = metricsTLSPrefix + "handshakes_in_progress"
+	tlsHandshakesQueuedName       = metricsTLSPrefix + "handshakes_queued"
+	tlsSNIRejectsTotalName        = metricsTLSPrefix + "sni_rejects_total"
+	tlsSNICacheLookupsTotalName   = metricsTLSPrefix + "sni_cache_lookups_total"
+
+	// TCP router level.
+	metricTCPRouterPrefix             = MetricNamePrefix + "tcp_router_"
+	tcpRouterIdleReapedConnsTotalName = metricTCPRouterPrefix + "idle_reaped_connections_total"
+	tcpRouterConcurrentConnsName      = metricTCPRouterPrefix + "concurrent_connections"
+
+	// TCP middleware level.
+	metricTCPMiddlewarePrefix         = MetricNamePrefix + "tcp_middleware_"
+	tcpInFlightClientsName            = metricTCPMiddlewarePrefix + "inflight_clients"
+	tcpInFlightQueueWaitDurationName  = metricTCPMiddlewarePrefix + "inflight_queue_wait_duration_seconds"
+	tcpInFlightQueueTimeoutsTotalName = metricTCPMiddlewarePrefix + "inflight_queue_timeouts_total"
+
+	// TCP admission.
+	metricTCPAdmissionPrefix     = MetricNamePrefix + "tcp_admission_"
+	tcpAdmissionConnsName        = metricTCPAdmissionPrefix + "connections"
+	tcpAdmissionRejectsTotalName = metricTCPAdmissionPrefix + "rejects_total"
+
+	// TCP IP decision cache.
+	tcpIPDecisionCacheLookupsTotalName = MetricNamePrefix + "tcp_ip_decision_cache_lookups_total"
 
 	// entry point.
 	metricEntryPointPrefix        = MetricNamePrefix + "entrypoint_"
@@ -55,6 +78,9 @@ const (
 	serviceServerUpName        = metricServicePrefix + "server_up"
 	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
 	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+	serviceTCPDialDurationName = metricServicePrefix + "tcp_dial_duration_seconds"
+	serviceTCPComparisonsName  = metricServicePrefix + "tcp_mirror_comparisons_total"
+	serviceTCPServerConnsName  = metricServicePrefix + "tcp_server_open_connections"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -73,6 +99,10 @@ var promState = newPrometheusState()
 
 var promRegistry = stdprometheus.NewRegistry()
 
+// tcpRouterConcurrentConnsBuckets are the buckets of the sampled concurrent connections of the TCP routers,
+// spread to help sizing their maximum connections.
+var tcpRouterConcurrentConnsBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
 // PrometheusHandler exposes Prometheus routes.
 func PrometheusHandler() http.Handler {
 	return promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{})
@@ -122,6 +152,47 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestampName,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	tlsHandshakesInProgress := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: tlsHandshakesInProgressName,
+		Help: "How many TLS handshakes of TCP routers are in progress, by entryPoint",
+	}, []string{"entrypoint"})
+	tlsHandshakesQueued := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: tlsHandshakesQueuedName,
+		Help: "How many TLS handshakes of TCP routers are waiting for the handshakes concurrency limit, by entryPoint",
+	}, []string{"entrypoint"})
+	tlsSNIRejects := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tlsSNIRejectsTotalName,
+		Help: "How many TLS connections were rejected for an SNI exceeding the maximum length, by entryPoint",
+	}, []string{"entrypoint"})
+	tlsSNICacheLookups := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tlsSNICacheLookupsTotalName,
+		Help: "How many TCP TLS routing decisions were looked up in the SNI cache, by entryPoint and result",
+	}, []string{"entrypoint", "result"})
+	tcpInFlightClients := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: tcpInFlightClientsName,
+		Help: "How many clients hold connections of an InFlightConn TCP middleware, queued ones included, by middleware",
+	}, []string{"middleware"})
+	tcpInFlightQueueWaitDurations := newHistogramFrom(stdprometheus.HistogramOpts{
+		Name:    tcpInFlightQueueWaitDurationName,
+		Help:    "How long the queued connections of an InFlightConn TCP middleware waited to be granted, by middleware and service",
+		Buckets: buckets,
+	}, []string{"middleware", "service"})
+	tcpInFlightQueueTimeouts := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tcpInFlightQueueTimeoutsTotalName,
+		Help: "How many queued connections of an InFlightConn TCP middleware were closed for exceeding the queue timeout, by middleware and service",
+	}, []string{"middleware", "service"})
+	tcpAdmissionConns := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: tcpAdmissionConnsName,
+		Help: "How many TCP connections the TCP admission holds, by priority",
+	}, []string{"priority"})
+	tcpAdmissionRejects := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tcpAdmissionRejectsTotalName,
+		Help: "How many TCP connections were rejected by the TCP admission under connection pressure, by priority",
+	}, []string{"priority"})
+	tcpIPDecisionCacheLookups := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tcpIPDecisionCacheLookupsTotalName,
+		Help: "How many TCP connections source IPs were looked up in the caches of the ClientIP and SourceRange decisions, by type and result",
+	}, []string{"type", "result"})
 	openConnections := newGaugeFrom(stdprometheus.GaugeOpts{
 		Name: openConnectionsName,
 		Help: "How many open connections exist, by entryPoint and protocol",
@@ -131,18 +202,39 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		configReloads.cv,
 		lastConfigReloadSuccess.gv,
 		tlsCertsNotAfterTimestamp.gv,
+		tlsHandshakesInProgress.gv,
+		tlsHandshakesQueued.gv,
+		tlsSNIRejects.cv,
+		tlsSNICacheLookups.cv,
+		tcpInFlightClients.gv,
+		tcpInFlightQueueWaitDurations.hv,
+		tcpInFlightQueueTimeouts.cv,
+		tcpAdmissionConns.gv,
+		tcpAdmissionRejects.cv,
+		tcpIPDecisionCacheLookups.cv,
 		openConnections.gv,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           configReloads,
-		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
-		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
-		openConnectionsGauge:           openConnections,
-	}
+		epEnabled:                        config.AddEntryPointsLabels,
+		routerEnabled:                    config.AddRoutersLabels,
+		svcEnabled:                       config.AddServicesLabels,
+		configReloadsCounter:             configReloads,
+		lastConfigReloadSuccessGauge:     lastConfigReloadSuccess,
+		tlsCertsNotAfterTimestampGauge:   tlsCertsNotAfterTimestamp,
+		tlsHandshakesInProgressGauge:     tlsHandshakesInProgress,
+		tlsHandshakesQueuedGauge:         tlsHandshakesQueued,
+		tlsSNIRejectsCounter:             tlsSNIRejects,
+		tlsSNICacheLookupsCounter:        tlsSNICacheLookups,
+		tcpInFlightClientsGauge:          tcpInFlightClients,
+		tcpInFlightQueueTimeoutsCounter:  tcpInFlightQueueTimeouts,
+		tcpAdmissionConnsGauge:           tcpAdmissionConns,
+		tcpAdmissionRejectsCounter:       tcpAdmissionRejects,
+		tcpIPDecisionCacheLookupsCounter: tcpIPDecisionCacheLookups,
+		openConnectionsGauge:             openConnections,
+	}
+
+	reg.tcpInFlightQueueWaitHistogram, _ = NewHistogramWithScale(tcpInFlightQueueWaitDurations, time.Second)
 
 	if config.AddEntryPointsLabels {
 		entryPointReqs := newCounterWithHeadersFrom(stdprometheus.CounterOpts{
@@ -204,6 +296,15 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: routerRespsBytesTotalName,
 			Help: "The total size of responses in bytes handled by a router, partitioned by service, status code, protocol, and method.",
 		}, []string{"code", "method", "protocol", "router", "service"})
+		tcpRouterIdleReapedConns := newCounterFrom(stdprometheus.CounterOpts{
+			Name: tcpRouterIdleReapedConnsTotalName,
+			Help: "How many TCP connections were closed for exceeding the idle timeout of their service, by router",
+		}, []string{"router"})
+		tcpRouterConcurrentConns := newHistogramFrom(stdprometheus.HistogramOpts{
+			Name:    tcpRouterConcurrentConnsName,
+			Help:    "How many concurrent connections a TCP router had, sampled periodically, by router",
+			Buckets: tcpRouterConcurrentConnsBuckets,
+		}, []string{"router"})
 
 		promState.vectors = append(promState.vectors,
 			routerReqs.cv,
@@ -211,12 +312,16 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			routerReqDurations.hv,
 			routerReqsBytesTotal.cv,
 			routerRespsBytesTotal.cv,
+			tcpRouterIdleReapedConns.cv,
+			tcpRouterConcurrentConns.hv,
 		)
 		reg.routerReqsCounter = routerReqs
 		reg.routerReqsTLSCounter = routerReqsTLS
 		reg.routerReqDurationHistogram, _ = NewHistogramWithScale(routerReqDurations, time.Second)
 		reg.routerReqsBytesCounter = routerReqsBytesTotal
 		reg.routerRespsBytesCounter = routerRespsBytesTotal
+		reg.tcpRouterIdleReapedConnsCounter = tcpRouterIdleReapedConns
+		reg.tcpRouterConcurrencyHistogram = tcpRouterConcurrentConns
 	}
 
 	if config.AddServicesLabels {
@@ -249,6 +354,19 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceRespsBytesTotalName,
 			Help: "The total size of responses in bytes returned by a service, partitioned by status code, protocol, and method.",
 		}, []string{"code", "method", "protocol", "service"})
+		serviceTCPDialDurations := newHistogramFrom(stdprometheus.HistogramOpts{
+			Name:    serviceTCPDialDurationName,
+			Help:    "How long it took to establish the connections to the servers of a TCP service, partitioned by server.",
+			Buckets: buckets,
+		}, []string{"service", "server"})
+		serviceTCPComparisons := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceTCPComparisonsName,
+			Help: "How many responses of the mirror of a TCP service were compared to the ones of the service, partitioned by result.",
+		}, []string{"service", "result"})
+		serviceTCPServerConns := newGaugeFrom(stdprometheus.GaugeOpts{
+			Name: serviceTCPServerConnsName,
+			Help: "How many connections are open to each server of a TCP service limiting the connections per server, partitioned by server.",
+		}, []string{"service", "server"})
 
 		promState.vectors = append(promState.vectors,
 			serviceReqs.cv,
@@ -258,6 +376,9 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceServerUp.gv,
 			serviceReqsBytesTotal.cv,
 			serviceRespsBytesTotal.cv,
+			serviceTCPDialDurations.hv,
+			serviceTCPComparisons.cv,
+			serviceTCPServerConns.gv,
 		)
 
 		reg.serviceReqsCounter = serviceReqs
@@ -267,6 +388,9 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceServerUpGauge = serviceServerUp
 		reg.serviceReqsBytesCounter = serviceReqsBytesTotal
 		reg.serviceRespsBytesCounter = serviceRespsBytesTotal
+		reg.serviceTCPDialDurationHistogram, _ = NewHistogramWithScale(serviceTCPDialDurations, time.Second)
+		reg.serviceTCPComparisonsCounter = serviceTCPComparisons
+		reg.serviceTCPServerConnsGauge = serviceTCPServerConns
 	}
 
 	return reg
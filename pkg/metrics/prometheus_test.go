@@ -114,6 +114,54 @@ func TestPrometheus(t *testing.T) {
 		TLSCertsNotAfterTimestampGauge().
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		TLSHandshakesInProgressGauge().
+		With("entrypoint", "test").
+		Set(2)
+	prometheusRegistry.
+		TLSHandshakesQueuedGauge().
+		With("entrypoint", "test").
+		Set(1)
+	prometheusRegistry.
+		TLSSNIRejectsCounter().
+		With("entrypoint", "test").
+		Add(1)
+	prometheusRegistry.
+		TLSSNICacheLookupsCounter().
+		With("entrypoint", "test", "result", "hit").
+		Add(1)
+	prometheusRegistry.
+		TCPRouterIdleReapedConnsCounter().
+		With("router", "demo").
+		Add(1)
+	prometheusRegistry.
+		TCPRouterConcurrencyHistogram().
+		With("router", "demo").
+		Observe(12)
+	prometheusRegistry.
+		TCPInFlightClientsGauge().
+		With("middleware", "demo").
+		Set(2)
+	prometheusRegistry.
+		TCPInFlightQueueWaitHistogram().
+		With("middleware", "demo", "service", "demo").
+		Observe(1)
+	prometheusRegistry.
+		TCPInFlightQueueTimeoutsCounter().
+		With("middleware", "demo", "service", "demo").
+		Add(1)
+	prometheusRegistry.
+		TCPAdmissionConnsGauge().
+		With("priority", "5").
+		Set(3)
+	prometheusRegistry.
+		TCPAdmissionRejectsCounter().
+		With("priority", "-5").
+		Add(1)
+	prometheusRegistry.
+		TCPIPDecisionCacheLookupsCounter().
+		With("type", "ClientIP", "result", "hit").
+		Add(1)
 
 	prometheusRegistry.
 		EntryPointReqsCounter().
@@ -181,6 +229,18 @@ func TestPrometheus(t *testing.T) {
 		ServiceReqsBytesCounter().
 		With("service", "service1", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet, "protocol", "http").
 		Add(1)
+	prometheusRegistry.
+		ServiceTCPDialDurationHistogram().
+		With("service", "service1", "server", "127.0.0.10:5432").
+		Observe(0.01)
+	prometheusRegistry.
+		ServiceTCPComparisonsCounter().
+		With("service", "service1", "result", "divergence").
+		Add(1)
+	prometheusRegistry.
+		ServiceTCPServerConnsGauge().
+		With("service", "service1", "server", "127.0.0.10:5432").
+		Set(2)
 
 	delayForTrackingCompletion()
 
@@ -216,6 +276,94 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestampName),
 		},
+		{
+			name: tlsHandshakesInProgressName,
+			labels: map[string]string{
+				"entrypoint": "test",
+			},
+			assert: buildGaugeAssert(t, tlsHandshakesInProgressName, 2),
+		},
+		{
+			name: tlsHandshakesQueuedName,
+			labels: map[string]string{
+				"entrypoint": "test",
+			},
+			assert: buildGaugeAssert(t, tlsHandshakesQueuedName, 1),
+		},
+		{
+			name: tlsSNIRejectsTotalName,
+			labels: map[string]string{
+				"entrypoint": "test",
+			},
+			assert: buildCounterAssert(t, tlsSNIRejectsTotalName, 1),
+		},
+		{
+			name: tlsSNICacheLookupsTotalName,
+			labels: map[string]string{
+				"entrypoint": "test",
+				"result":     "hit",
+			},
+			assert: buildCounterAssert(t, tlsSNICacheLookupsTotalName, 1),
+		},
+		{
+			name: tcpRouterIdleReapedConnsTotalName,
+			labels: map[string]string{
+				"router": "demo",
+			},
+			assert: buildCounterAssert(t, tcpRouterIdleReapedConnsTotalName, 1),
+		},
+		{
+			name: tcpRouterConcurrentConnsName,
+			labels: map[string]string{
+				"router": "demo",
+			},
+			assert: buildHistogramAssert(t, tcpRouterConcurrentConnsName, 1),
+		},
+		{
+			name: tcpInFlightClientsName,
+			labels: map[string]string{
+				"middleware": "demo",
+			},
+			assert: buildGaugeAssert(t, tcpInFlightClientsName, 2),
+		},
+		{
+			name: tcpInFlightQueueWaitDurationName,
+			labels: map[string]string{
+				"middleware": "demo",
+				"service":    "demo",
+			},
+			assert: buildHistogramAssert(t, tcpInFlightQueueWaitDurationName, 1),
+		},
+		{
+			name: tcpInFlightQueueTimeoutsTotalName,
+			labels: map[string]string{
+				"middleware": "demo",
+				"service":    "demo",
+			},
+			assert: buildCounterAssert(t, tcpInFlightQueueTimeoutsTotalName, 1),
+		},
+		{
+			name: tcpAdmissionConnsName,
+			labels: map[string]string{
+				"priority": "5",
+			},
+			assert: buildGaugeAssert(t, tcpAdmissionConnsName, 3),
+		},
+		{
+			name: tcpAdmissionRejectsTotalName,
+			labels: map[string]string{
+				"priority": "-5",
+			},
+			assert: buildCounterAssert(t, tcpAdmissionRejectsTotalName, 1),
+		},
+		{
+			name: tcpIPDecisionCacheLookupsTotalName,
+			labels: map[string]string{
+				"type":   "ClientIP",
+				"result": "hit",
+			},
+			assert: buildCounterAssert(t, tcpIPDecisionCacheLookupsTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -377,6 +525,30 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildCounterAssert(t, serviceRespsBytesTotalName, 1),
 		},
+		{
+			name: serviceTCPDialDurationName,
+			labels: map[string]string{
+				"service": "service1",
+				"server":  "127.0.0.10:5432",
+			},
+			assert: buildHistogramAssert(t, serviceTCPDialDurationName, 1),
+		},
+		{
+			name: serviceTCPComparisonsName,
+			labels: map[string]string{
+				"service": "service1",
+				"result":  "divergence",
+			},
+			assert: buildCounterAssert(t, serviceTCPComparisonsName, 1),
+		},
+		{
+			name: serviceTCPServerConnsName,
+			labels: map[string]string{
+				"service": "service1",
+				"server":  "127.0.0.10:5432",
+			},
+			assert: buildGaugeAssert(t, serviceTCPServerConnsName, 2),
+		},
 	}
 
 	for _, test := range testCases {
@@ -22,6 +22,22 @@ const (
 	statsdOpenConnectionsName         = "open.connections"
 
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	statsdTLSHandshakesInProgressName   = "tls.handshakes.inProgress"
+	statsdTLSHandshakesQueuedName       = "tls.handshakes.queued"
+	statsdTLSSNIRejectsName             = "tls.sni.rejects.total"
+	statsdTLSSNICacheLookupsName        = "tls.sni.cache.lookups.total"
+
+	statsdTCPRouterIdleReapedConnsName = "tcp.router.connections.idleReaped.total"
+	statsdTCPRouterConcurrentConnsName = "tcp.router.connections.concurrent"
+
+	statsdTCPInFlightClientsName           = "tcp.middleware.inflight.clients"
+	statsdTCPInFlightQueueWaitDurationName = "tcp.middleware.inflight.queue.wait.duration"
+	statsdTCPInFlightQueueTimeoutsName     = "tcp.middleware.inflight.queue.timeouts.total"
+
+	statsdTCPAdmissionConnsName   = "tcp.admission.connections"
+	statsdTCPAdmissionRejectsName = "tcp.admission.rejects.total"
+
+	statsdTCPIPDecisionCacheLookupsName = "tcp.ip.decision.cache.lookups.total"
 
 	statsdEntryPointReqsName        = "entrypoint.request.total"
 	statsdEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -42,6 +58,9 @@ const (
 	statsdServiceServerUpName     = "service.server.up"
 	statsdServiceReqsBytesName    = "service.requests.bytes.total"
 	statsdServiceRespsBytesName   = "service.responses.bytes.total"
+	statsdServiceDialDurationName = "service.tcp.dial.duration"
+	statsdServiceComparisonsName  = "service.tcp.mirror.comparisons.total"
+	statsdServiceServerConnsName  = "service.tcp.server.connections"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -58,12 +77,23 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 	}
 
 	registry := &standardRegistry{
-		configReloadsCounter:           statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
-		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
-		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
-		openConnectionsGauge:           statsdClient.NewGauge(statsdOpenConnectionsName),
+		configReloadsCounter:             statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
+		lastConfigReloadSuccessGauge:     statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
+		tlsCertsNotAfterTimestampGauge:   statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		tlsHandshakesInProgressGauge:     statsdClient.NewGauge(statsdTLSHandshakesInProgressName),
+		tlsHandshakesQueuedGauge:         statsdClient.NewGauge(statsdTLSHandshakesQueuedName),
+		tlsSNIRejectsCounter:             statsdClient.NewCounter(statsdTLSSNIRejectsName, 1.0),
+		tlsSNICacheLookupsCounter:        statsdClient.NewCounter(statsdTLSSNICacheLookupsName, 1.0),
+		tcpInFlightClientsGauge:          statsdClient.NewGauge(statsdTCPInFlightClientsName),
+		tcpInFlightQueueTimeoutsCounter:  statsdClient.NewCounter(statsdTCPInFlightQueueTimeoutsName, 1.0),
+		tcpAdmissionConnsGauge:           statsdClient.NewGauge(statsdTCPAdmissionConnsName),
+		tcpAdmissionRejectsCounter:       statsdClient.NewCounter(statsdTCPAdmissionRejectsName, 1.0),
+		tcpIPDecisionCacheLookupsCounter: statsdClient.NewCounter(statsdTCPIPDecisionCacheLookupsName, 1.0),
+		openConnectionsGauge:             statsdClient.NewGauge(statsdOpenConnectionsName),
 	}
 
+	registry.tcpInFlightQueueWaitHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdTCPInFlightQueueWaitDurationName, 1.0), time.Millisecond)
+
 	if config.AddEntryPointsLabels {
 		registry.epEnabled = config.AddEntryPointsLabels
 		registry.entryPointReqsCounter = NewCounterWithNoopHeaders(statsdClient.NewCounter(statsdEntryPointReqsName, 1.0))
@@ -80,6 +110,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.routerReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdRouterReqsDurationName, 1.0), time.Millisecond)
 		registry.routerReqsBytesCounter = statsdClient.NewCounter(statsdRouterReqsBytesName, 1.0)
 		registry.routerRespsBytesCounter = statsdClient.NewCounter(statsdRouterRespsBytesName, 1.0)
+		registry.tcpRouterIdleReapedConnsCounter = statsdClient.NewCounter(statsdTCPRouterIdleReapedConnsName, 1.0)
+		registry.tcpRouterConcurrencyHistogram = statsdClient.NewTiming(statsdTCPRouterConcurrentConnsName, 1.0)
 	}
 
 	if config.AddServicesLabels {
@@ -91,6 +123,9 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
 		registry.serviceReqsBytesCounter = statsdClient.NewCounter(statsdServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = statsdClient.NewCounter(statsdServiceRespsBytesName, 1.0)
+		registry.serviceTCPDialDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdServiceDialDurationName, 1.0), time.Millisecond)
+		registry.serviceTCPComparisonsCounter = statsdClient.NewCounter(statsdServiceComparisonsName, 1.0)
+		registry.serviceTCPServerConnsGauge = statsdClient.NewGauge(statsdServiceServerConnsName)
 	}
 
 	return registry
@@ -40,6 +40,22 @@ func TestStatsDWithPrefix(t *testing.T) {
 	testRegistry(t, "testPrefix", statsdRegistry)
 }
 
+func TestStatsDWithoutRoutersLabels(t *testing.T) {
+	t.Cleanup(func() {
+		StopStatsd()
+	})
+
+	statsdRegistry := RegisterStatsd(context.Background(), &types.Statsd{Address: ":18125", PushInterval: ptypes.Duration(time.Second)})
+
+	// The router labeled metrics are only registered along with the routers labels.
+	if statsdRegistry.TCPRouterIdleReapedConnsCounter() != nil {
+		t.Errorf("Statsd registry should not register the TCP router idle reaped connections counter without the routers labels")
+	}
+	if statsdRegistry.TCPRouterConcurrencyHistogram() != nil {
+		t.Errorf("Statsd registry should not register the TCP router concurrency histogram without the routers labels")
+	}
+}
+
 func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 	t.Helper()
 
@@ -53,6 +69,20 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".open.connections:1.000000|g\n",
 
 		metricsPrefix + ".tls.certs.notAfterTimestamp:1.000000|g\n",
+		metricsPrefix + ".tls.handshakes.inProgress:2.000000|g\n",
+		metricsPrefix + ".tls.handshakes.queued:1.000000|g\n",
+		metricsPrefix + ".tls.sni.rejects.total:1.000000|c\n",
+		metricsPrefix + ".tls.sni.cache.lookups.total:1.000000|c\n",
+
+		metricsPrefix + ".tcp.router.connections.idleReaped.total:1.000000|c\n",
+		metricsPrefix + ".tcp.router.connections.concurrent:12.000000|ms",
+		metricsPrefix + ".tcp.middleware.inflight.clients:2.000000|g\n",
+		metricsPrefix + ".tcp.middleware.inflight.queue.wait.duration:1.000000|ms",
+		metricsPrefix + ".tcp.middleware.inflight.queue.timeouts.total:1.000000|c\n",
+
+		metricsPrefix + ".tcp.admission.connections:3.000000|g\n",
+		metricsPrefix + ".tcp.admission.rejects.total:1.000000|c\n",
+		metricsPrefix + ".tcp.ip.decision.cache.lookups.total:1.000000|c\n",
 
 		metricsPrefix + ".entrypoint.request.total:1.000000|c\n",
 		metricsPrefix + ".entrypoint.request.tls.total:1.000000|c\n",
@@ -73,6 +103,9 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		metricsPrefix + ".service.server.up:1.000000|g\n",
 		metricsPrefix + ".service.requests.bytes.total:1.000000|c\n",
 		metricsPrefix + ".service.responses.bytes.total:1.000000|c\n",
+		metricsPrefix + ".service.tcp.dial.duration:10.000000|ms",
+		metricsPrefix + ".service.tcp.mirror.comparisons.total:1.000000|c\n",
+		metricsPrefix + ".service.tcp.server.connections:2.000000|g\n",
 	}
 
 	udp.ShouldReceiveAll(t, expected, func() {
@@ -81,6 +114,22 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.OpenConnectionsGauge().With("entrypoint", "test", "protocol", "TCP").Set(1)
 
 		registry.TLSCertsNotAfterTimestampGauge().With("key", "value").Set(1)
+		registry.TLSHandshakesInProgressGauge().With("entrypoint", "test").Set(2)
+		registry.TLSHandshakesQueuedGauge().With("entrypoint", "test").Set(1)
+		registry.TLSSNIRejectsCounter().With("entrypoint", "test").Add(1)
+		registry.TLSSNICacheLookupsCounter().With("entrypoint", "test", "result", "hit").Add(1)
+
+		registry.TCPRouterIdleReapedConnsCounter().With("router", "demo").Add(1)
+		registry.TCPRouterConcurrencyHistogram().With("router", "demo").Observe(12)
+
+		registry.TCPInFlightClientsGauge().With("middleware", "demo").Set(2)
+		registry.TCPInFlightQueueWaitHistogram().With("middleware", "demo", "service", "demo").Observe(1)
+		registry.TCPInFlightQueueTimeoutsCounter().With("middleware", "demo", "service", "demo").Add(1)
+
+		registry.TCPAdmissionConnsGauge().With("priority", "5").Set(3)
+		registry.TCPAdmissionRejectsCounter().With("priority", "-5").Add(1)
+
+		registry.TCPIPDecisionCacheLookupsCounter().With("type", "ClientIP", "result", "hit").Add(1)
 
 		registry.EntryPointReqsCounter().With(nil, "entrypoint", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		registry.EntryPointReqsTLSCounter().With("entrypoint", "test", "tls_version", "foo", "tls_cipher", "bar").Add(1)
@@ -104,5 +153,8 @@ func testRegistry(t *testing.T, metricsPrefix string, registry Registry) {
 		registry.ServiceServerUpGauge().With("service:test", "url", "http://127.0.0.1").Set(1)
 		registry.ServiceReqsBytesCounter().With("service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
 		registry.ServiceRespsBytesCounter().With("service", "test", "code", strconv.Itoa(http.StatusOK), "method", http.MethodGet).Add(1)
+		registry.ServiceTCPDialDurationHistogram().With("service", "test", "server", "127.0.0.1:5432").Observe(10)
+		registry.ServiceTCPComparisonsCounter().With("service", "test", "result", "match").Add(1)
+		registry.ServiceTCPServerConnsGauge().With("service", "test", "server", "127.0.0.1:5432").Set(2)
 	})
 }
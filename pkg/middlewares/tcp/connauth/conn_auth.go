@@ -0,0 +1,213 @@
+package connauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const typeName = "ConnAuthTCP"
+
+const (
+	defaultTimeout       = 500 * time.Millisecond
+	defaultCacheDuration = 5 * time.Second
+	// maxCachedDecisions bounds the memory of the cache when many distinct clients connect within the cache duration.
+	maxCachedDecisions = 10000
+)
+
+// Request is the body of the requests sent to the webhook for each connection.
+type Request struct {
+	// Source is the IP of the client of the connection.
+	Source string `json:"source"`
+	// ServerName is the SNI sent by the client of the TLS connection, if any.
+	ServerName string `json:"serverName,omitempty"`
+	// Router is the name of the TCP router the connection is routed by.
+	Router string `json:"router,omitempty"`
+}
+
+type decision struct {
+	allowed bool
+	expires time.Time
+}
+
+// connAuth is a middleware delegating the authorization of the connections to a webhook.
+type connAuth struct {
+	name          string
+	next          tcp.Handler
+	address       string
+	client        *http.Client
+	failOpen      bool
+	cacheDuration time.Duration
+
+	mu        sync.Mutex
+	decisions map[Request]decision
+}
+
+// New creates a ConnAuth middleware, which consults the webhook before forwarding the connections to next.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPConnAuth, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	address, err := url.ParseRequestURI(config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook address %q: %w", config.Address, err)
+	}
+
+	// Only HTTP webhooks are supported, the gRPC ones are not.
+	if address.Scheme != "http" && address.Scheme != "https" {
+		return nil, fmt.Errorf("invalid webhook address %q: unsupported scheme %q, only http and https are supported", config.Address, address.Scheme)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	cacheDuration := time.Duration(config.CacheDuration)
+	if cacheDuration == 0 {
+		cacheDuration = defaultCacheDuration
+	}
+
+	return &connAuth{
+		name:          name,
+		next:          next,
+		address:       config.Address,
+		client:        &http.Client{Timeout: timeout},
+		failOpen:      config.FailOpen,
+		cacheDuration: cacheDuration,
+		decisions:     make(map[Request]decision),
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection, once allowed by the webhook.
+func (a *connAuth) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), a.name, typeName)
+
+	request, err := newRequest(conn)
+	if err != nil {
+		logger.Error().Err(err).Msg("Cannot build the webhook request of the connection")
+		conn.Close()
+		return
+	}
+
+	if !a.allowed(request) {
+		logger.Debug().Msgf("Connection from %s denied", conn.RemoteAddr())
+		conn.Close()
+		return
+	}
+
+	a.next.ServeTCP(conn)
+}
+
+// newRequest returns the webhook request of the given connection.
+func newRequest(conn tcp.WriteCloser) (Request, error) {
+	source, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return Request{}, fmt.Errorf("cannot parse IP from remote addr: %w", err)
+	}
+
+	request := Request{Source: source}
+	if attributes := tcp.GetConnAttributes(conn); attributes != nil {
+		request.ServerName, _ = attributes.Get(tcp.SNIAttribute)
+		request.Router, _ = attributes.Get(tcp.RouterAttribute)
+	}
+
+	return request, nil
+}
+
+// allowed returns whether the connection of the given request is allowed,
+// according to the cached decision, or to the webhook.
+func (a *connAuth) allowed(request Request) bool {
+	if allowed, ok := a.cached(request); ok {
+		return allowed
+	}
+
+	allowed, err := a.consult(request)
+	if err != nil {
+		logger := middlewares.GetLogger(context.Background(), a.name, typeName)
+		logger.Error().Err(err).Bool("failOpen", a.failOpen).Msg("Cannot consult the webhook")
+
+		// The failures are not cached, for the webhook to be consulted again as soon as it is available.
+		return a.failOpen
+	}
+
+	a.cache(request, allowed)
+
+	return allowed
+}
+
+// consult sends the request to the webhook, and returns its decision.
+func (a *connAuth) consult(request Request) (bool, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, fmt.Errorf("marshaling the webhook request: %w", err)
+	}
+
+	resp, err := a.client.Post(a.address, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices:
+		return true, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected webhook status code %d", resp.StatusCode)
+	}
+}
+
+func (a *connAuth) cached(request Request) (bool, bool) {
+	if a.cacheDuration < 0 {
+		return false, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	d, ok := a.decisions[request]
+	if !ok || time.Now().After(d.expires) {
+		return false, false
+	}
+
+	return d.allowed, true
+}
+
+// cache caches the decision of the webhook for the cache duration.
+// When the cache is full, the expired decisions are evicted, and the decision is not cached if none expired.
+func (a *connAuth) cache(request Request, allowed bool) {
+	if a.cacheDuration < 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+
+	if len(a.decisions) >= maxCachedDecisions {
+		for r, d := range a.decisions {
+			if now.After(d.expires) {
+				delete(a.decisions, r)
+			}
+		}
+
+		if len(a.decisions) >= maxCachedDecisions {
+			return
+		}
+	}
+
+	a.decisions[request] = decision{allowed: allowed, expires: now.Add(a.cacheDuration)}
+}
@@ -0,0 +1,231 @@
+package connauth
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestConnAuth_ServeTCP(t *testing.T) {
+	var requests atomic.Int64
+	webhook := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+
+		var request Request
+		if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// The webhook denies the connections of a source, and the ones to an SNI.
+		if request.Source == "10.0.0.2" || request.ServerName == "denied.example.com" {
+			rw.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(webhook.Close)
+
+	testCases := []struct {
+		desc       string
+		remoteAddr string
+		serverName string
+		expected   bool
+	}{
+		{
+			desc:       "allowed connection",
+			remoteAddr: "10.0.0.1:1234",
+			serverName: "foo.example.com",
+			expected:   true,
+		},
+		{
+			desc:       "connection denied by source",
+			remoteAddr: "10.0.0.2:1234",
+			serverName: "foo.example.com",
+		},
+		{
+			desc:       "connection denied by SNI",
+			remoteAddr: "10.0.0.1:1234",
+			serverName: "denied.example.com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			requests.Store(0)
+
+			var served atomic.Int64
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) { served.Add(1) })
+
+			middleware, err := New(context.Background(), next, dynamic.TCPConnAuth{Address: webhook.URL}, "foo")
+			require.NoError(t, err)
+
+			// The second connection, from the same source, is served from the cached decision.
+			for range 2 {
+				conn := newFakeConn(test.remoteAddr, test.serverName, "bar@file")
+				middleware.ServeTCP(conn)
+
+				assert.Equal(t, !test.expected, conn.closed)
+			}
+
+			if test.expected {
+				assert.Equal(t, int64(2), served.Load())
+			} else {
+				assert.Zero(t, served.Load())
+			}
+			assert.Equal(t, int64(1), requests.Load())
+		})
+	}
+}
+
+func TestConnAuth_request(t *testing.T) {
+	received := make(chan Request, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var request Request
+		_ = json.NewDecoder(req.Body).Decode(&request)
+		received <- request
+	}))
+	t.Cleanup(webhook.Close)
+
+	middleware, err := New(context.Background(), tcp.HandlerFunc(func(conn tcp.WriteCloser) {}), dynamic.TCPConnAuth{Address: webhook.URL}, "foo")
+	require.NoError(t, err)
+
+	middleware.ServeTCP(newFakeConn("10.0.0.1:1234", "foo.example.com", "bar@file"))
+
+	assert.Equal(t, Request{Source: "10.0.0.1", ServerName: "foo.example.com", Router: "bar@file"}, <-received)
+}
+
+func TestConnAuth_unavailableWebhook(t *testing.T) {
+	release := make(chan struct{})
+	webhook := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	t.Cleanup(func() {
+		close(release)
+		webhook.Close()
+	})
+
+	testCases := []struct {
+		desc     string
+		failOpen bool
+	}{
+		{
+			desc: "fail-closed",
+		},
+		{
+			desc:     "fail-open",
+			failOpen: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			var served atomic.Int64
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) { served.Add(1) })
+
+			config := dynamic.TCPConnAuth{
+				Address:  webhook.URL,
+				Timeout:  ptypes.Duration(50 * time.Millisecond),
+				FailOpen: test.failOpen,
+			}
+			middleware, err := New(context.Background(), next, config, "foo")
+			require.NoError(t, err)
+
+			start := time.Now()
+
+			conn := newFakeConn("10.0.0.1:1234", "", "")
+			middleware.ServeTCP(conn)
+
+			// The connection is not held beyond the timeout of the webhook.
+			assert.Less(t, time.Since(start), time.Second)
+			assert.Equal(t, !test.failOpen, conn.closed)
+			assert.Equal(t, test.failOpen, served.Load() == 1)
+		})
+	}
+}
+
+func TestNew_invalidAddress(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		address string
+	}{
+		{
+			desc:    "not a URL",
+			address: "webhook",
+		},
+		{
+			desc:    "gRPC webhook",
+			address: "grpc://auth.example.com:9000",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), nil, dynamic.TCPConnAuth{Address: test.address}, "foo")
+			assert.Error(t, err)
+		})
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+
+	addr       string
+	attributes *tcp.ConnAttributes
+	closed     bool
+}
+
+func newFakeConn(addr, serverName, router string) *fakeConn {
+	attributes := &tcp.ConnAttributes{}
+	if serverName != "" {
+		attributes.Set(tcp.SNIAttribute, serverName)
+	}
+	if router != "" {
+		attributes.Set(tcp.RouterAttribute, router)
+	}
+
+	return &fakeConn{addr: addr, attributes: attributes}
+}
+
+func (c *fakeConn) Attributes() *tcp.ConnAttributes {
+	return c.attributes
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return fakeAddr{addr: c.addr}
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	panic("implement me")
+}
+
+type fakeAddr struct {
+	addr string
+}
+
+func (a fakeAddr) Network() string {
+	return "tcp"
+}
+
+func (a fakeAddr) String() string {
+	return a.addr
+}
@@ -2,37 +2,96 @@ package inflightconn
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
+	"slices"
 	"sync"
+	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
 const typeName = "InFlightConnTCP"
 
+// tlsConn is a TLS terminated connection, whose client certificate is known once the handshake completed.
+type tlsConn interface {
+	HandshakeContext(ctx context.Context) error
+	ConnectionState() tls.ConnectionState
+}
+
+// Metrics are the metrics reported by the middleware, each of them being optional.
+type Metrics struct {
+	// Clients reports the number of clients holding connections, queued ones included.
+	Clients gokitmetrics.Gauge
+	// QueueWait observes how long the queued connections waited to be granted, by service.
+	QueueWait metrics.ScalableHistogram
+	// QueueTimeouts counts the queued connections closed for exceeding the queue timeout, by service.
+	QueueTimeouts gokitmetrics.Counter
+}
+
 type inFlightConn struct {
-	name           string
-	next           tcp.Handler
-	maxConnections int64
+	name             string
+	next             tcp.Handler
+	maxConnections   int64
+	totalConnections int64
+	queueTimeout     time.Duration
+	byClientCert     bool
+	// clients reports the number of clients holding connections, queued ones included, when set.
+	clients gokitmetrics.Gauge
+	// queueWait observes how long the queued connections waited to be granted, when set.
+	queueWait metrics.ScalableHistogram
+	// queueTimeouts counts the queued connections closed for exceeding the queue timeout, when set.
+	queueTimeouts gokitmetrics.Counter
 
 	mu          sync.Mutex
-	connections map[string]int64 // current number of connections by remote IP.
+	connections map[string]int64 // current number of connections by client, the queued ones included.
+	served      int64            // current number of connections served, across the clients.
+	queued      map[string][]chan struct{}
+	// rotation holds the clients with queued connections, in the order they are granted the freed connections.
+	rotation []string
 }
 
 // New creates a max connections middleware.
-// The connections are identified and grouped by remote IP.
-func New(ctx context.Context, next tcp.Handler, config dynamic.TCPInFlightConn, name string) (tcp.Handler, error) {
+// The connections are identified and grouped by remote IP, or by client certificate.
+// The given metrics are labeled by middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPInFlightConn, name string, inFlightMetrics Metrics) (tcp.Handler, error) {
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 
+	if config.TotalAmount < 0 {
+		return nil, fmt.Errorf("invalid total amount %d: must be positive", config.TotalAmount)
+	}
+
+	if inFlightMetrics.Clients != nil {
+		inFlightMetrics.Clients = inFlightMetrics.Clients.With("middleware", name)
+	}
+
+	if inFlightMetrics.QueueWait != nil {
+		inFlightMetrics.QueueWait = inFlightMetrics.QueueWait.With("middleware", name)
+	}
+
+	if inFlightMetrics.QueueTimeouts != nil {
+		inFlightMetrics.QueueTimeouts = inFlightMetrics.QueueTimeouts.With("middleware", name)
+	}
+
 	return &inFlightConn{
-		name:           name,
-		next:           next,
-		connections:    make(map[string]int64),
-		maxConnections: config.Amount,
+		name:             name,
+		next:             next,
+		connections:      make(map[string]int64),
+		queued:           make(map[string][]chan struct{}),
+		maxConnections:   config.Amount,
+		totalConnections: config.TotalAmount,
+		queueTimeout:     time.Duration(config.QueueTimeout),
+		byClientCert:     config.ByClientCert,
+		clients:          inFlightMetrics.Clients,
+		queueWait:        inFlightMetrics.QueueWait,
+		queueTimeouts:    inFlightMetrics.QueueTimeouts,
 	}, nil
 }
 
@@ -40,51 +99,181 @@ func New(ctx context.Context, next tcp.Handler, config dynamic.TCPInFlightConn,
 func (i *inFlightConn) ServeTCP(conn tcp.WriteCloser) {
 	logger := middlewares.GetLogger(context.Background(), i.name, typeName)
 
-	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	client, err := i.client(conn)
 	if err != nil {
-		logger.Error().Err(err).Msg("Cannot parse IP from remote addr")
+		logger.Error().Err(err).Msg("Cannot identify the client of the connection")
 		conn.Close()
 		return
 	}
 
-	if err = i.increment(ip); err != nil {
+	// The queue metrics are labeled by the service of the router the connection is routed by.
+	var service string
+	if attributes := tcp.GetConnAttributes(conn); attributes != nil {
+		service, _ = attributes.Get(tcp.ServiceAttribute)
+	}
+
+	if err = i.increment(client, service); err != nil {
 		logger.Error().Err(err).Msg("Connection rejected")
-		conn.Close()
+		tcp.CloseWithReason(conn, tcp.CloseReasonLimitExceeded)
 		return
 	}
 
-	defer i.decrement(ip)
+	defer i.decrement(client)
 
 	i.next.ServeTCP(conn)
 }
 
+// client returns the client of the given connection, which is its remote IP,
+// or the subject of its client certificate when the connections are grouped by client certificate.
+func (i *inFlightConn) client(conn tcp.WriteCloser) (string, error) {
+	if i.byClientCert {
+		if tlsConn, ok := conn.(tlsConn); ok {
+			if err := tcp.HandshakeTLS(tlsConn); err != nil {
+				return "", fmt.Errorf("TLS handshake: %w", err)
+			}
+
+			if certificates := tlsConn.ConnectionState().PeerCertificates; len(certificates) > 0 {
+				return certificates[0].Subject.String(), nil
+			}
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return "", fmt.Errorf("cannot parse IP from remote addr: %w", err)
+	}
+
+	return ip, nil
+}
+
 // increment increases the counter for the number of connections tracked for the
-// given IP.
+// given client.
 // It returns an error if the counter would go above the max allowed number of
 // connections.
-func (i *inFlightConn) increment(ip string) error {
+// When the total number of connections is reached, it waits for a connection to be granted,
+// up to the queue timeout, the queue metrics being labeled with the given service.
+func (i *inFlightConn) increment(client, service string) error {
+	i.mu.Lock()
+
+	if i.connections[client] >= i.maxConnections {
+		i.mu.Unlock()
+		return fmt.Errorf("max number of connections reached for %s", client)
+	}
+
+	if i.totalConnections == 0 || i.served < i.totalConnections {
+		i.served++
+		i.setConnections(client, i.connections[client]+1)
+		i.mu.Unlock()
+		return nil
+	}
+
+	if i.queueTimeout <= 0 {
+		i.mu.Unlock()
+		return errors.New("max total number of connections reached")
+	}
+
+	granted := make(chan struct{})
+	if len(i.queued[client]) == 0 {
+		i.rotation = append(i.rotation, client)
+	}
+	i.queued[client] = append(i.queued[client], granted)
+	i.setConnections(client, i.connections[client]+1)
+	i.mu.Unlock()
+
+	queuedAt := time.Now()
+	timer := time.NewTimer(i.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-granted:
+		i.observeQueueWait(service, queuedAt)
+		return nil
+	case <-timer.C:
+	}
+
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	if i.connections[ip] >= i.maxConnections {
-		return fmt.Errorf("max number of connections reached for %s", ip)
+	// The connection may have been granted in the meantime.
+	select {
+	case <-granted:
+		i.observeQueueWait(service, queuedAt)
+		return nil
+	default:
 	}
 
-	i.connections[ip]++
+	i.withdraw(client, granted)
+	i.setConnections(client, i.connections[client]-1)
 
-	return nil
+	if i.queueTimeouts != nil {
+		i.queueTimeouts.With("service", service).Add(1)
+	}
+
+	return fmt.Errorf("no connection terminated within the queue timeout for %s", client)
 }
 
 // decrement decreases the counter for the number of connections tracked for the
-// given IP.
+// given client.
 // It ensures that the counter does not go below zero.
-func (i *inFlightConn) decrement(ip string) {
+// The terminated connection is granted to the next client with queued connections, if any.
+func (i *inFlightConn) decrement(client string) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	if i.connections[ip] <= 0 {
+	if i.connections[client] <= 0 {
+		return
+	}
+
+	i.setConnections(client, i.connections[client]-1)
+
+	if len(i.rotation) == 0 {
+		i.served--
+		return
+	}
+
+	next := i.rotation[0]
+	granted := i.queued[next][0]
+
+	i.rotation = i.rotation[1:]
+	i.queued[next] = i.queued[next][1:]
+	// The client is granted its next queued connection once the other clients have been granted one.
+	if len(i.queued[next]) > 0 {
+		i.rotation = append(i.rotation, next)
+	} else {
+		delete(i.queued, next)
+	}
+
+	close(granted)
+}
+
+// withdraw removes the given queued connection of the client,
+// and the client from the rotation when it has no queued connections anymore.
+func (i *inFlightConn) withdraw(client string, granted chan struct{}) {
+	i.queued[client] = slices.DeleteFunc(i.queued[client], func(c chan struct{}) bool { return c == granted })
+	if len(i.queued[client]) > 0 {
 		return
 	}
 
-	i.connections[ip]--
+	delete(i.queued, client)
+	i.rotation = slices.DeleteFunc(i.rotation, func(c string) bool { return c == client })
+}
+
+// setConnections sets the number of connections of the given client, it must be called with the lock held.
+func (i *inFlightConn) setConnections(client string, connections int64) {
+	if connections > 0 {
+		i.connections[client] = connections
+	} else {
+		delete(i.connections, client)
+	}
+
+	if i.clients != nil {
+		i.clients.Set(float64(len(i.connections)))
+	}
+}
+
+// observeQueueWait observes the wait of a queued connection to the given service, granted after being queued at the given time.
+func (i *inFlightConn) observeQueueWait(service string, queuedAt time.Time) {
+	if i.queueWait != nil {
+		i.queueWait.With("service", service).ObserveFromStart(queuedAt)
+	}
 }
@@ -3,12 +3,18 @@ package inflightconn
 import (
 	"context"
 	"net"
+	"sync"
 	"testing"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
 )
 
 func TestInFlightConn_ServeTCP(t *testing.T) {
@@ -27,7 +33,7 @@ func TestInFlightConn_ServeTCP(t *testing.T) {
 		finishCh <- struct{}{}
 	})
 
-	middleware, err := New(context.Background(), next, dynamic.TCPInFlightConn{Amount: 1}, "foo")
+	middleware, err := New(context.Background(), next, dynamic.TCPInFlightConn{Amount: 1}, "foo", Metrics{})
 	require.NoError(t, err)
 
 	// The first connection should succeed and wait.
@@ -52,6 +58,165 @@ func TestInFlightConn_ServeTCP(t *testing.T) {
 	requireMessage(t, proceedCh)
 }
 
+func TestInFlightConn_fairQueuing(t *testing.T) {
+	served := make(chan string, 9)
+	release := make(chan struct{})
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served <- conn.RemoteAddr().String()
+		<-release
+	})
+
+	clientsGauge := &clientsGauge{}
+	config := dynamic.TCPInFlightConn{Amount: 6, TotalAmount: 2, QueueTimeout: ptypes.Duration(time.Minute)}
+	middleware, err := New(context.Background(), next, config, "foo", Metrics{Clients: clientsGauge})
+	require.NoError(t, err)
+
+	// Both clients contend for the connections, the first one opening more connections than the other.
+	clients := map[string]int{"127.0.0.1:9000": 6, "127.0.0.2:9000": 3}
+	for addr, count := range clients {
+		for range count {
+			go middleware.ServeTCP(fakeConn{addr: addr})
+		}
+	}
+
+	// The first connections are served, whatever their client, until the total amount is reached.
+	for range 2 {
+		select {
+		case <-served:
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for a served connection")
+		}
+	}
+
+	inFlight := middleware.(*inFlightConn)
+	var queued map[string]int
+	require.Eventually(t, func() bool {
+		inFlight.mu.Lock()
+		defer inFlight.mu.Unlock()
+
+		queued = make(map[string]int)
+		for client, conns := range inFlight.queued {
+			queued[client] = len(conns)
+		}
+
+		return queued["127.0.0.1"]+queued["127.0.0.2"] == 7
+	}, time.Second, 10*time.Millisecond)
+
+	// The clients holding only queued connections are counted.
+	clientsGauge.mu.Lock()
+	assert.InDelta(t, 2, clientsGauge.value, 0)
+	assert.Equal(t, []string{"middleware", "foo"}, clientsGauge.labelValues)
+	clientsGauge.mu.Unlock()
+
+	// While both clients have queued connections, the terminated connections are granted to each client in turn.
+	turns := 2 * min(queued["127.0.0.1"], queued["127.0.0.2"])
+
+	granted := make(map[string]int)
+	var previous string
+	for range turns {
+		release <- struct{}{}
+
+		select {
+		case addr := <-served:
+			assert.NotEqual(t, previous, addr)
+			previous = addr
+			granted[addr]++
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for a granted connection")
+		}
+	}
+
+	assert.Equal(t, map[string]int{"127.0.0.1:9000": turns / 2, "127.0.0.2:9000": turns / 2}, granted)
+
+	close(release)
+}
+
+func TestInFlightConn_queueTimeout(t *testing.T) {
+	proceedCh := make(chan struct{})
+	waitCh := make(chan struct{})
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		proceedCh <- struct{}{}
+		<-waitCh
+	})
+
+	config := dynamic.TCPInFlightConn{Amount: 2, TotalAmount: 1, QueueTimeout: ptypes.Duration(50 * time.Millisecond)}
+	middleware, err := New(context.Background(), next, config, "foo", Metrics{})
+	require.NoError(t, err)
+
+	go middleware.ServeTCP(fakeConn{addr: "127.0.0.1:9000"})
+	requireMessage(t, proceedCh)
+
+	// The connection of another client is queued, and closed once the queue timeout is reached.
+	closeCh := make(chan struct{})
+	go middleware.ServeTCP(fakeConn{addr: "127.0.0.2:9000", closeCh: closeCh})
+	requireMessage(t, closeCh)
+
+	inFlight := middleware.(*inFlightConn)
+	inFlight.mu.Lock()
+	assert.Empty(t, inFlight.queued)
+	assert.Empty(t, inFlight.rotation)
+	assert.Equal(t, map[string]int64{"127.0.0.1": 1}, inFlight.connections)
+	inFlight.mu.Unlock()
+
+	close(waitCh)
+}
+
+func TestInFlightConn_queueMetrics(t *testing.T) {
+	proceedCh := make(chan struct{})
+	waitCh := make(chan struct{})
+
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		proceedCh <- struct{}{}
+		<-waitCh
+	})
+
+	queueWait := &queueWaitHistogram{}
+	queueTimeouts := &testhelpers.CollectingCounter{}
+	config := dynamic.TCPInFlightConn{Amount: 2, TotalAmount: 1, QueueTimeout: ptypes.Duration(200 * time.Millisecond)}
+	middleware, err := New(context.Background(), next, config, "foo", Metrics{QueueWait: queueWait, QueueTimeouts: queueTimeouts})
+	require.NoError(t, err)
+
+	// The queue metrics are labeled by the service the connections are routed to.
+	attributes := &tcp.ConnAttributes{}
+	attributes.Set(tcp.ServiceAttribute, "bar@file")
+
+	go middleware.ServeTCP(fakeConn{addr: "127.0.0.1:9000", attributes: attributes})
+	requireMessage(t, proceedCh)
+
+	// The connection of another client is queued, and granted once the first connection terminates.
+	go middleware.ServeTCP(fakeConn{addr: "127.0.0.2:9000", attributes: attributes})
+
+	inFlight := middleware.(*inFlightConn)
+	require.Eventually(t, func() bool {
+		inFlight.mu.Lock()
+		defer inFlight.mu.Unlock()
+
+		return len(inFlight.queued) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	waitCh <- struct{}{}
+	requireMessage(t, proceedCh)
+
+	// The connection of a third client is queued, and closed once the queue timeout is reached.
+	closeCh := make(chan struct{})
+	go middleware.ServeTCP(fakeConn{addr: "127.0.0.3:9000", closeCh: closeCh, attributes: attributes})
+	requireMessage(t, closeCh)
+
+	inFlight.mu.Lock()
+	assert.InDelta(t, 1, queueTimeouts.CounterValue, 0)
+	assert.Equal(t, []string{"service", "bar@file"}, queueTimeouts.LastLabelValues)
+	inFlight.mu.Unlock()
+
+	queueWait.mu.Lock()
+	assert.Equal(t, 1, queueWait.samples)
+	assert.Equal(t, []string{"middleware", "foo", "service", "bar@file"}, queueWait.labelValues)
+	queueWait.mu.Unlock()
+
+	close(waitCh)
+}
+
 func requireMessage(t *testing.T, c chan struct{}) {
 	t.Helper()
 	select {
@@ -61,12 +226,67 @@ func requireMessage(t *testing.T, c chan struct{}) {
 	}
 }
 
+// clientsGauge records the last value set, and the last label values.
+type clientsGauge struct {
+	mu          sync.Mutex
+	value       float64
+	labelValues []string
+}
+
+func (g *clientsGauge) With(labelValues ...string) gokitmetrics.Gauge {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.labelValues = labelValues
+	return g
+}
+
+func (g *clientsGauge) Set(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.value = value
+}
+
+func (g *clientsGauge) Add(float64) {}
+
+// queueWaitHistogram records the number of queue waits observed, and the label values it was given.
+type queueWaitHistogram struct {
+	mu          sync.Mutex
+	samples     int
+	labelValues []string
+}
+
+func (h *queueWaitHistogram) With(labelValues ...string) metrics.ScalableHistogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.labelValues = append(h.labelValues, labelValues...)
+	return h
+}
+
+func (h *queueWaitHistogram) Observe(float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples++
+}
+
+func (h *queueWaitHistogram) ObserveFromStart(time.Time) {
+	h.Observe(0)
+}
+
 type fakeConn struct {
 	net.Conn
 
-	addr    string
-	wait    bool
-	closeCh chan struct{}
+	addr       string
+	wait       bool
+	closeCh    chan struct{}
+	attributes *tcp.ConnAttributes
+}
+
+func (c fakeConn) Attributes() *tcp.ConnAttributes {
+	return c.attributes
 }
 
 func (c fakeConn) RemoteAddr() net.Addr {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
@@ -18,12 +19,13 @@ const (
 // ipAllowLister is a middleware that provides Checks of the Requesting IP against a set of Allowlists.
 type ipAllowLister struct {
 	next        tcp.Handler
-	allowLister *ip.Checker
+	allowLister *ip.DecisionCache
 	name        string
 }
 
 // New builds a new TCP IPAllowLister given a list of CIDR-Strings to allow.
-func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPAllowList, name string) (tcp.Handler, error) {
+// The decisions are cached by source IP, the given counter, when set, counting the lookups in the cache.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPAllowList, name string, decisionLookups gokitmetrics.Counter) (tcp.Handler, error) {
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 
@@ -39,7 +41,7 @@ func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPAllowList, n
 	logger.Debug().Msgf("Setting up IPAllowLister with sourceRange: %s", config.SourceRange)
 
 	return &ipAllowLister{
-		allowLister: checker,
+		allowLister: ip.NewDecisionCache(checker, ip.DefaultDecisionCacheSize, decisionLookups),
 		next:        next,
 		name:        name,
 	}, nil
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
@@ -18,12 +19,13 @@ const (
 // ipWhiteLister is a middleware that provides Checks of the Requesting IP against a set of Whitelists.
 type ipWhiteLister struct {
 	next        tcp.Handler
-	whiteLister *ip.Checker
+	whiteLister *ip.DecisionCache
 	name        string
 }
 
 // New builds a new TCP IPWhiteLister given a list of CIDR-Strings to whitelist.
-func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPWhiteList, name string) (tcp.Handler, error) {
+// The decisions are cached by source IP, the given counter, when set, counting the lookups in the cache.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPWhiteList, name string, decisionLookups gokitmetrics.Counter) (tcp.Handler, error) {
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 
@@ -39,7 +41,7 @@ func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPWhiteList, n
 	logger.Debug().Msgf("Setting up IPWhiteLister with sourceRange: %s", config.SourceRange)
 
 	return &ipWhiteLister{
-		whiteLister: checker,
+		whiteLister: ip.NewDecisionCache(checker, ip.DefaultDecisionCacheSize, decisionLookups),
 		next:        next,
 		name:        name,
 	}, nil
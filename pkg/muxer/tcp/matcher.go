@@ -1,6 +1,8 @@
 package tcp
 
 import (
+	"bytes"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"strings"
@@ -14,10 +16,15 @@ import (
 var tcpFuncs = map[string]func(*matchersTree, ...string) error{
 	"ALPN":          expect1Parameter(alpn),
 	"ClientIP":      expect1Parameter(clientIP),
+	"ConnAttr":      expect2Parameters(connAttr),
 	"HostSNI":       expect1Parameter(hostSNI),
 	"HostSNIRegexp": expect1Parameter(hostSNIRegexp),
+	"Signature":     expect1Parameter(signature),
 }
 
+// MaxSignatureLength is the maximum number of bytes, at the start of the connections, the Signature matcher matches on.
+const MaxSignatureLength = 64
+
 func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
 	return func(route *matchersTree, s ...string) error {
 		if len(s) != 1 {
@@ -28,6 +35,16 @@ func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTre
 	}
 }
 
+func expect2Parameters(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
+	return func(route *matchersTree, s ...string) error {
+		if len(s) != 2 {
+			return fmt.Errorf("unexpected number of parameters; got %d, expected 2", len(s))
+		}
+
+		return fn(route, s...)
+	}
+}
+
 // alpn checks if any of the connection ALPN protocols matches one of the matcher protocols.
 func alpn(tree *matchersTree, protos ...string) error {
 	proto := protos[0]
@@ -55,8 +72,10 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 		return fmt.Errorf("initializing IP checker for ClientIP matcher: %w", err)
 	}
 
+	cache := tree.newIPDecisionCache(checker)
+
 	tree.matcher = func(meta ConnData) bool {
-		ok, err := checker.Contains(meta.remoteIP)
+		ok, err := cache.Contains(meta.remoteIP)
 		if err != nil {
 			log.Warn().Err(err).Msg("ClientIP matcher: could not match remote address")
 			return false
@@ -67,6 +86,56 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 	return nil
 }
 
+// connAttr checks if the connection attribute, set by the handlers which served the connection before the routing, matches the matcher value.
+func connAttr(tree *matchersTree, attr ...string) error {
+	key, value := attr[0], attr[1]
+
+	tree.matcher = func(meta ConnData) bool {
+		if meta.attributes == nil {
+			return false
+		}
+
+		attrValue, ok := meta.attributes.Get(key)
+		return ok && attrValue == value
+	}
+
+	return nil
+}
+
+// signature checks if the first bytes of the connection match the matcher signature,
+// either a hexadecimal prefix starting with 0x, or a regular expression.
+func signature(tree *matchersTree, signatures ...string) error {
+	sig := signatures[0]
+
+	if hexPrefix, ok := strings.CutPrefix(sig, "0x"); ok {
+		prefix, err := hex.DecodeString(hexPrefix)
+		if err != nil {
+			return fmt.Errorf("decoding Signature matcher hexadecimal prefix: %w", err)
+		}
+
+		if len(prefix) == 0 || len(prefix) > MaxSignatureLength {
+			return fmt.Errorf("invalid length for Signature matcher, %d bytes is not between 1 and %d bytes", len(prefix), MaxSignatureLength)
+		}
+
+		tree.matcher = func(meta ConnData) bool {
+			return bytes.HasPrefix(meta.peeked, prefix)
+		}
+
+		return nil
+	}
+
+	re, err := regexp.Compile(sig)
+	if err != nil {
+		return fmt.Errorf("compiling Signature matcher: %w", err)
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return re.Match(meta.peeked)
+	}
+
+	return nil
+}
+
 var hostOrIP = regexp.MustCompile(`^[[:alnum:]\.\-\:]+$`)
 
 // hostSNI checks if the SNI Host of the connection match the matcher host.
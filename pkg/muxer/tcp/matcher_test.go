@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -376,3 +377,204 @@ func Test_ALPN(t *testing.T) {
 		})
 	}
 }
+
+func Test_ConnAttr(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		rule       string
+		attributes map[string]string
+		expected   bool
+		buildErr   bool
+	}{
+		{
+			desc:     "Invalid ConnAttr matcher (missing value)",
+			rule:     "ConnAttr(`class`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ConnAttr matcher (empty name)",
+			rule:     "ConnAttr(``, `bulk`)",
+			buildErr: true,
+		},
+		{
+			desc:       "Matching attribute",
+			rule:       "ConnAttr(`class`, `bulk`)",
+			attributes: map[string]string{"class": "bulk"},
+			expected:   true,
+		},
+		{
+			desc:       "Attribute with another value",
+			rule:       "ConnAttr(`class`, `bulk`)",
+			attributes: map[string]string{"class": "interactive"},
+		},
+		{
+			desc: "Attribute not set",
+			rule: "ConnAttr(`class`, `bulk`)",
+		},
+		{
+			desc:     "Invalid ConnAttr matcher (empty value)",
+			rule:     "ConnAttr(`class`, ``)",
+			buildErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			attributes := &tcp.ConnAttributes{}
+			for key, value := range test.attributes {
+				attributes.Set(key, value)
+			}
+
+			handler, _ := muxer.Match(ConnData{attributes: attributes})
+			assert.Equal(t, test.expected, handler != nil)
+		})
+	}
+}
+
+func Test_ConnAttrSetByMiddleware(t *testing.T) {
+	muxer, err := NewMuxer()
+	require.NoError(t, err)
+
+	var routed string
+	err = muxer.AddRoute("ConnAttr(`class`, `bulk`)", "", 1, tcp.HandlerFunc(func(conn tcp.WriteCloser) { routed = "bulk" }))
+	require.NoError(t, err)
+	err = muxer.AddRoute("HostSNI(`*`)", "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) { routed = "default" }))
+	require.NoError(t, err)
+
+	router := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		connData, err := NewConnData("", conn, nil)
+		require.NoError(t, err)
+
+		handler, _ := muxer.Match(connData)
+		require.NotNil(t, handler)
+		handler.ServeTCP(conn)
+	})
+
+	classifier := func(next tcp.Handler) tcp.Handler {
+		return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+			if conn.RemoteAddr().String() == "10.0.0.1:1234" {
+				tcp.GetConnAttributes(conn).Set("class", "bulk")
+			}
+			next.ServeTCP(conn)
+		})
+	}
+
+	handler := classifier(router)
+
+	handler.ServeTCP(tcp.WithConnAttributes(&fakeConn{remoteAddr: fakeAddr{addr: "10.0.0.1:1234"}}))
+	assert.Equal(t, "bulk", routed)
+
+	handler.ServeTCP(tcp.WithConnAttributes(&fakeConn{remoteAddr: fakeAddr{addr: "10.0.0.2:1234"}}))
+	assert.Equal(t, "default", routed)
+
+	// Without attributes, the ConnAttr matcher never matches.
+	router.ServeTCP(&fakeConn{remoteAddr: fakeAddr{addr: "10.0.0.1:1234"}})
+	assert.Equal(t, "default", routed)
+}
+
+func Test_Signature(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		peeked   string
+		buildErr bool
+		match    bool
+	}{
+		{
+			desc:     "Invalid Signature matcher (empty hexadecimal prefix)",
+			rule:     "Signature(`0x`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid Signature matcher (invalid hexadecimal prefix)",
+			rule:     "Signature(`0x16z3`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid Signature matcher (hexadecimal prefix too long)",
+			rule:     "Signature(`0x" + strings.Repeat("00", MaxSignatureLength+1) + "`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid Signature matcher (invalid regexp)",
+			rule:     "Signature(`^SSH-(`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid Signature matcher (too many parameters)",
+			rule:     "Signature(`^SSH-`, `^HTTP`)",
+			buildErr: true,
+		},
+		{
+			desc:   "Matching hexadecimal prefix",
+			rule:   "Signature(`0x5353482d`)",
+			peeked: "SSH-2.0-OpenSSH_9.6\r\n",
+			match:  true,
+		},
+		{
+			desc:   "Not matching hexadecimal prefix",
+			rule:   "Signature(`0x160301`)",
+			peeked: "SSH-2.0-OpenSSH_9.6\r\n",
+		},
+		{
+			desc:   "Hexadecimal prefix longer than the peeked bytes",
+			rule:   "Signature(`0x5353482d322e30`)",
+			peeked: "SSH-",
+		},
+		{
+			desc:   "Matching regexp",
+			rule:   "Signature(`^SSH-2\\.0-`)",
+			peeked: "SSH-2.0-OpenSSH_9.6\r\n",
+			match:  true,
+		},
+		{
+			desc:   "Not matching regexp",
+			rule:   "Signature(`^SSH-`)",
+			peeked: "GET / HTTP/1.1\r\n",
+		},
+		{
+			desc:   "Regexp matching beyond the maximum signature length",
+			rule:   "Signature(`OpenSSH`)",
+			peeked: strings.Repeat("a", MaxSignatureLength) + "OpenSSH",
+		},
+		{
+			desc: "Nothing peeked",
+			rule: "Signature(`^SSH-`)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, muxer.MatchesSignature())
+
+			connData := ConnData{}
+			connData.SetPeeked(test.peeked)
+
+			handler, _ := muxer.Match(connData)
+			assert.Equal(t, test.match, handler != nil)
+		})
+	}
+}
@@ -26,12 +26,14 @@ func clientIPV2(tree *matchersTree, clientIPs ...string) error {
 		return fmt.Errorf("could not initialize IP Checker for \"ClientIP\" matcher: %w", err)
 	}
 
+	cache := tree.newIPDecisionCache(checker)
+
 	tree.matcher = func(meta ConnData) bool {
 		if meta.remoteIP == "" {
 			return false
 		}
 
-		ok, err := checker.Contains(meta.remoteIP)
+		ok, err := cache.Contains(meta.remoteIP)
 		if err != nil {
 			log.Warn().Err(err).Msg("ClientIP matcher: could not match remote address")
 			return false
@@ -3,10 +3,13 @@ package tcp
 import (
 	"fmt"
 	"net"
+	"slices"
 	"sort"
 	"strings"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 	"github.com/traefik/traefik/v3/pkg/types"
@@ -18,6 +21,9 @@ type ConnData struct {
 	serverName string
 	remoteIP   string
 	alpnProtos []string
+	attributes *tcp.ConnAttributes
+	// peeked are the first bytes of the connection, up to MaxSignatureLength.
+	peeked []byte
 }
 
 // NewConnData builds a connData struct from the given parameters.
@@ -36,14 +42,26 @@ func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (
 		serverName: types.CanonicalDomain(serverName),
 		remoteIP:   remoteIP,
 		alpnProtos: alpnProtos,
+		attributes: tcp.GetConnAttributes(conn),
 	}, nil
 }
 
+// SetPeeked sets the first bytes of the connection, matched by the Signature matcher.
+func (c *ConnData) SetPeeked(peeked string) {
+	if len(peeked) > MaxSignatureLength {
+		peeked = peeked[:MaxSignatureLength]
+	}
+
+	c.peeked = []byte(peeked)
+}
+
 // Muxer defines a muxer that handles TCP routing with rules.
 type Muxer struct {
 	routes   routes
 	parser   predicate.Parser
 	parserV2 predicate.Parser
+	// ipDecisionLookups, if set, counts the lookups in the caches of the ClientIP matchers decisions.
+	ipDecisionLookups gokitmetrics.Counter
 }
 
 // NewMuxer returns a TCP muxer.
@@ -86,6 +104,12 @@ func (m Muxer) Match(meta ConnData) (tcp.Handler, bool) {
 	return nil, false
 }
 
+// SetIPDecisionCacheLookups sets the counter of the lookups in the caches of the ClientIP matchers decisions,
+// of the routes added afterward.
+func (m *Muxer) SetIPDecisionCacheLookups(lookups gokitmetrics.Counter) {
+	m.ipDecisionLookups = lookups
+}
+
 // GetRulePriority computes the priority for a given rule.
 // The priority is calculated using the length of rule.
 // There is a special case where the HostSNI(`*`) has a priority of -1.
@@ -149,7 +173,7 @@ func (m *Muxer) AddRoute(rule string, syntax string, priority int, handler tcp.H
 
 	ruleTree := buildTree()
 
-	var matchers matchersTree
+	matchers := matchersTree{ipDecisionLookups: m.ipDecisionLookups}
 	err = matchers.addRule(ruleTree, matcherFuncs)
 	if err != nil {
 		return fmt.Errorf("error while adding rule %s: %w", rule, err)
@@ -164,6 +188,8 @@ func (m *Muxer) AddRoute(rule string, syntax string, priority int, handler tcp.H
 		handler:  handler,
 		matchers: matchers,
 		catchAll: catchAll,
+		sniOnly:  matchesSNIOnly(ruleTree),
+		peeking:  hasMatcher(ruleTree, "Signature"),
 		priority: priority,
 	}
 	m.routes = append(m.routes, newRoute)
@@ -178,9 +204,97 @@ func (m *Muxer) HasRoutes() bool {
 	return len(m.routes) > 0
 }
 
+// MatchesSNIOnly reports whether the routes only match on the server name (SNI) of the connections,
+// in which case the route matching a connection only depends on its SNI.
+func (m *Muxer) MatchesSNIOnly() bool {
+	for _, route := range m.routes {
+		if !route.sniOnly {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchesSignature reports whether any of the routes matches on the first bytes of the connections,
+// which then need to be peeked before matching.
+func (m *Muxer) MatchesSignature() bool {
+	for _, route := range m.routes {
+		if route.peeking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NeedsSignature reports whether the first bytes of the given connection have to be peeked to route it,
+// i.e. whether a route matching on them may match the connection, ahead of the first route matching it otherwise.
+func (m *Muxer) NeedsSignature(meta ConnData) bool {
+	for _, route := range m.routes {
+		if route.peeking {
+			if route.matchers.mayMatch(meta) {
+				return true
+			}
+
+			continue
+		}
+
+		if route.matchers.match(meta) {
+			return false
+		}
+	}
+
+	return false
+}
+
+// hasMatcher reports whether the given rule uses the named matcher.
+func hasMatcher(tree *rules.Tree, matcher string) bool {
+	if tree.RuleLeft != nil && tree.RuleRight != nil {
+		return hasMatcher(tree.RuleLeft, matcher) || hasMatcher(tree.RuleRight, matcher)
+	}
+
+	return strings.EqualFold(tree.Matcher, matcher)
+}
+
+// matchesSNIOnly reports whether the given rule only uses the HostSNI and HostSNIRegexp matchers.
+func matchesSNIOnly(tree *rules.Tree) bool {
+	if tree.RuleLeft != nil && tree.RuleRight != nil {
+		return matchesSNIOnly(tree.RuleLeft) && matchesSNIOnly(tree.RuleRight)
+	}
+
+	return strings.EqualFold(tree.Matcher, "HostSNI") || strings.EqualFold(tree.Matcher, "HostSNIRegexp")
+}
+
 // ParseHostSNI extracts the HostSNIs declared in a rule.
 // This is a first naive implementation used in TCP routing.
 func ParseHostSNI(rule string) ([]string, error) {
+	tree, err := parseRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.ParseMatchers([]string{"HostSNI"}), nil
+}
+
+// IsSNICatchAll reports whether the given rule matches the connections whatever their SNI,
+// i.e. it declares the HostSNI(`*`) catch-all, or neither HostSNI nor HostSNIRegexp matchers.
+func IsSNICatchAll(rule string) (bool, error) {
+	tree, err := parseRule(rule)
+	if err != nil {
+		return false, err
+	}
+
+	hostSNIs := tree.ParseMatchers([]string{"HostSNI"})
+	if slices.Contains(hostSNIs, "*") {
+		return true, nil
+	}
+
+	return len(hostSNIs) == 0 && len(tree.ParseMatchers([]string{"HostSNIRegexp"})) == 0, nil
+}
+
+// parseRule parses the given rule into its tree.
+func parseRule(rule string) (*rules.Tree, error) {
 	var matchers []string
 	for matcher := range tcpFuncs {
 		matchers = append(matchers, matcher)
@@ -204,7 +318,7 @@ func ParseHostSNI(rule string) ([]string, error) {
 		return nil, fmt.Errorf("error while parsing rule %s", rule)
 	}
 
-	return buildTree().ParseMatchers([]string{"HostSNI"}), nil
+	return buildTree(), nil
 }
 
 // routes implements sort.Interface.
@@ -228,6 +342,10 @@ type route struct {
 	handler tcp.Handler
 	// catchAll indicates whether the route rule has exactly the catchAll value (HostSNI(`*`)).
 	catchAll bool
+	// sniOnly indicates whether the route rule only matches on the SNI.
+	sniOnly bool
+	// peeking indicates whether the route rule matches on the first bytes of the connection.
+	peeking bool
 	// priority is used to disambiguate between two (or more) rules that would
 	// all match for a given request.
 	// Computed from the matching rule length, if not user-set.
@@ -240,11 +358,15 @@ type matchersTree struct {
 	// If matcher is not nil, it means that this matcherTree is a leaf of the tree.
 	// It is therefore mutually exclusive with left and right.
 	matcher func(ConnData) bool
+	// peeking reports whether the matcher matches on the first bytes of the connection.
+	peeking bool
 	// operator to combine the evaluation of left and right leaves.
 	operator string
 	// Mutually exclusive with matcher.
 	left  *matchersTree
 	right *matchersTree
+	// ipDecisionLookups, if set, counts the lookups in the caches of the ClientIP matchers decisions.
+	ipDecisionLookups gokitmetrics.Counter
 }
 
 func (m *matchersTree) match(meta ConnData) bool {
@@ -270,19 +392,51 @@ func (m *matchersTree) match(meta ConnData) bool {
 	}
 }
 
+// mayMatch reports whether the connection may match, once its first bytes are peeked:
+// the matchers matching on them, whatever their negation, are assumed to match.
+func (m *matchersTree) mayMatch(meta ConnData) bool {
+	if m == nil {
+		return false
+	}
+
+	if m.matcher != nil {
+		return m.peeking || m.matcher(meta)
+	}
+
+	switch m.operator {
+	case "or":
+		return m.left.mayMatch(meta) || m.right.mayMatch(meta)
+	case "and":
+		return m.left.mayMatch(meta) && m.right.mayMatch(meta)
+	default:
+		return false
+	}
+}
+
+// newIPDecisionCache returns a cache of the decisions of the given checker of a ClientIP matcher.
+// As the route matchers are rebuilt on configuration change, so are the caches.
+func (m *matchersTree) newIPDecisionCache(checker *ip.Checker) *ip.DecisionCache {
+	var lookups gokitmetrics.Counter
+	if m.ipDecisionLookups != nil {
+		lookups = m.ipDecisionLookups.With("type", "ClientIP")
+	}
+
+	return ip.NewDecisionCache(checker, ip.DefaultDecisionCacheSize, lookups)
+}
+
 type matcherFuncs map[string]func(*matchersTree, ...string) error
 
 func (m *matchersTree) addRule(rule *rules.Tree, funcs matcherFuncs) error {
 	switch rule.Matcher {
 	case "and", "or":
 		m.operator = rule.Matcher
-		m.left = &matchersTree{}
+		m.left = &matchersTree{ipDecisionLookups: m.ipDecisionLookups}
 		err := m.left.addRule(rule.RuleLeft, funcs)
 		if err != nil {
 			return err
 		}
 
-		m.right = &matchersTree{}
+		m.right = &matchersTree{ipDecisionLookups: m.ipDecisionLookups}
 		return m.right.addRule(rule.RuleRight, funcs)
 	default:
 		err := rules.CheckRule(rule)
@@ -295,6 +449,8 @@ func (m *matchersTree) addRule(rule *rules.Tree, funcs matcherFuncs) error {
 			return err
 		}
 
+		m.peeking = strings.EqualFold(rule.Matcher, "Signature")
+
 		if rule.Not {
 			matcherFunc := m.matcher
 			m.matcher = func(meta ConnData) bool {
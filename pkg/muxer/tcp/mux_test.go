@@ -2,9 +2,11 @@ package tcp
 
 import (
 	"net"
+	"strings"
 	"testing"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -387,6 +389,59 @@ func TestParseHostSNI(t *testing.T) {
 	}
 }
 
+func TestIsSNICatchAll(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		expression    string
+		expected      bool
+		errorExpected bool
+	}{
+		{
+			desc:          "Unknown rule",
+			expression:    "Unknown(`example.com`)",
+			errorExpected: true,
+		},
+		{
+			desc:       "HostSNI rule",
+			expression: "HostSNI(`example.com`)",
+		},
+		{
+			desc:       "HostSNIRegexp rule",
+			expression: "HostSNIRegexp(`^.+\\.example\\.com$`)",
+		},
+		{
+			desc:       "HostSNI catch-all rule",
+			expression: "HostSNI(`*`)",
+			expected:   true,
+		},
+		{
+			desc:       "HostSNI catch-all rule and another rule",
+			expression: "HostSNI(`*`) && ClientIP(`10.0.0.0/8`)",
+			expected:   true,
+		},
+		{
+			desc:       "No HostSNI rule",
+			expression: "ClientIP(`10.0.0.0/8`)",
+			expected:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			catchAll, err := IsSNICatchAll(test.expression)
+			if test.errorExpected {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, catchAll)
+		})
+	}
+}
+
 func Test_Priority(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -490,6 +545,167 @@ func TestGetRulePriority(t *testing.T) {
 	}
 }
 
+func TestMatchesSNIOnly(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rules    []string
+		expected bool
+	}{
+		{
+			desc:     "no routes",
+			expected: true,
+		},
+		{
+			desc:     "HostSNI and HostSNIRegexp rules",
+			rules:    []string{"HostSNI(`foo.example.com`) || HostSNI(`bar.example.com`)", "HostSNIRegexp(`^.+\\.example\\.org$`)", "!HostSNI(`baz.example.com`)"},
+			expected: true,
+		},
+		{
+			desc:     "rule matching on the client IP",
+			rules:    []string{"HostSNI(`foo.example.com`)", "HostSNI(`bar.example.com`) && ClientIP(`10.0.0.0/8`)"},
+			expected: false,
+		},
+		{
+			desc:     "rule matching on the ALPN protocols",
+			rules:    []string{"ALPN(`h2`)"},
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			for _, rule := range test.rules {
+				err = muxer.AddRoute(rule, "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, muxer.MatchesSNIOnly())
+		})
+	}
+}
+
+func TestNeedsSignature(t *testing.T) {
+	type route struct {
+		rule     string
+		priority int
+	}
+
+	testCases := []struct {
+		desc     string
+		routes   []route
+		expected bool
+	}{
+		{
+			desc: "no Signature rules",
+			routes: []route{
+				{rule: "ClientIP(`10.0.0.0/8`)"},
+			},
+			expected: false,
+		},
+		{
+			desc: "Signature rule",
+			routes: []route{
+				{rule: "Signature(`^SSH-`)"},
+			},
+			expected: true,
+		},
+		{
+			desc: "Signature rule not matching the client IP",
+			routes: []route{
+				{rule: "Signature(`^SSH-`) && ClientIP(`192.168.0.0/16`)"},
+				{rule: "HostSNI(`*`)", priority: 1},
+			},
+			expected: false,
+		},
+		{
+			desc: "negated Signature rule matching the client IP",
+			routes: []route{
+				{rule: "!Signature(`^SSH-`) && ClientIP(`10.0.0.0/8`)"},
+			},
+			expected: true,
+		},
+		{
+			desc: "Signature rule behind a rule matching the connection",
+			routes: []route{
+				{rule: "ClientIP(`10.0.0.0/8`)", priority: 2},
+				{rule: "Signature(`^SSH-`)", priority: 1},
+			},
+			expected: false,
+		},
+		{
+			desc: "Signature rule ahead of a rule matching the connection",
+			routes: []route{
+				{rule: "ClientIP(`10.0.0.0/8`)", priority: 1},
+				{rule: "Signature(`^SSH-`)", priority: 2},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			for _, route := range test.routes {
+				err = muxer.AddRoute(route.rule, "", route.priority, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expected, muxer.NeedsSignature(ConnData{remoteIP: "10.0.0.1"}))
+		})
+	}
+}
+
+// labelsCounter counts the added values by label values.
+type labelsCounter struct {
+	values map[string]float64
+	labels []string
+}
+
+func (c labelsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	c.labels = append(append([]string(nil), c.labels...), labelValues...)
+	return c
+}
+
+func (c labelsCounter) Add(delta float64) {
+	c.values[strings.Join(c.labels, ",")] += delta
+}
+
+func TestClientIPDecisionCache(t *testing.T) {
+	muxer, err := NewMuxer()
+	require.NoError(t, err)
+
+	lookups := labelsCounter{values: make(map[string]float64)}
+	muxer.SetIPDecisionCacheLookups(lookups)
+
+	err = muxer.AddRoute("ClientIP(`10.0.0.0/24`)", "", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+	require.NoError(t, err)
+
+	for _, remoteAddr := range []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.1.1:1000", "10.0.1.1:1001", "10.0.0.1:1002"} {
+		conn := &fakeConn{remoteAddr: fakeAddr{addr: remoteAddr}}
+
+		connData, err := NewConnData("", conn, nil)
+		require.NoError(t, err)
+
+		handler, _ := muxer.Match(connData)
+		assert.Equal(t, strings.HasPrefix(remoteAddr, "10.0.0."), handler != nil, remoteAddr)
+	}
+
+	// The decisions are cached by source IP, whatever the source port.
+	assert.Equal(t, map[string]float64{
+		"type,ClientIP,result,hit":  3,
+		"type,ClientIP,result,miss": 2,
+	}, lookups.values)
+}
+
 type fakeConn struct {
 	call       map[string]int
 	remoteAddr net.Addr
@@ -17,8 +17,10 @@ import (
 	"github.com/traefik/traefik/v3/pkg/types"
 	"github.com/traefik/traefik/v3/pkg/version"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	kerror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	kinformers "k8s.io/client-go/informers"
 	kclientset "k8s.io/client-go/kubernetes"
@@ -47,7 +49,10 @@ type Client interface {
 	GetService(namespace, name string) (*corev1.Service, bool, error)
 	GetSecret(namespace, name string) (*corev1.Secret, bool, error)
 	GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error)
+	GetEndpointSlicesForService(namespace, serviceName string) ([]*discoveryv1.EndpointSlice, error)
 	GetNodes() ([]*corev1.Node, bool, error)
+	GetPod(namespace, name string) (*corev1.Pod, bool, error)
+	GetConfigMap(namespace, name string) (*corev1.ConfigMap, bool, error)
 }
 
 // TODO: add tests for the clientWrapper (and its methods) itself.
@@ -59,11 +64,18 @@ type clientWrapper struct {
 	factoriesCrd        map[string]traefikinformers.SharedInformerFactory
 	factoriesKube       map[string]kinformers.SharedInformerFactory
 	factoriesSecret     map[string]kinformers.SharedInformerFactory
+	// factoryHotpatch watches the hotpatch ConfigMap, when set.
+	factoryHotpatch kinformers.SharedInformerFactory
 
 	labelSelector string
+	listChunkSize int64
 
 	isNamespaceAll    bool
 	watchedNamespaces []string
+
+	// hotpatchNamespace and hotpatchName identify the hotpatch ConfigMap, which is watched whatever the watched namespaces.
+	hotpatchNamespace string
+	hotpatchName      string
 }
 
 func createClientFromConfig(c *rest.Config) (*clientWrapper, error) {
@@ -163,16 +175,8 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 
 	c.watchedNamespaces = namespaces
 
-	notOwnedByHelm := func(opts *metav1.ListOptions) {
-		opts.LabelSelector = "owner!=helm"
-	}
-
-	matchesLabelSelector := func(opts *metav1.ListOptions) {
-		opts.LabelSelector = c.labelSelector
-	}
-
 	for _, ns := range namespaces {
-		factoryCrd := traefikinformers.NewSharedInformerFactoryWithOptions(c.csCrd, resyncPeriod, traefikinformers.WithNamespace(ns), traefikinformers.WithTweakListOptions(matchesLabelSelector))
+		factoryCrd := traefikinformers.NewSharedInformerFactoryWithOptions(c.csCrd, resyncPeriod, traefikinformers.WithNamespace(ns), traefikinformers.WithTweakListOptions(c.tweakCRDListOptions))
 		_, err := factoryCrd.Traefik().V1alpha1().IngressRoutes().Informer().AddEventHandler(eventHandler)
 		if err != nil {
 			return nil, err
@@ -214,7 +218,7 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 			return nil, err
 		}
 
-		factoryKube := kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, kinformers.WithNamespace(ns))
+		factoryKube := kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, kinformers.WithNamespace(ns), kinformers.WithTweakListOptions(c.tweakKubeListOptions))
 		_, err = factoryKube.Core().V1().Services().Informer().AddEventHandler(eventHandler)
 		if err != nil {
 			return nil, err
@@ -223,8 +227,16 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		if err != nil {
 			return nil, err
 		}
+		_, err = factoryKube.Discovery().V1().EndpointSlices().Informer().AddEventHandler(eventHandler)
+		if err != nil {
+			return nil, err
+		}
+		_, err = factoryKube.Core().V1().Pods().Informer().AddEventHandler(eventHandler)
+		if err != nil {
+			return nil, err
+		}
 
-		factorySecret := kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, kinformers.WithNamespace(ns), kinformers.WithTweakListOptions(notOwnedByHelm))
+		factorySecret := kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, kinformers.WithNamespace(ns), kinformers.WithTweakListOptions(c.tweakSecretListOptions))
 		_, err = factorySecret.Core().V1().Secrets().Informer().AddEventHandler(eventHandler)
 		if err != nil {
 			return nil, err
@@ -241,12 +253,23 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		return nil, err
 	}
 
+	if c.hotpatchName != "" {
+		c.factoryHotpatch = kinformers.NewSharedInformerFactoryWithOptions(c.csKube, resyncPeriod, kinformers.WithNamespace(c.hotpatchNamespace), kinformers.WithTweakListOptions(c.tweakHotpatchListOptions))
+		_, err = c.factoryHotpatch.Core().V1().ConfigMaps().Informer().AddEventHandler(eventHandler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, ns := range namespaces {
 		c.factoriesCrd[ns].Start(stopCh)
 		c.factoriesKube[ns].Start(stopCh)
 		c.factoriesSecret[ns].Start(stopCh)
 	}
 	c.factoryClusterScope.Start(stopCh)
+	if c.factoryHotpatch != nil {
+		c.factoryHotpatch.Start(stopCh)
+	}
 
 	for _, ns := range namespaces {
 		for t, ok := range c.factoriesCrd[ns].WaitForCacheSync(stopCh) {
@@ -274,9 +297,44 @@ func (c *clientWrapper) WatchAll(namespaces []string, stopCh <-chan struct{}) (<
 		}
 	}
 
+	if c.factoryHotpatch != nil {
+		for t, ok := range c.factoryHotpatch.WaitForCacheSync(stopCh) {
+			if !ok {
+				return nil, fmt.Errorf("timed out waiting for controller caches to sync %s in namespace %q", t.String(), c.hotpatchNamespace)
+			}
+		}
+	}
+
 	return eventCh, nil
 }
 
+// tweakCRDListOptions tweaks the list options of the Traefik resources informers.
+func (c *clientWrapper) tweakCRDListOptions(opts *metav1.ListOptions) {
+	opts.LabelSelector = c.labelSelector
+	c.tweakKubeListOptions(opts)
+}
+
+// tweakKubeListOptions tweaks the list options of the Kubernetes resources informers.
+func (c *clientWrapper) tweakKubeListOptions(opts *metav1.ListOptions) {
+	if c.listChunkSize > 0 {
+		opts.Limit = c.listChunkSize
+	}
+}
+
+// tweakSecretListOptions tweaks the list options of the Secrets informers,
+// ignoring the Secrets owned by Helm.
+func (c *clientWrapper) tweakSecretListOptions(opts *metav1.ListOptions) {
+	opts.LabelSelector = "owner!=helm"
+	c.tweakKubeListOptions(opts)
+}
+
+// tweakHotpatchListOptions tweaks the list options of the hotpatch ConfigMap informer,
+// only watching the hotpatch ConfigMap in its namespace.
+func (c *clientWrapper) tweakHotpatchListOptions(opts *metav1.ListOptions) {
+	opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", c.hotpatchName).String()
+	c.tweakKubeListOptions(opts)
+}
+
 func (c *clientWrapper) GetIngressRoutes() []*traefikv1alpha1.IngressRoute {
 	var result []*traefikv1alpha1.IngressRoute
 
@@ -455,6 +513,18 @@ func (c *clientWrapper) GetEndpoints(namespace, name string) (*corev1.Endpoints,
 	return endpoint, exist, err
 }
 
+// GetEndpointSlicesForService returns the EndpointSlices of the named service from the given namespace.
+func (c *clientWrapper) GetEndpointSlicesForService(namespace, serviceName string) ([]*discoveryv1.EndpointSlice, error) {
+	if !c.isWatchedNamespace(namespace) {
+		return nil, fmt.Errorf("failed to get endpointslices for service %s/%s: namespace is not within watched namespaces", namespace, serviceName)
+	}
+
+	// The EndpointSlices are bound to their service by the kubernetes.io/service-name label.
+	serviceSelector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+
+	return c.factoriesKube[c.lookupNamespace(namespace)].Discovery().V1().EndpointSlices().Lister().EndpointSlices(namespace).List(serviceSelector)
+}
+
 // GetSecret returns the named secret from the given namespace.
 func (c *clientWrapper) GetSecret(namespace, name string) (*corev1.Secret, bool, error) {
 	if !c.isWatchedNamespace(namespace) {
@@ -472,6 +542,29 @@ func (c *clientWrapper) GetNodes() ([]*corev1.Node, bool, error) {
 	return nodes, exist, err
 }
 
+// GetPod returns the named pod from the given namespace.
+func (c *clientWrapper) GetPod(namespace, name string) (*corev1.Pod, bool, error) {
+	if !c.isWatchedNamespace(namespace) {
+		return nil, false, fmt.Errorf("failed to get pod %s/%s: namespace is not within watched namespaces", namespace, name)
+	}
+
+	pod, err := c.factoriesKube[c.lookupNamespace(namespace)].Core().V1().Pods().Lister().Pods(namespace).Get(name)
+	exist, err := translateNotFoundError(err)
+	return pod, exist, err
+}
+
+// GetConfigMap returns the named ConfigMap from the given namespace,
+// only the hotpatch ConfigMap being watched.
+func (c *clientWrapper) GetConfigMap(namespace, name string) (*corev1.ConfigMap, bool, error) {
+	if c.factoryHotpatch == nil || namespace != c.hotpatchNamespace || name != c.hotpatchName {
+		return nil, false, fmt.Errorf("failed to get configmap %s/%s: only the hotpatch ConfigMap is watched", namespace, name)
+	}
+
+	configMap, err := c.factoryHotpatch.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace).Get(name)
+	exist, err := translateNotFoundError(err)
+	return configMap, exist, err
+}
+
 // lookupNamespace returns the lookup namespace key for the given namespace.
 // When listening on all namespaces, it returns the client-go identifier ("")
 // for all-namespaces. Otherwise, it returns the given namespace.
@@ -1,6 +1,7 @@
 package crd
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -63,3 +64,90 @@ func TestClientIgnoresHelmOwnedSecrets(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, found)
 }
+
+func TestClientListChunkSize(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		listChunkSize int64
+		expected      int64
+	}{
+		{
+			desc: "Client default",
+		},
+		{
+			desc:          "Configured chunk size",
+			listChunkSize: 250,
+			expected:      250,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClientImpl(kubefake.NewSimpleClientset(), traefikcrdfake.NewSimpleClientset())
+			client.labelSelector = "app=traefik"
+			client.listChunkSize = test.listChunkSize
+
+			opts := metav1.ListOptions{}
+			client.tweakCRDListOptions(&opts)
+			assert.Equal(t, test.expected, opts.Limit)
+			assert.Equal(t, "app=traefik", opts.LabelSelector)
+
+			opts = metav1.ListOptions{}
+			client.tweakKubeListOptions(&opts)
+			assert.Equal(t, test.expected, opts.Limit)
+			assert.Empty(t, opts.LabelSelector)
+
+			opts = metav1.ListOptions{}
+			client.tweakSecretListOptions(&opts)
+			assert.Equal(t, test.expected, opts.Limit)
+			assert.Equal(t, "owner!=helm", opts.LabelSelector)
+		})
+	}
+}
+
+func TestNewK8sClientInvalidListChunkSize(t *testing.T) {
+	for _, size := range []int64{-1, minListChunkSize - 1, maxListChunkSize + 1} {
+		p := Provider{ListChunkSize: size}
+
+		_, err := p.newK8sClient(context.Background())
+		assert.Error(t, err)
+	}
+}
+
+func TestNewK8sClientInvalidEndpointConditions(t *testing.T) {
+	for _, conditions := range [][]string{{"Terminating"}, {"Ready", "ready"}} {
+		p := Provider{EndpointConditions: conditions}
+
+		_, err := p.newK8sClient(context.Background())
+		assert.Error(t, err)
+	}
+}
+
+func TestNewK8sClientInvalidEndpointSource(t *testing.T) {
+	testCases := []struct {
+		source            string
+		endpointsFallback bool
+	}{
+		{source: "EndpointSlices"},
+		{source: endpointSourceEndpoints, endpointsFallback: true},
+		{source: endpointSourceEndpointSlices, endpointsFallback: true},
+	}
+
+	for _, test := range testCases {
+		p := Provider{EndpointSource: test.source, EndpointsFallback: test.endpointsFallback}
+
+		_, err := p.newK8sClient(context.Background())
+		assert.Error(t, err)
+	}
+}
+
+func TestNewK8sClientInvalidHotpatchConfigMap(t *testing.T) {
+	for _, hotpatchConfigMap := range []string{"hotpatch", "/hotpatch", "default/", "default/hotpatch/foo"} {
+		p := Provider{HotpatchConfigMap: hotpatchConfigMap, MaxConcurrentLookups: defaultMaxConcurrentLookups}
+
+		_, err := p.newK8sClient(context.Background())
+		assert.ErrorContains(t, err, "invalid hotpatch ConfigMap")
+	}
+}
@@ -0,0 +1,73 @@
+package crd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// externalNameLookupTimeout is the timeout of the resolution of an ExternalName target.
+const externalNameLookupTimeout = 5 * time.Second
+
+// errExternalNameLookup is wrapped by the errors of the ExternalName targets which cannot be resolved,
+// when their resolution failure fails the routes.
+var errExternalNameLookup = errors.New("resolving externalName")
+
+// ExternalNameLookup configures the resolution of the hostnames targeted by the ExternalName services of the TCP services,
+// each time the configuration is loaded, instead of when dialing the servers.
+type ExternalNameLookup struct {
+	FailRoute bool `description:"Defines whether a resolution failure fails the routes of the service, instead of keeping its last resolved addresses." json:"failRoute,omitempty" toml:"failRoute,omitempty" yaml:"failRoute,omitempty" export:"true"`
+}
+
+// resolveExternalName returns the addresses of the given ExternalName target.
+// When the resolution fails, the last resolved addresses of the target are returned,
+// or the target itself when it has never been resolved, unless the FailRoute option is set.
+func (p *Provider) resolveExternalName(ctx context.Context, externalName string) ([]string, error) {
+	if net.ParseIP(externalName) != nil {
+		return []string{externalName}, nil
+	}
+
+	lookupHost := p.lookupHost
+	if lookupHost == nil {
+		lookupHost = net.DefaultResolver.LookupHost
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, externalNameLookupTimeout)
+	defer cancel()
+
+	addresses, err := lookupHost(lookupCtx, externalName)
+	if err == nil && len(addresses) == 0 {
+		err = errors.New("no addresses found")
+	}
+
+	if err == nil {
+		// The addresses are sorted not to generate a different configuration on each resolution.
+		slices.Sort(addresses)
+
+		if p.externalNameAddresses == nil {
+			p.externalNameAddresses = make(map[string][]string)
+		}
+		p.externalNameAddresses[externalName] = addresses
+
+		return addresses, nil
+	}
+
+	logger := log.Ctx(ctx).With().Str("externalName", externalName).Err(err).Logger()
+
+	if p.ExternalNameLookup.FailRoute {
+		return nil, fmt.Errorf("%w %q: %w", errExternalNameLookup, externalName, err)
+	}
+
+	if lastAddresses, ok := p.externalNameAddresses[externalName]; ok {
+		logger.Error().Strs("addresses", lastAddresses).Msg("Cannot resolve the ExternalName target, keeping its last resolved addresses")
+		return lastAddresses, nil
+	}
+
+	logger.Error().Msg("Cannot resolve the ExternalName target, which has never been resolved, it is resolved when dialing the servers")
+	return []string{externalName}, nil
+}
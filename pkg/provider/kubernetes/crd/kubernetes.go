@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -41,6 +42,9 @@ import (
 const (
 	annotationKubernetesIngressClass = "kubernetes.io/ingress.class"
 	traefikDefaultIngressClass       = "traefik"
+
+	// annotationLogLevel overrides the log level of the messages about the annotated object.
+	annotationLogLevel = "traefik.io/log-level"
 )
 
 const (
@@ -48,6 +52,52 @@ const (
 	providerNamespaceSeparator = "@"
 )
 
+// defaultMaxHostSNIs is the default maximum number of HostSNI values in an IngressRouteTCP route rule.
+const defaultMaxHostSNIs = 100
+
+// defaultMaxConcurrentLookups is the default maximum number of concurrent Service and endpoints lookups during a sync.
+const defaultMaxConcurrentLookups = 10
+
+// EndpointSlice endpoint conditions accepted by the EndpointConditions option.
+const (
+	endpointConditionReady   = "Ready"
+	endpointConditionServing = "Serving"
+)
+
+// Sources of the endpoints of the TCP services accepted by the EndpointSource option.
+const (
+	endpointSourceAuto           = "auto"
+	endpointSourceEndpoints      = "endpoints"
+	endpointSourceEndpointSlices = "endpointslices"
+)
+
+// Resolutions of the conflicting service options accepted by the ServiceOptionsConflict option.
+const (
+	serviceOptionsConflictReject       = "reject"
+	serviceOptionsConflictFirstService = "firstService"
+)
+
+// Handling of the TLS terminated TCP routes matching any SNI accepted by the TerminatedCatchAll option.
+const terminatedCatchAllReject = "reject"
+
+// Resolutions of the HostSNIs claimed by both TLS passthrough and TLS terminated TCP routes accepted by the MixedTLSModeConflict option.
+const (
+	mixedTLSModeConflictPassthrough = "passthrough"
+	mixedTLSModeConflictTerminate   = "terminate"
+)
+
+// Handling of the IngressRouteTCPs having a generateName and no name accepted by the GenerateNameOnly option.
+const generateNameOnlySkip = "skip"
+
+// Handling of the routes referencing a TLSOption of a disallowed namespace accepted by the DisallowedTLSOption option.
+const disallowedTLSOptionDefault = "default"
+
+// Bounds of the ListChunkSize option.
+const (
+	minListChunkSize = 10
+	maxListChunkSize = 10000
+)
+
 // Provider holds configurations of the provider.
 type Provider struct {
 	Endpoint                  string              `description:"Kubernetes server endpoint (required for external cluster client)." json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty"`
@@ -61,12 +111,64 @@ type Provider struct {
 	ThrottleDuration          ptypes.Duration     `description:"Ingress refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
 	AllowEmptyServices        bool                `description:"Allow the creation of services without endpoints." json:"allowEmptyServices,omitempty" toml:"allowEmptyServices,omitempty" yaml:"allowEmptyServices,omitempty" export:"true"`
 	NativeLBByDefault         bool                `description:"Defines whether to use Native Kubernetes load-balancing mode by default." json:"nativeLBByDefault,omitempty" toml:"nativeLBByDefault,omitempty" yaml:"nativeLBByDefault,omitempty" export:"true"`
+	ListChunkSize             int64               `description:"Defines the maximum number of resources returned by each list request made to the Kubernetes API, zero means the client default." json:"listChunkSize,omitempty" toml:"listChunkSize,omitempty" yaml:"listChunkSize,omitempty" export:"true"`
+	PoolTransitionEvents      bool                `description:"Defines whether to log an event when the servers pool of a TCP router becomes empty, or is no longer empty." json:"poolTransitionEvents,omitempty" toml:"poolTransitionEvents,omitempty" yaml:"poolTransitionEvents,omitempty" export:"true"`
+	ZeroWeightFallback        bool                `description:"Defines whether TCP routes whose services all have a zero weight fall back to equal weighting instead of being rejected." json:"zeroWeightFallback,omitempty" toml:"zeroWeightFallback,omitempty" yaml:"zeroWeightFallback,omitempty" export:"true"`
+	NotReadyEndpointsFallback bool                `description:"Defines whether the not ready endpoints of a TCP service are used as a last resort when none of its endpoints is ready." json:"notReadyEndpointsFallback,omitempty" toml:"notReadyEndpointsFallback,omitempty" yaml:"notReadyEndpointsFallback,omitempty" export:"true"`
+	MaxHostSNIs               int                 `description:"Defines the maximum number of HostSNI values in an IngressRouteTCP route rule, zero means no limit." json:"maxHostSNIs,omitempty" toml:"maxHostSNIs,omitempty" yaml:"maxHostSNIs,omitempty" export:"true"`
+	TopologyEvents            bool                `description:"Defines whether to log an event describing the topology of a TCP router, from its entry points to its backends, when it changes." json:"topologyEvents,omitempty" toml:"topologyEvents,omitempty" yaml:"topologyEvents,omitempty" export:"true"`
+	TopologyFile              string              `description:"Defines the file the topology of the TCP routers, from their entry points to their backends, is exported to as JSON each time it changes." json:"topologyFile,omitempty" toml:"topologyFile,omitempty" yaml:"topologyFile,omitempty" export:"true"`
+	ExternalNameAllowList     []string            `description:"Defines the CIDRs and hostnames the ExternalName services of TCP services are allowed to target, empty means any target is allowed." json:"externalNameAllowList,omitempty" toml:"externalNameAllowList,omitempty" yaml:"externalNameAllowList,omitempty" export:"true"`
+	EndpointConditions        []string            `description:"Defines the EndpointSlice conditions (Ready, Serving) of which one must be true for an endpoint of a TCP service to be used, defaults to Ready." json:"endpointConditions,omitempty" toml:"endpointConditions,omitempty" yaml:"endpointConditions,omitempty" export:"true"`
+	EndpointsFallback         bool                `description:"Defines whether the Endpoints of a TCP service are used, with a warning, when its EndpointSlices have no ready address while its Endpoints have some." json:"endpointsFallback,omitempty" toml:"endpointsFallback,omitempty" yaml:"endpointsFallback,omitempty" export:"true"`
+	EndpointSource            string              `description:"Defines the source of the endpoints of the TCP services: auto uses the EndpointSlices of a Service when it has some and its Endpoints otherwise, endpoints only uses the Endpoints, endpointslices only uses the EndpointSlices." json:"endpointSource,omitempty" toml:"endpointSource,omitempty" yaml:"endpointSource,omitempty" export:"true"`
+	ServiceWeightAnnotation   string              `description:"Defines the annotation of the Kubernetes Services whose value sets the weight of the TCP services targeting them, when no weight is set on the service reference." json:"serviceWeightAnnotation,omitempty" toml:"serviceWeightAnnotation,omitempty" yaml:"serviceWeightAnnotation,omitempty" export:"true"`
+	ServiceOptionsConflict    string              `description:"Defines how the conflicting connection options of the services of a TCP route are resolved: unset keeps the options of each service with a warning, reject rejects the route, firstService applies the options of the first service of the route." json:"serviceOptionsConflict,omitempty" toml:"serviceOptionsConflict,omitempty" yaml:"serviceOptionsConflict,omitempty" export:"true"`
+	TerminatedCatchAll        string              `description:"Defines how the TLS terminated TCP routes matching any SNI are handled: unset serves them the default certificate with a warning, reject rejects them." json:"terminatedCatchAll,omitempty" toml:"terminatedCatchAll,omitempty" yaml:"terminatedCatchAll,omitempty" export:"true"`
+	MixedTLSModeConflict      string              `description:"Defines how a HostSNI claimed on an entry point by both TLS passthrough and TLS terminated TCP routes is resolved: unset rejects the routes, passthrough rejects the TLS terminated ones, terminate rejects the TLS passthrough ones." json:"mixedTLSModeConflict,omitempty" toml:"mixedTLSModeConflict,omitempty" yaml:"mixedTLSModeConflict,omitempty" export:"true"`
+	DisallowedTLSOption       string              `description:"Defines how the routes referencing a TLSOption of a namespace they are not allowed to reference are handled: unset rejects them, default serves them the default TLS options with an error." json:"disallowedTLSOption,omitempty" toml:"disallowedTLSOption,omitempty" yaml:"disallowedTLSOption,omitempty" export:"true"`
+	GenerateNameOnly          string              `description:"Defines how the IngressRouteTCPs having a generateName and no name, whose router keys are unstable, are handled: unset loads them with a warning, skip skips them with an error." json:"generateNameOnly,omitempty" toml:"generateNameOnly,omitempty" yaml:"generateNameOnly,omitempty" export:"true"`
+	MaxConcurrentLookups      int                 `description:"Defines the maximum number of concurrent Service and endpoints lookups performed when loading the TCP routes." json:"maxConcurrentLookups,omitempty" toml:"maxConcurrentLookups,omitempty" yaml:"maxConcurrentLookups,omitempty" export:"true"`
+	ExternalNameLookup        *ExternalNameLookup `description:"Resolves the hostnames targeted by the ExternalName services of the TCP services when loading the configuration, instead of when dialing the servers." json:"externalNameLookup,omitempty" toml:"externalNameLookup,omitempty" yaml:"externalNameLookup,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	SecretReadRetry           *SecretReadRetry    `description:"Retries the reads of the TLS secrets of the TCP routes failing transiently, with an exponential backoff." json:"secretReadRetry,omitempty" toml:"secretReadRetry,omitempty" yaml:"secretReadRetry,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	LocalNodeShedding         *LocalNodeShedding  `description:"Excludes the endpoints of the node Traefik runs on from the TCP services while the load of the node exceeds a threshold." json:"localNodeShedding,omitempty" toml:"localNodeShedding,omitempty" yaml:"localNodeShedding,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	HotpatchConfigMap         string              `description:"Defines the namespace/name of a ConfigMap overriding the routing of the TCP routers by SNI, each entry redirecting a SNI to a Service, as [namespace/]name:port, or dropping its connections, as drop." json:"hotpatchConfigMap,omitempty" toml:"hotpatchConfigMap,omitempty" yaml:"hotpatchConfigMap,omitempty" export:"true"`
 
 	lastConfiguration safe.Safe
 
+	// tcpPoolsEmpty tracks, for each TCP router, whether its servers pool was empty at the last sync.
+	tcpPoolsEmpty map[string]bool
+
+	// tcpTopology holds, for each TCP router, its topology at the last sync.
+	tcpTopology map[string]tcpTopologyRoute
+	// tcpTopologyExported tells whether tcpTopology was exported to the TopologyFile.
+	tcpTopologyExported bool
+
+	// tcpClusterIPs holds, for each Service targeted by a NativeLB TCP service, its ClusterIP when last loaded.
+	tcpClusterIPs map[string]string
+
+	// localNodeName is the name of the node Traefik runs on, resolved when the local node shedding is enabled.
+	localNodeName string
+	// localNodeUnderPressure reports whether the load of the local node exceeds the local node shedding threshold.
+	localNodeUnderPressure atomic.Bool
+	// loadSignal returns the load of the local node, nodeLoadPerCPU if nil.
+	loadSignal func() (float64, error)
+
+	// externalNameAddresses holds, for each ExternalName target, its last resolved addresses.
+	externalNameAddresses map[string][]string
+	// lookupHost resolves the ExternalName targets, net.DefaultResolver.LookupHost if nil.
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
 	routerTransform k8s.RouterTransform
 }
 
+// SetDefaults sets the default values.
+func (p *Provider) SetDefaults() {
+	p.MaxHostSNIs = defaultMaxHostSNIs
+	p.MaxConcurrentLookups = defaultMaxConcurrentLookups
+	p.EndpointSource = endpointSourceAuto
+}
+
 func (p *Provider) SetRouterTransform(routerTransform k8s.RouterTransform) {
 	p.routerTransform = routerTransform
 }
@@ -89,6 +191,66 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 	log.Ctx(ctx).Info().Msgf("label selector is: %q", p.LabelSelector)
 
+	if p.ListChunkSize != 0 && (p.ListChunkSize < minListChunkSize || p.ListChunkSize > maxListChunkSize) {
+		return nil, fmt.Errorf("invalid list chunk size %d: must be between %d and %d", p.ListChunkSize, minListChunkSize, maxListChunkSize)
+	}
+
+	for _, condition := range p.EndpointConditions {
+		if condition != endpointConditionReady && condition != endpointConditionServing {
+			return nil, fmt.Errorf("invalid endpoint condition %q: must be %s or %s", condition, endpointConditionReady, endpointConditionServing)
+		}
+	}
+
+	switch p.EndpointSource {
+	case "", endpointSourceAuto:
+	case endpointSourceEndpoints, endpointSourceEndpointSlices:
+		if p.EndpointsFallback {
+			return nil, fmt.Errorf("the EndpointsFallback option requires the %s endpoint source, not %s", endpointSourceAuto, p.EndpointSource)
+		}
+	default:
+		return nil, fmt.Errorf("invalid endpoint source %q: must be %s, %s or %s", p.EndpointSource, endpointSourceAuto, endpointSourceEndpoints, endpointSourceEndpointSlices)
+	}
+
+	switch p.ServiceOptionsConflict {
+	case "", serviceOptionsConflictReject, serviceOptionsConflictFirstService:
+	default:
+		return nil, fmt.Errorf("invalid service options conflict resolution %q: must be %s or %s", p.ServiceOptionsConflict, serviceOptionsConflictReject, serviceOptionsConflictFirstService)
+	}
+
+	if p.TerminatedCatchAll != "" && p.TerminatedCatchAll != terminatedCatchAllReject {
+		return nil, fmt.Errorf("invalid terminated catch-all handling %q: must be %s", p.TerminatedCatchAll, terminatedCatchAllReject)
+	}
+
+	switch p.MixedTLSModeConflict {
+	case "", mixedTLSModeConflictPassthrough, mixedTLSModeConflictTerminate:
+	default:
+		return nil, fmt.Errorf("invalid mixed TLS mode conflict resolution %q: must be %s or %s", p.MixedTLSModeConflict, mixedTLSModeConflictPassthrough, mixedTLSModeConflictTerminate)
+	}
+
+	if p.DisallowedTLSOption != "" && p.DisallowedTLSOption != disallowedTLSOptionDefault {
+		return nil, fmt.Errorf("invalid disallowed TLSOption handling %q: must be %s", p.DisallowedTLSOption, disallowedTLSOptionDefault)
+	}
+
+	if p.GenerateNameOnly != "" && p.GenerateNameOnly != generateNameOnlySkip {
+		return nil, fmt.Errorf("invalid generateName only handling %q: must be %s", p.GenerateNameOnly, generateNameOnlySkip)
+	}
+
+	if p.MaxConcurrentLookups < 1 {
+		return nil, fmt.Errorf("invalid max concurrent lookups %d: must be at least 1", p.MaxConcurrentLookups)
+	}
+
+	if p.SecretReadRetry != nil && p.SecretReadRetry.Attempts < 1 {
+		return nil, fmt.Errorf("invalid secret read attempts %d: must be at least 1", p.SecretReadRetry.Attempts)
+	}
+
+	var hotpatchNamespace, hotpatchName string
+	if p.HotpatchConfigMap != "" {
+		hotpatchNamespace, hotpatchName, err = parseHotpatchConfigMap(p.HotpatchConfigMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	withEndpoint := ""
 	if p.Endpoint != "" {
 		withEndpoint = fmt.Sprintf(" with endpoint %s", p.Endpoint)
@@ -112,6 +274,9 @@ func (p *Provider) newK8sClient(ctx context.Context) (*clientWrapper, error) {
 	}
 
 	client.labelSelector = p.LabelSelector
+	client.listChunkSize = p.ListChunkSize
+	client.hotpatchNamespace = hotpatchNamespace
+	client.hotpatchName = hotpatchName
 	return client, nil
 }
 
@@ -139,7 +304,23 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 		logger.Info().Msg("ExternalName service loading is enabled, please ensure that this is expected (see AllowExternalNameServices option)")
 	}
 
+	if p.ZeroWeightFallback {
+		logger.Info().Msg("TCP routes whose services all have a zero weight fall back to equal weighting (see ZeroWeightFallback option)")
+	}
+
+	if p.LocalNodeShedding != nil {
+		p.localNodeName, err = p.LocalNodeShedding.localNodeName()
+		if err != nil {
+			return err
+		}
+	}
+
 	pool.GoCtx(func(ctxPool context.Context) {
+		var pressureChan <-chan interface{}
+		if p.LocalNodeShedding != nil {
+			pressureChan = p.watchLocalNodePressure(logger.WithContext(ctxPool))
+		}
+
 		operation := func() error {
 			eventsChan, err := k8sClient.WatchAll(p.Namespaces, ctxPool.Done())
 			if err != nil {
@@ -160,34 +341,37 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 			}
 
 			for {
+				var event interface{}
 				select {
 				case <-ctxPool.Done():
 					return nil
-				case event := <-eventsChan:
-					// Note that event is the *first* event that came in during this throttling interval -- if we're hitting our throttle, we may have dropped events.
-					// This is fine, because we don't treat different event types differently.
-					// But if we do in the future, we'll need to track more information about the dropped events.
-					conf := p.loadConfigurationFromCRD(ctxLog, k8sClient)
-
-					confHash, err := hashstructure.Hash(conf, nil)
-					switch {
-					case err != nil:
-						logger.Error().Err(err).Msg("Unable to hash the configuration")
-					case p.lastConfiguration.Get() == confHash:
-						logger.Debug().Msgf("Skipping Kubernetes event kind %T", event)
-					default:
-						p.lastConfiguration.Set(confHash)
-						configurationChan <- dynamic.Message{
-							ProviderName:  providerName,
-							Configuration: conf,
-						}
-					}
+				case event = <-pressureChan:
+				case event = <-eventsChan:
+				}
 
-					// If we're throttling,
-					// we sleep here for the throttle duration to enforce that we don't refresh faster than our throttle.
-					// time.Sleep returns immediately if p.ThrottleDuration is 0 (no throttle).
-					time.Sleep(throttleDuration)
+				// Note that event is the *first* event that came in during this throttling interval -- if we're hitting our throttle, we may have dropped events.
+				// This is fine, because we don't treat different event types differently.
+				// But if we do in the future, we'll need to track more information about the dropped events.
+				conf := p.loadConfigurationFromCRD(ctxLog, k8sClient)
+
+				confHash, err := hashstructure.Hash(conf, nil)
+				switch {
+				case err != nil:
+					logger.Error().Err(err).Msg("Unable to hash the configuration")
+				case p.lastConfiguration.Get() == confHash:
+					logger.Debug().Msgf("Skipping Kubernetes event kind %T", event)
+				default:
+					p.lastConfiguration.Set(confHash)
+					configurationChan <- dynamic.Message{
+						ProviderName:  providerName,
+						Configuration: conf,
+					}
 				}
+
+				// If we're throttling,
+				// we sleep here for the throttle duration to enforce that we don't refresh faster than our throttle.
+				// time.Sleep returns immediately if p.ThrottleDuration is 0 (no throttle).
+				time.Sleep(throttleDuration)
 			}
 		}
 
@@ -220,6 +404,9 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 		},
 	}
 
+	// The hotpatch overrides the routing of the IngressRouteTCPs.
+	p.applyTCPHotpatch(ctx, client, conf.TCP)
+
 	// Done after because tlsConfigs is mutated by the others above.
 	conf.TLS.Certificates = getTLSConfig(tlsConfigs)
 
@@ -318,6 +505,7 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			InFlightConn: middlewareTCP.Spec.InFlightConn,
 			IPWhiteList:  middlewareTCP.Spec.IPWhiteList,
 			IPAllowList:  middlewareTCP.Spec.IPAllowList,
+			ConnAuth:     middlewareTCP.Spec.ConnAuth,
 		}
 	}
 
@@ -446,6 +634,8 @@ func (p *Provider) loadConfigurationFromCRD(ctx context.Context, client Client)
 			}
 		}
 
+		tcpServerTransport.LocalAddress = serversTransportTCP.Spec.LocalAddress
+
 		if serversTransportTCP.Spec.TLS != nil {
 			var rootCAs []types.FileOrContent
 			for _, secret := range serversTransportTCP.Spec.TLS.RootCAsSecrets {
@@ -506,6 +696,11 @@ func getServicePort(svc *corev1.Service, port intstr.IntOrString) (*corev1.Servi
 		return nil, errors.New("ingressRoute service port not defined")
 	}
 
+	// An ExternalName service may not declare any port, in which case the port from the IngressRoute is used as is.
+	if len(svc.Spec.Ports) == 0 && (svc.Spec.Type != corev1.ServiceTypeExternalName || port.Type == intstr.String) {
+		return nil, fmt.Errorf("service %s/%s exposes no ports", svc.Namespace, svc.Name)
+	}
+
 	hasValidPort := false
 	for _, p := range svc.Spec.Ports {
 		if (port.Type == intstr.Int && port.IntVal == p.Port) || (port.Type == intstr.String && port.StrVal == p.Name) {
@@ -0,0 +1,177 @@
+package crd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/provider"
+	traefikv1alpha1 "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// hotpatchDrop is the value of the hotpatch ConfigMap entries dropping the connections of their SNI.
+const hotpatchDrop = "drop"
+
+// hotpatchPriority is the priority of the routers overriding the routing of the hotpatched SNIs,
+// i.e. the highest priority of the user-defined routers, for them to take precedence over the IngressRouteTCPs.
+const hotpatchPriority = math.MaxInt - 1000
+
+// parseHotpatchConfigMap returns the namespace and the name of the given HotpatchConfigMap option.
+func parseHotpatchConfigMap(hotpatchConfigMap string) (string, string, error) {
+	namespace, name, ok := strings.Cut(hotpatchConfigMap, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid hotpatch ConfigMap %q: must be namespace/name", hotpatchConfigMap)
+	}
+
+	return namespace, name, nil
+}
+
+// hotpatchTarget is the Kubernetes Service the connections of a hotpatched SNI are redirected to.
+type hotpatchTarget struct {
+	namespace string
+	service   traefikv1alpha1.ServiceTCP
+}
+
+// parseHotpatchTarget parses the value of a hotpatch ConfigMap entry redirecting a SNI: [namespace/]name:port,
+// the namespace defaulting to the one of the ConfigMap.
+func parseHotpatchTarget(value, defaultNamespace string) (hotpatchTarget, error) {
+	index := strings.LastIndex(value, ":")
+	if index <= 0 || index == len(value)-1 {
+		return hotpatchTarget{}, fmt.Errorf("invalid hotpatch target %q: must be %s, or [namespace/]name:port", value, hotpatchDrop)
+	}
+
+	namespace, name, ok := strings.Cut(value[:index], "/")
+	if !ok {
+		namespace, name = defaultNamespace, value[:index]
+	}
+
+	if namespace == "" || name == "" {
+		return hotpatchTarget{}, fmt.Errorf("invalid hotpatch target %q: must be %s, or [namespace/]name:port", value, hotpatchDrop)
+	}
+
+	return hotpatchTarget{
+		namespace: namespace,
+		service:   traefikv1alpha1.ServiceTCP{Name: name, Port: intstr.Parse(value[index+1:])},
+	}, nil
+}
+
+// applyTCPHotpatch overrides the routing of the SNIs listed in the hotpatch ConfigMap, if any, on the given configuration.
+// Each entry of the ConfigMap maps a SNI to either drop, to drop its connections,
+// or a Kubernetes Service, as [namespace/]name:port, to redirect its connections to.
+// The routers matching a hotpatched SNI with a HostSNI matcher are overridden, on their entry points,
+// by a router of the highest priority matching only the SNI.
+func (p *Provider) applyTCPHotpatch(ctx context.Context, client Client, conf *dynamic.TCPConfiguration) {
+	if p.HotpatchConfigMap == "" {
+		return
+	}
+
+	logger := log.Ctx(ctx).With().Str("hotpatchConfigMap", p.HotpatchConfigMap).Logger()
+
+	namespace, name, err := parseHotpatchConfigMap(p.HotpatchConfigMap)
+	if err != nil {
+		logger.Error().Err(err).Msg("Cannot apply the hotpatch")
+		return
+	}
+
+	configMap, exists, err := client.GetConfigMap(namespace, name)
+	if err != nil {
+		logger.Error().Err(forbiddenError(err, "configmaps", namespace)).Msg("Cannot read the hotpatch ConfigMap")
+		return
+	}
+
+	if !exists || len(configMap.Data) == 0 {
+		return
+	}
+
+	snis := make([]string, 0, len(configMap.Data))
+	for sni := range configMap.Data {
+		snis = append(snis, sni)
+	}
+	sort.Strings(snis)
+
+	for _, sni := range snis {
+		value := strings.TrimSpace(configMap.Data[sni])
+		sniLogger := logger.With().Str("sni", sni).Str("hotpatch", value).Logger()
+
+		routerNames := routersMatchingSNI(conf.Routers, sni)
+		if len(routerNames) == 0 {
+			sniLogger.Warn().Msg("Hotpatch ignored: no router matches the SNI")
+			continue
+		}
+
+		serviceName := provider.Normalize("hotpatch-" + sni)
+
+		service, err := p.hotpatchService(ctx, client, value, namespace)
+		if err != nil {
+			sniLogger.Error().Err(err).Msg("Cannot apply the hotpatch")
+			continue
+		}
+		conf.Services[serviceName] = service
+
+		for _, routerName := range routerNames {
+			router := conf.Routers[routerName].DeepCopy()
+			router.Rule = fmt.Sprintf("HostSNI(`%s`)", sni)
+			router.Priority = hotpatchPriority
+			router.Service = serviceName
+
+			conf.Routers[routerName+"-"+serviceName] = router
+
+			sniLogger.Warn().Str("router", routerName).Msg("Routing of the SNI overridden by the hotpatch ConfigMap")
+		}
+	}
+}
+
+// hotpatchService returns the service the connections of a hotpatched SNI are forwarded to, for the given hotpatch value.
+// The service of the dropped SNIs has no servers, for their connections to be closed.
+// The target Service of another namespace than the hotpatch ConfigMap one is only allowed along with the cross-namespace references.
+func (p *Provider) hotpatchService(ctx context.Context, client Client, value, defaultNamespace string) (*dynamic.TCPService, error) {
+	if value == hotpatchDrop {
+		return &dynamic.TCPService{LoadBalancer: &dynamic.TCPServersLoadBalancer{}}, nil
+	}
+
+	target, err := parseHotpatchTarget(value, defaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isNamespaceAllowed(p.AllowCrossNamespace, defaultNamespace, target.namespace) {
+		return nil, fmt.Errorf("hotpatch target %s/%s is not in the hotpatch ConfigMap namespace %s", target.namespace, target.service.Name, defaultNamespace)
+	}
+
+	servers, err := p.loadTCPServers(ctx, client, target.namespace, target.service)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(servers) == 0 {
+		return nil, errors.New("the hotpatch target has no servers")
+	}
+
+	return &dynamic.TCPService{LoadBalancer: &dynamic.TCPServersLoadBalancer{Servers: servers}}, nil
+}
+
+// routersMatchingSNI returns the sorted names of the routers whose rule matches the given SNI with a HostSNI matcher.
+func routersMatchingSNI(routers map[string]*dynamic.TCPRouter, sni string) []string {
+	var names []string
+	for name, router := range routers {
+		hostSNIs, err := tcpmuxer.ParseHostSNI(router.Rule)
+		if err != nil {
+			continue
+		}
+
+		if slices.ContainsFunc(hostSNIs, func(hostSNI string) bool { return strings.EqualFold(hostSNI, sni) }) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -140,13 +140,19 @@ func (p *Provider) loadIngressRouteConfiguration(ctx context.Context, client Cli
 							Msgf("Namespace %q is ignored in cross-provider context", ns)
 					}
 
-					if !isNamespaceAllowed(p.AllowCrossNamespace, ingressRoute.Namespace, ns) {
+					switch {
+					case isNamespaceAllowed(p.AllowCrossNamespace, ingressRoute.Namespace, ns):
+						r.TLS.Options = tlsOptionsName
+
+					case p.DisallowedTLSOption == disallowedTLSOptionDefault:
+						logger.Error().Msgf("TLSOption %s/%s is not in the IngressRoute namespace %s, the default TLS options are used (see DisallowedTLSOption option)",
+							ns, ingressRoute.Spec.TLS.Options.Name, ingressRoute.Namespace)
+
+					default:
 						logger.Error().Msgf("TLSOption %s/%s is not in the IngressRoute namespace %s",
 							ns, ingressRoute.Spec.TLS.Options.Name, ingressRoute.Namespace)
 						continue
 					}
-
-					r.TLS.Options = tlsOptionsName
 				}
 			}
 
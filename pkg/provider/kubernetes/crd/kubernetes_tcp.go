@@ -2,19 +2,35 @@ package crd
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/provider"
 	traefikv1alpha1 "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
 	"github.com/traefik/traefik/v3/pkg/tls"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	kerror "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client Client, tlsConfigs map[string]*tls.CertAndStores) *dynamic.TCPConfiguration {
@@ -25,38 +41,85 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 		ServersTransports: map[string]*dynamic.TCPServersTransport{},
 	}
 
-	for _, ingressRouteTCP := range client.GetIngressRouteTCPs() {
+	pools := make(map[string]tcpPool)
+	topology := make(map[string]tcpTopologyRoute)
+	catchAlls := make(map[terminatedCatchAll][]string)
+	tlsModes := make(map[mixedTLSModeSNI]mixedTLSModeRouters)
+
+	ingressRouteTCPs := deduplicateIngressRouteTCPs(ctx, client.GetIngressRouteTCPs())
+	client = p.prefetchServiceLookups(ctx, client, ingressRouteTCPs)
+
+	for _, ingressRouteTCP := range ingressRouteTCPs {
 		logger := log.Ctx(ctx).With().Str("ingress", ingressRouteTCP.Name).Str("namespace", ingressRouteTCP.Namespace).Logger()
 
 		if !shouldProcessIngress(p.IngressClass, ingressRouteTCP.Annotations[annotationKubernetesIngressClass]) {
 			continue
 		}
 
-		if ingressRouteTCP.Spec.TLS != nil && !ingressRouteTCP.Spec.TLS.Passthrough {
-			err := getTLSTCP(ctx, ingressRouteTCP, client, tlsConfigs)
-			if err != nil {
-				logger.Error().Err(err).Msg("Error configuring TLS")
-			}
-		}
+		logger = withLogLevelAnnotation(logger, ingressRouteTCP.Annotations)
 
 		ingressName := ingressRouteTCP.Name
 		if len(ingressName) == 0 {
+			// The API server sets the name of the objects created with a generateName,
+			// an object without name acquiring one on a later sync would change the keys of its routers.
+			logger = logger.With().Str("generateName", ingressRouteTCP.GenerateName).Logger()
+			if p.GenerateNameOnly == generateNameOnlySkip {
+				logger.Error().Msg("IngressRouteTCP with a generateName and no name, the keys of its routers would be unstable, it is skipped (see GenerateNameOnly option)")
+				continue
+			}
+
+			logger.Warn().Msg("IngressRouteTCP with a generateName and no name, the keys of its routers are derived from the generateName and may be unstable (see GenerateNameOnly option)")
 			ingressName = ingressRouteTCP.GenerateName
 		}
 
+		if ingressRouteTCP.Spec.TLS != nil && !ingressRouteTCP.Spec.TLS.Passthrough {
+			err := p.getTLSTCP(logger.WithContext(ctx), ingressRouteTCP, client, tlsConfigs)
+			if err != nil {
+				logger.Error().Err(err).Msg("Error configuring TLS")
+			}
+		}
+
 		for _, route := range ingressRouteTCP.Spec.Routes {
-			if len(route.Match) == 0 {
-				logger.Error().Msg("Empty match rule")
+			rule, err := routeTCPRule(route)
+			if err != nil {
+				logger.Error().Err(err).Msg("Invalid route rule")
 				continue
 			}
 
-			key, err := makeServiceKey(route.Match, ingressName)
+			if p.MaxHostSNIs > 0 {
+				// The rule parsing errors are reported when the router is built.
+				hostSNIs, err := tcpmuxer.ParseHostSNI(rule)
+				if err == nil && len(hostSNIs) > p.MaxHostSNIs {
+					logger.Error().Str("route", rule).
+						Msgf("Route rule declares %d HostSNI values, exceeding the limit of %d (see MaxHostSNIs option)", len(hostSNIs), p.MaxHostSNIs)
+					continue
+				}
+			}
+
+			var catchAll bool
+			if ingressRouteTCP.Spec.TLS != nil && !ingressRouteTCP.Spec.TLS.Passthrough {
+				// The rule parsing errors are reported when the router is built.
+				catchAll, _ = tcpmuxer.IsSNICatchAll(rule)
+			}
+
+			if catchAll {
+				if p.TerminatedCatchAll == terminatedCatchAllReject {
+					logger.Error().Str("route", rule).
+						Msg("TLS terminated route matching any SNI, a specific HostSNI is required to select its certificate, the route is rejected (see TerminatedCatchAll option)")
+					continue
+				}
+
+				logger.Warn().Str("route", rule).
+					Msg("TLS terminated route matching any SNI, the connections whose SNI matches no certificate are served the default certificate (see TerminatedCatchAll option)")
+			}
+
+			key, err := makeServiceKey(rule, ingressName)
 			if err != nil {
 				logger.Error().Err(err).Send()
 				continue
 			}
 
-			mds, err := p.makeMiddlewareTCPKeys(ctx, ingressRouteTCP.Namespace, route.Middlewares)
+			mds, err := p.makeMiddlewareTCPKeys(logger.WithContext(ctx), ingressRouteTCP.Namespace, route.Middlewares)
 			if err != nil {
 				logger.Error().Err(err).Msg("Failed to create middleware keys")
 				continue
@@ -64,31 +127,92 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 
 			serviceName := makeID(ingressRouteTCP.Namespace, key)
 
+			var unresolved, conflicting bool
+			var firstBalancer *dynamic.TCPServersLoadBalancer
 			for _, service := range route.Services {
-				balancerServerTCP, err := p.createLoadBalancerServerTCP(client, ingressRouteTCP.Namespace, service)
+				balancerServerTCP, err := p.createLoadBalancerServerTCP(logger.WithContext(ctx), client, ingressRouteTCP.Namespace, service)
 				if err != nil {
 					logger.Error().
 						Str("serviceName", service.Name).
 						Stringer("servicePort", &service.Port).
 						Err(err).
 						Msg("Cannot create service")
+
+					if errors.Is(err, errExternalNameLookup) {
+						unresolved = true
+						break
+					}
 					continue
 				}
 
+				if firstBalancer == nil {
+					firstBalancer = balancerServerTCP.LoadBalancer
+				} else if options := conflictingServiceOptions(firstBalancer, balancerServerTCP.LoadBalancer); len(options) > 0 {
+					serviceLogger := logger.With().
+						Str("serviceName", service.Name).
+						Stringer("servicePort", &service.Port).
+						Strs("options", options).
+						Logger()
+
+					switch p.ServiceOptionsConflict {
+					case serviceOptionsConflictReject:
+						serviceLogger.Error().Msg("Service options conflict with the ones of the first service of the route, the route is rejected (see ServiceOptionsConflict option)")
+						conflicting = true
+					case serviceOptionsConflictFirstService:
+						serviceLogger.Warn().Msg("Service options conflict with the ones of the first service of the route, applying the ones of the first service (see ServiceOptionsConflict option)")
+						applyServiceOptions(balancerServerTCP.LoadBalancer, firstBalancer)
+					default:
+						serviceLogger.Warn().Msg("Service options conflict with the ones of the first service of the route, each service keeps its own options (see ServiceOptionsConflict option)")
+					}
+
+					if conflicting {
+						break
+					}
+				}
+
+				// The weight only applies to the load balancer of services.
+				var weight *int
+				if len(route.Services) > 1 {
+					weight, err = p.getServiceWeight(client, ingressRouteTCP.Namespace, service)
+					if err != nil {
+						logger.Error().
+							Str("serviceName", service.Name).
+							Stringer("servicePort", &service.Port).
+							Err(err).
+							Msg("Cannot create service")
+						continue
+					}
+				}
+
 				// If there is only one service defined, we skip the creation of the load balancer of services,
 				// i.e. the service on top is directly a load balancer of servers.
+				serviceKey := serviceName
+				if len(route.Services) > 1 {
+					serviceKey = fmt.Sprintf("%s-%s-%s", serviceName, service.Name, &service.Port)
+				}
+
+				services, err := p.makeZoneWeightedServicesTCP(client, ingressRouteTCP.Namespace, service, serviceKey, balancerServerTCP)
+				if err != nil {
+					logger.Error().
+						Str("serviceName", service.Name).
+						Stringer("servicePort", &service.Port).
+						Err(err).
+						Msg("Cannot create service")
+					continue
+				}
+
+				for key, svc := range services {
+					conf.Services[key] = svc
+				}
+
 				if len(route.Services) == 1 {
-					conf.Services[serviceName] = balancerServerTCP
 					break
 				}
 
-				serviceKey := fmt.Sprintf("%s-%s-%s", serviceName, service.Name, &service.Port)
-				conf.Services[serviceKey] = balancerServerTCP
-
 				srv := dynamic.TCPWRRService{Name: serviceKey}
 				srv.SetDefaults()
-				if service.Weight != nil {
-					srv.Weight = service.Weight
+				if weight != nil {
+					srv.Weight = weight
 				}
 
 				if conf.Services[serviceName] == nil {
@@ -97,54 +221,403 @@ func (p *Provider) loadIngressRouteTCPConfiguration(ctx context.Context, client
 				conf.Services[serviceName].Weighted.Services = append(conf.Services[serviceName].Weighted.Services, srv)
 			}
 
+			if unresolved {
+				logger.Error().Str("route", rule).Msg("An ExternalName target of the route cannot be resolved, the route is rejected (see ExternalNameLookup option)")
+
+				deleteRouteServices(conf, serviceName)
+				continue
+			}
+
+			if conflicting {
+				deleteRouteServices(conf, serviceName)
+				continue
+			}
+
+			if svc := conf.Services[serviceName]; svc != nil && svc.Weighted != nil && allZeroWeights(svc.Weighted.Services) {
+				if !p.ZeroWeightFallback {
+					logger.Error().Str("route", rule).Msg("All services of the route have a zero weight, the route is rejected (see ZeroWeightFallback option)")
+
+					deleteRouteServices(conf, serviceName)
+					continue
+				}
+
+				logger.Warn().Str("route", rule).Msg("All services of the route have a zero weight, falling back to equal weighting")
+
+				for i := range svc.Weighted.Services {
+					svc.Weighted.Services[i].SetDefaults()
+				}
+			}
+
 			r := &dynamic.TCPRouter{
 				EntryPoints: ingressRouteTCP.Spec.EntryPoints,
 				Middlewares: mds,
-				Rule:        route.Match,
+				Rule:        rule,
 				Priority:    route.Priority,
 				RuleSyntax:  route.Syntax,
 				Service:     serviceName,
 			}
 
 			if ingressRouteTCP.Spec.TLS != nil {
+				tlsOptionsName, allowed := p.tlsOptionsTCP(logger, ingressRouteTCP)
+				if !allowed {
+					continue
+				}
+
 				r.TLS = &dynamic.RouterTCPTLSConfig{
-					Passthrough:  ingressRouteTCP.Spec.TLS.Passthrough,
-					CertResolver: ingressRouteTCP.Spec.TLS.CertResolver,
-					Domains:      ingressRouteTCP.Spec.TLS.Domains,
+					Passthrough:              ingressRouteTCP.Spec.TLS.Passthrough,
+					CertResolver:             ingressRouteTCP.Spec.TLS.CertResolver,
+					Domains:                  ingressRouteTCP.Spec.TLS.Domains,
+					CloseOnCertificateChange: ingressRouteTCP.Spec.TLS.CloseOnCertificateChange,
+					Options:                  tlsOptionsName,
 				}
 
-				if ingressRouteTCP.Spec.TLS.Options != nil && len(ingressRouteTCP.Spec.TLS.Options.Name) > 0 {
-					tlsOptionsName := ingressRouteTCP.Spec.TLS.Options.Name
-					// Is a Kubernetes CRD reference (i.e. not a cross-provider reference)
-					ns := ingressRouteTCP.Spec.TLS.Options.Namespace
-					if !strings.Contains(tlsOptionsName, providerNamespaceSeparator) {
-						if len(ns) == 0 {
-							ns = ingressRouteTCP.Namespace
+				if failureService := ingressRouteTCP.Spec.TLS.HandshakeFailureService; failureService != nil {
+					if ingressRouteTCP.Spec.TLS.Passthrough {
+						logger.Warn().Msg("HandshakeFailureService is ignored as the TLS connections are not terminated (passthrough)")
+					} else {
+						balancerServerTCP, err := p.createLoadBalancerServerTCP(logger.WithContext(ctx), client, ingressRouteTCP.Namespace, *failureService)
+						if err != nil {
+							logger.Error().
+								Str("serviceName", failureService.Name).
+								Stringer("servicePort", &failureService.Port).
+								Err(err).
+								Msg("Cannot create handshake failure service")
+						} else {
+							failureServiceName := serviceName + "-handshake-failure"
+							conf.Services[failureServiceName] = balancerServerTCP
+							r.TLS.HandshakeFailureService = failureServiceName
 						}
-						tlsOptionsName = makeID(ns, tlsOptionsName)
-					} else if len(ns) > 0 {
-						logger.Warn().
-							Str("TLSOption", ingressRouteTCP.Spec.TLS.Options.Name).
-							Msgf("Namespace %q is ignored in cross-provider context", ns)
 					}
+				}
+			}
 
-					if !isNamespaceAllowed(p.AllowCrossNamespace, ingressRouteTCP.Namespace, ns) {
-						logger.Error().Msgf("TLSOption %s/%s is not in the IngressRouteTCP namespace %s",
-							ns, ingressRouteTCP.Spec.TLS.Options.Name, ingressRouteTCP.Namespace)
-						continue
+			conf.Routers[serviceName] = r
+
+			// The routers without entry points are attached to the default ones.
+			entryPoints := r.EntryPoints
+			if len(entryPoints) == 0 {
+				entryPoints = []string{""}
+			}
+
+			if catchAll {
+				priority := route.Priority
+				if priority == 0 {
+					priority = tcpmuxer.GetRulePriority(rule)
+				}
+
+				for _, entryPoint := range entryPoints {
+					key := terminatedCatchAll{entryPoint: entryPoint, priority: priority}
+					catchAlls[key] = append(catchAlls[key], serviceName)
+				}
+			}
+
+			if r.TLS != nil {
+				// The rule parsing errors are reported when the router is built.
+				hostSNIs, _ := tcpmuxer.ParseHostSNI(rule)
+
+				for _, entryPoint := range entryPoints {
+					for _, hostSNI := range hostSNIs {
+						if hostSNI == "*" {
+							continue
+						}
+
+						key := mixedTLSModeSNI{entryPoint: entryPoint, hostSNI: strings.ToLower(hostSNI)}
+						tlsModes[key] = tlsModes[key].with(serviceName, r.TLS.Passthrough)
 					}
+				}
+			}
+
+			if p.PoolTransitionEvents {
+				pools[serviceName] = tcpPool{logger: logger, servers: countTCPServers(conf.Services, serviceName)}
+			}
 
-					r.TLS.Options = tlsOptionsName
+			if p.TopologyEvents || p.TopologyFile != "" {
+				// The rule parsing errors are reported when the router is built.
+				hostSNIs, _ := tcpmuxer.ParseHostSNI(rule)
+
+				topology[serviceName] = tcpTopologyRoute{
+					Namespace:   ingressRouteTCP.Namespace,
+					Ingress:     ingressRouteTCP.Name,
+					EntryPoints: r.EntryPoints,
+					Router:      serviceName,
+					HostSNIs:    hostSNIs,
+					Service:     serviceName,
+					Backends:    tcpBackends(conf.Services, serviceName),
 				}
 			}
+		}
+	}
 
-			conf.Routers[serviceName] = r
+	rejected := rejectCollidingCatchAlls(ctx, conf, catchAlls)
+	rejected = append(rejected, p.resolveMixedTLSModeConflicts(ctx, conf, tlsModes)...)
+
+	for _, router := range rejected {
+		delete(pools, router)
+		delete(topology, router)
+	}
+
+	if p.PoolTransitionEvents {
+		p.logTCPPoolTransitions(pools)
+	}
+
+	if p.TopologyEvents {
+		p.logTCPTopologyChanges(ctx, topology)
+	}
+
+	if p.TopologyFile != "" && (!p.tcpTopologyExported || !reflect.DeepEqual(p.tcpTopology, topology)) {
+		err := exportTCPTopology(p.TopologyFile, topology)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("topologyFile", p.TopologyFile).Msg("Cannot export the TCP routers topology")
 		}
+
+		// On failure, the export is retried on the next sync.
+		p.tcpTopologyExported = err == nil
+	}
+
+	if p.TopologyEvents || p.TopologyFile != "" {
+		p.tcpTopology = topology
 	}
 
 	return conf
 }
 
+// tlsOptionsTCP returns the name of the TLS options of the routers of the given IngressRouteTCP, empty for the default ones,
+// and whether the routers are allowed, i.e. not rejected for referencing TLS options of another namespace.
+func (p *Provider) tlsOptionsTCP(logger zerolog.Logger, ingressRouteTCP *traefikv1alpha1.IngressRouteTCP) (string, bool) {
+	if ingressRouteTCP.Spec.TLS == nil || ingressRouteTCP.Spec.TLS.Options == nil || len(ingressRouteTCP.Spec.TLS.Options.Name) == 0 {
+		return "", true
+	}
+
+	tlsOptionsName := ingressRouteTCP.Spec.TLS.Options.Name
+	// Is a Kubernetes CRD reference (i.e. not a cross-provider reference)
+	ns := ingressRouteTCP.Spec.TLS.Options.Namespace
+	if !strings.Contains(tlsOptionsName, providerNamespaceSeparator) {
+		if len(ns) == 0 {
+			ns = ingressRouteTCP.Namespace
+		}
+		tlsOptionsName = makeID(ns, tlsOptionsName)
+	} else if len(ns) > 0 {
+		logger.Warn().
+			Str("TLSOption", ingressRouteTCP.Spec.TLS.Options.Name).
+			Msgf("Namespace %q is ignored in cross-provider context", ns)
+	}
+
+	switch {
+	case isNamespaceAllowed(p.AllowCrossNamespace, ingressRouteTCP.Namespace, ns):
+		return tlsOptionsName, true
+
+	case p.DisallowedTLSOption == disallowedTLSOptionDefault:
+		logger.Error().Msgf("TLSOption %s/%s is not in the IngressRouteTCP namespace %s, the default TLS options are used (see DisallowedTLSOption option)",
+			ns, ingressRouteTCP.Spec.TLS.Options.Name, ingressRouteTCP.Namespace)
+		return "", true
+
+	default:
+		logger.Error().Msgf("TLSOption %s/%s is not in the IngressRouteTCP namespace %s",
+			ns, ingressRouteTCP.Spec.TLS.Options.Name, ingressRouteTCP.Namespace)
+		return "", false
+	}
+}
+
+// trackClusterIP logs an event when the ClusterIP of the given Service, targeted by a NativeLB TCP service, changed since it was last loaded,
+// i.e. when the Service was recreated, its previous ClusterIP being defunct.
+// The ClusterIPs are kept while the Services are missing, for their recreation to be detected across the syncs they are missing during.
+func (p *Provider) trackClusterIP(ctx context.Context, service *corev1.Service) {
+	if p.tcpClusterIPs == nil {
+		p.tcpClusterIPs = make(map[string]string)
+	}
+
+	key := service.Namespace + "/" + service.Name
+
+	previous, tracked := p.tcpClusterIPs[key]
+	p.tcpClusterIPs[key] = service.Spec.ClusterIP
+
+	if !tracked || previous == service.Spec.ClusterIP {
+		return
+	}
+
+	log.Ctx(ctx).Warn().
+		Str("namespace", service.Namespace).
+		Str("serviceName", service.Name).
+		Str("previousClusterIP", previous).
+		Str("clusterIP", service.Spec.ClusterIP).
+		Msg("ClusterIP of the Service changed since it was last loaded, the TCP servers are updated to the new ClusterIP")
+}
+
+// tcpPool is the servers pool of a TCP router, along with the logger of its IngressRouteTCP.
+type tcpPool struct {
+	logger  zerolog.Logger
+	servers int
+}
+
+// logTCPPoolTransitions logs an event for each TCP router of the final configuration whose servers pool became empty,
+// or is no longer empty, since the last sync.
+// It is called once the rejected routers are removed, for the transitions to only be logged for the routers actually loaded.
+func (p *Provider) logTCPPoolTransitions(pools map[string]tcpPool) {
+	poolsEmpty := make(map[string]bool, len(pools))
+
+	routerNames := make([]string, 0, len(pools))
+	for routerName := range pools {
+		routerNames = append(routerNames, routerName)
+	}
+	sort.Strings(routerNames)
+
+	for _, routerName := range routerNames {
+		pool := pools[routerName]
+		poolsEmpty[routerName] = pool.servers == 0
+
+		wasEmpty, tracked := p.tcpPoolsEmpty[routerName]
+		switch {
+		case !tracked || wasEmpty == (pool.servers == 0):
+		case pool.servers == 0:
+			pool.logger.Warn().Str(logs.RouterName, routerName).Str("transition", "empty").
+				Msg("TCP router servers pool is now empty")
+		default:
+			pool.logger.Info().Str(logs.RouterName, routerName).Str("transition", "nonEmpty").Int("servers", pool.servers).
+				Msg("TCP router servers pool is no longer empty")
+		}
+	}
+
+	p.tcpPoolsEmpty = poolsEmpty
+}
+
+// countTCPServers returns the number of servers reachable through the given service.
+func countTCPServers(services map[string]*dynamic.TCPService, serviceName string) int {
+	service, ok := services[serviceName]
+	if !ok {
+		return 0
+	}
+
+	switch {
+	case service.LoadBalancer != nil:
+		return len(service.LoadBalancer.Servers)
+	case service.Weighted != nil:
+		var count int
+		for _, wrrService := range service.Weighted.Services {
+			if wrrService.Weight != nil && *wrrService.Weight == 0 {
+				continue
+			}
+			count += countTCPServers(services, wrrService.Name)
+		}
+		return count
+	default:
+		return 0
+	}
+}
+
+// tcpTopologyRoute is the topology of a TCP router, from its entry points to its backends.
+type tcpTopologyRoute struct {
+	Namespace   string   `json:"namespace"`
+	Ingress     string   `json:"ingress"`
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	Router      string   `json:"router"`
+	HostSNIs    []string `json:"hostSNIs,omitempty"`
+	Service     string   `json:"service"`
+	Backends    []string `json:"backends,omitempty"`
+}
+
+// logTCPTopologyChanges logs an event for each TCP router added, updated, or removed since the last sync.
+func (p *Provider) logTCPTopologyChanges(ctx context.Context, topology map[string]tcpTopologyRoute) {
+	logger := log.Ctx(ctx)
+
+	for _, routerName := range sortedTopologyRouters(topology) {
+		route := topology[routerName]
+
+		previous, tracked := p.tcpTopology[routerName]
+		switch {
+		case !tracked:
+			logger.Info().Str("topology", "added").Interface("route", route).Msg("TCP router topology added")
+		case !reflect.DeepEqual(previous, route):
+			logger.Info().Str("topology", "updated").Interface("route", route).Msg("TCP router topology updated")
+		}
+	}
+
+	for _, routerName := range sortedTopologyRouters(p.tcpTopology) {
+		if _, ok := topology[routerName]; !ok {
+			logger.Info().Str("topology", "removed").Interface("route", p.tcpTopology[routerName]).Msg("TCP router topology removed")
+		}
+	}
+}
+
+// exportTCPTopology writes the given topology to the given file, as a JSON array of the routes sorted by router name.
+// The file is replaced atomically, for its readers to never see a partially written topology.
+func exportTCPTopology(file string, topology map[string]tcpTopologyRoute) error {
+	routes := make([]tcpTopologyRoute, 0, len(topology))
+	for _, routerName := range sortedTopologyRouters(topology) {
+		routes = append(routes, topology[routerName])
+	}
+
+	data, err := json.MarshalIndent(routes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling topology: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	// The temporary files are only readable by their owner.
+	if err = tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("setting temporary file mode: %w", err)
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+func sortedTopologyRouters(topology map[string]tcpTopologyRoute) []string {
+	keys := make([]string, 0, len(topology))
+	for key := range topology {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// tcpBackends returns the addresses of the servers of the given service, including the ones of its weighted services.
+func tcpBackends(services map[string]*dynamic.TCPService, serviceName string) []string {
+	service, ok := services[serviceName]
+	if !ok {
+		return nil
+	}
+
+	var backends []string
+	switch {
+	case service.LoadBalancer != nil:
+		for _, server := range service.LoadBalancer.Servers {
+			backends = append(backends, server.Address)
+		}
+	case service.Weighted != nil:
+		for _, wrrService := range service.Weighted.Services {
+			backends = append(backends, tcpBackends(services, wrrService.Name)...)
+		}
+	}
+
+	return backends
+}
+
+// allZeroWeights reports whether all the given weighted services have a zero weight.
+func allZeroWeights(services []dynamic.TCPWRRService) bool {
+	for _, service := range services {
+		if service.Weight == nil || *service.Weight != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (p *Provider) makeMiddlewareTCPKeys(ctx context.Context, ingRouteTCPNamespace string, middlewares []traefikv1alpha1.ObjectReference) ([]string, error) {
 	var mds []string
 
@@ -174,7 +647,7 @@ func (p *Provider) makeMiddlewareTCPKeys(ctx context.Context, ingRouteTCPNamespa
 	return mds, nil
 }
 
-func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace string, service traefikv1alpha1.ServiceTCP) (*dynamic.TCPService, error) {
+func (p *Provider) createLoadBalancerServerTCP(ctx context.Context, client Client, parentNamespace string, service traefikv1alpha1.ServiceTCP) (*dynamic.TCPService, error) {
 	ns := parentNamespace
 	if len(service.Namespace) > 0 {
 		if !isNamespaceAllowed(p.AllowCrossNamespace, parentNamespace, service.Namespace) {
@@ -184,14 +657,23 @@ func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace st
 		ns = service.Namespace
 	}
 
-	servers, err := p.loadTCPServers(client, ns, service)
+	servers, err := p.loadTCPServers(ctx, client, ns, service)
 	if err != nil {
 		return nil, err
 	}
 
 	tcpService := &dynamic.TCPService{
 		LoadBalancer: &dynamic.TCPServersLoadBalancer{
-			Servers: servers,
+			Servers:                 servers,
+			Sticky:                  service.Sticky,
+			HalfClose:               service.HalfClose,
+			NoDelay:                 service.NoDelay,
+			Strategy:                service.Strategy,
+			HashSeed:                service.HashSeed,
+			MaxConnectionsPerServer: service.MaxConnectionsPerServer,
+			Audit:                   service.Audit,
+			PrefixFrame:             service.PrefixFrame,
+			Priority:                service.Priority,
 		},
 	}
 
@@ -204,6 +686,46 @@ func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace st
 		}
 	}
 
+	if service.HealthCheck != nil {
+		tcpService.LoadBalancer.HealthCheck = &dynamic.TCPServerHealthCheck{}
+		tcpService.LoadBalancer.HealthCheck.SetDefaults()
+
+		if service.HealthCheck.Interval != 0 {
+			tcpService.LoadBalancer.HealthCheck.Interval = service.HealthCheck.Interval
+		}
+		if service.HealthCheck.Timeout != 0 {
+			tcpService.LoadBalancer.HealthCheck.Timeout = service.HealthCheck.Timeout
+		}
+		tcpService.LoadBalancer.HealthCheck.Jitter = service.HealthCheck.Jitter
+		tcpService.LoadBalancer.HealthCheck.Send = service.HealthCheck.Send
+		tcpService.LoadBalancer.HealthCheck.Expect = service.HealthCheck.Expect
+		tcpService.LoadBalancer.HealthCheck.LocalAddress = service.HealthCheck.LocalAddress
+	}
+
+	if service.PerAttemptDialTimeout != nil {
+		if err := tcpService.LoadBalancer.PerAttemptDialTimeout.Set(service.PerAttemptDialTimeout.String()); err != nil {
+			return nil, fmt.Errorf("reading perAttemptDialTimeout: %w", err)
+		}
+	}
+
+	if service.ConnectTimeout != nil {
+		if err := tcpService.LoadBalancer.ConnectTimeout.Set(service.ConnectTimeout.String()); err != nil {
+			return nil, fmt.Errorf("reading connectTimeout: %w", err)
+		}
+	}
+
+	if service.IdleTimeout != nil {
+		if err := tcpService.LoadBalancer.IdleTimeout.Set(service.IdleTimeout.String()); err != nil {
+			return nil, fmt.Errorf("reading idleTimeout: %w", err)
+		}
+	}
+
+	if service.MaxConnectionDuration != nil {
+		if err := tcpService.LoadBalancer.MaxConnectionDuration.Set(service.MaxConnectionDuration.String()); err != nil {
+			return nil, fmt.Errorf("reading maxConnectionDuration: %w", err)
+		}
+	}
+
 	if service.ServersTransport == "" && service.TerminationDelay != nil {
 		tcpService.LoadBalancer.TerminationDelay = service.TerminationDelay
 	}
@@ -218,10 +740,10 @@ func (p *Provider) createLoadBalancerServerTCP(client Client, parentNamespace st
 	return tcpService, nil
 }
 
-func (p *Provider) loadTCPServers(client Client, namespace string, svc traefikv1alpha1.ServiceTCP) ([]dynamic.TCPServer, error) {
+func (p *Provider) loadTCPServers(ctx context.Context, client Client, namespace string, svc traefikv1alpha1.ServiceTCP) ([]dynamic.TCPServer, error) {
 	service, exists, err := client.GetService(namespace, svc.Name)
 	if err != nil {
-		return nil, err
+		return nil, forbiddenError(err, "services", namespace)
 	}
 
 	if !exists {
@@ -267,9 +789,31 @@ func (p *Provider) loadTCPServers(client Client, namespace string, svc traefikv1
 	}
 
 	if service.Spec.Type == corev1.ServiceTypeExternalName {
-		servers = append(servers, dynamic.TCPServer{
-			Address: net.JoinHostPort(service.Spec.ExternalName, strconv.Itoa(int(svcPort.Port))),
-		})
+		allowed, err := p.isExternalNameAllowed(service.Spec.ExternalName)
+		if err != nil {
+			return nil, err
+		}
+
+		if !allowed {
+			return nil, fmt.Errorf("externalName %q of service %s/%s is not allowed (see ExternalNameAllowList option)", service.Spec.ExternalName, namespace, svc.Name)
+		}
+
+		if p.ExternalNameLookup == nil {
+			return []dynamic.TCPServer{{
+				Address: net.JoinHostPort(service.Spec.ExternalName, strconv.Itoa(int(svcPort.Port))),
+			}}, nil
+		}
+
+		addresses, err := p.resolveExternalName(ctx, service.Spec.ExternalName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, address := range addresses {
+			servers = append(servers, dynamic.TCPServer{
+				Address: net.JoinHostPort(address, strconv.Itoa(int(svcPort.Port))),
+			})
+		}
 	} else {
 		nativeLB := p.NativeLBByDefault
 		if svc.NativeLB != nil {
@@ -281,53 +825,807 @@ func (p *Provider) loadTCPServers(client Client, namespace string, svc traefikv1
 				return nil, fmt.Errorf("getting native Kubernetes Service address: %w", err)
 			}
 
+			p.trackClusterIP(ctx, service)
+
 			return []dynamic.TCPServer{{Address: address}}, nil
 		}
 
-		endpoints, endpointsExists, endpointsErr := client.GetEndpoints(namespace, svc.Name)
-		if endpointsErr != nil {
-			return nil, endpointsErr
-		}
+		subsets, err := p.loadEndpointSubsets(ctx, client, namespace, svc.Name)
+		if err != nil {
+			// Without selector, the endpoints of the service are never created by Kubernetes,
+			// they are not missing transiently, but until they are created by the operator.
+			if errors.Is(err, errEndpointsNotFound) && len(service.Spec.Selector) == 0 {
+				log.Ctx(ctx).Warn().
+					Str("serviceName", svc.Name).
+					Str("serviceNamespace", namespace).
+					Msg("Service has no selector and no Endpoints, its endpoints are not managed by Kubernetes: create an Endpoints, or EndpointSlice, object for the service, or add a selector to it")
+			}
 
-		if !endpointsExists {
-			return nil, errors.New("endpoints not found")
+			return nil, err
 		}
 
-		if len(endpoints.Subsets) == 0 && !p.AllowEmptyServices {
+		if len(subsets) == 0 && !p.AllowEmptyServices {
 			return nil, errors.New("subset not found")
 		}
 
+		var filter podFilter
+		if svc.PodSelector != "" {
+			filter.selector, err = labels.Parse(svc.PodSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pod selector %q: %w", svc.PodSelector, err)
+			}
+		}
+
+		if svc.ReadinessGate != nil {
+			if svc.ReadinessGate.ConditionType == "" && svc.ReadinessGate.Annotation == "" {
+				return nil, errors.New("readiness gate requires a condition type or an annotation")
+			}
+
+			filter.readinessGate = svc.ReadinessGate
+		}
+
+		var notReady, shed []dynamic.TCPServer
 		var port int32
-		for _, subset := range endpoints.Subsets {
+		for _, subset := range subsets {
+			var protocolMismatch bool
 			for _, p := range subset.Ports {
-				if svcPort.Name == p.Name {
+				if matchesEndpointsPort(service, svcPort, subset.Ports, p) {
+					// Endpoints of selectorless Services are managed by hand,
+					// and can declare the port with another protocol than the Service.
+					if portProtocol(svcPort.Protocol) != portProtocol(p.Protocol) {
+						log.Ctx(ctx).Warn().
+							Str("serviceName", svc.Name).
+							Str("serviceNamespace", namespace).
+							Str("portName", p.Name).
+							Msgf("Skipping endpoints port %d: its %s protocol does not match the %s protocol of the Service port", p.Port, portProtocol(p.Protocol), portProtocol(svcPort.Protocol))
+						protocolMismatch = true
+						break
+					}
+
 					port = p.Port
 					break
 				}
 			}
 
+			if protocolMismatch {
+				continue
+			}
+
 			if port == 0 {
 				return nil, errors.New("cannot define a port")
 			}
 
-			for _, addr := range subset.Addresses {
-				servers = append(servers, dynamic.TCPServer{
-					Address: net.JoinHostPort(addr.IP, strconv.Itoa(int(port))),
-				})
+			addresses := subset.Addresses
+			if p.shedLocalNode() {
+				var localAddresses []corev1.EndpointAddress
+				addresses, localAddresses = p.splitLocalNodeAddresses(addresses)
+
+				localServers, err := endpointServers(client, namespace, localAddresses, port, filter)
+				if err != nil {
+					return nil, err
+				}
+				shed = append(shed, localServers...)
 			}
-		}
-	}
 
-	return servers, nil
-}
+			readyServers, err := endpointServers(client, namespace, addresses, port, filter)
+			if err != nil {
+				return nil, err
+			}
+			servers = append(servers, readyServers...)
 
-func (p *Provider) makeTCPServersTransportKey(parentNamespace string, serversTransportName string) (string, error) {
-	if serversTransportName == "" {
-		return "", nil
-	}
+			notReadyServers, err := endpointServers(client, namespace, subset.NotReadyAddresses, port, filter)
+			if err != nil {
+				return nil, err
+			}
+			notReady = append(notReady, notReadyServers...)
+		}
 
-	if !p.AllowCrossNamespace && strings.HasSuffix(serversTransportName, providerNamespaceSeparator+providerName) {
-		// Since we are not able to know if another namespace is in the name (namespace-name@kubernetescrd),
+		var duplicates int
+		servers, duplicates = deduplicateServers(servers)
+		if duplicates > 0 {
+			// The Endpoints managed by hand can list the same address more than once, which would skew the load balancing.
+			log.Ctx(ctx).Warn().
+				Str("serviceName", svc.Name).
+				Str("serviceNamespace", namespace).
+				Int("duplicates", duplicates).
+				Msg("Ignoring the duplicate endpoint addresses of the service, its endpoints may be misconfigured")
+		}
+		shed, _ = deduplicateServers(shed)
+		notReady, _ = deduplicateServers(notReady)
+
+		if len(shed) > 0 {
+			logger := log.Ctx(ctx).With().Str("serviceName", svc.Name).Str("serviceNamespace", namespace).Logger()
+
+			if len(servers) == 0 {
+				logger.Debug().Int("localEndpoints", len(shed)).
+					Msg("Service only has endpoints on the local node under pressure, keeping them")
+				servers = shed
+			} else {
+				logger.Debug().Int("localEndpoints", len(shed)).
+					Msg("Excluding the endpoints of the local node under pressure")
+			}
+		}
+
+		if len(servers) == 0 && len(notReady) > 0 {
+			logger := log.Ctx(ctx).With().Str("serviceName", svc.Name).Str("serviceNamespace", namespace).Logger()
+
+			if !p.NotReadyEndpointsFallback {
+				logger.Warn().Str("state", "noReadyEndpoints").Int("notReadyEndpoints", len(notReady)).
+					Msg("Service has endpoints but none of them is ready, the servers pool is empty (see NotReadyEndpointsFallback option)")
+				return servers, nil
+			}
+
+			logger.Warn().Str("state", "servingNotReadyEndpoints").Int("notReadyEndpoints", len(notReady)).
+				Msg("Service has endpoints but none of them is ready, serving the not ready endpoints as a last resort")
+			return notReady, nil
+		}
+	}
+
+	log.Ctx(ctx).Debug().
+		Str("serviceName", svc.Name).
+		Str("serviceNamespace", namespace).
+		Int("servers", len(servers)).
+		Msg("TCP service servers loaded")
+
+	return servers, nil
+}
+
+// withLogLevelAnnotation returns the given logger of an object,
+// logging at the level set by the log level annotation of the object, if any.
+func withLogLevelAnnotation(logger zerolog.Logger, annotations map[string]string) zerolog.Logger {
+	value, ok := annotations[annotationLogLevel]
+	if !ok {
+		return logger
+	}
+
+	level, err := zerolog.ParseLevel(strings.ToLower(value))
+	if err != nil || level == zerolog.NoLevel {
+		logger.Warn().Str("logLevel", value).Msgf("Ignoring invalid %s annotation", annotationLogLevel)
+		return logger
+	}
+
+	return logger.Level(level)
+}
+
+// getServiceWeight returns the weight of the given service in the load balancer of services of its route:
+// the weight set on the service reference, or else the one set by the ServiceWeightAnnotation annotation of the targeted Service.
+// It returns nil when neither is set.
+func (p *Provider) getServiceWeight(client Client, parentNamespace string, service traefikv1alpha1.ServiceTCP) (*int, error) {
+	if service.Weight != nil {
+		return service.Weight, nil
+	}
+
+	if p.ServiceWeightAnnotation == "" {
+		return nil, nil
+	}
+
+	ns := parentNamespace
+	if len(service.Namespace) > 0 {
+		ns = service.Namespace
+	}
+
+	svc, exists, err := client.GetService(ns, service.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, errors.New("service not found")
+	}
+
+	value, ok := svc.Annotations[p.ServiceWeightAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	weight, err := strconv.Atoi(value)
+	if err != nil || weight < 0 {
+		return nil, fmt.Errorf("invalid value %q of the %s annotation of service %s/%s: must be a non-negative integer", value, p.ServiceWeightAnnotation, ns, service.Name)
+	}
+
+	return &weight, nil
+}
+
+// makeZoneWeightedServicesTCP returns the services to declare for the given service under the given key.
+// Without zone weights, it is the load balancer of servers itself,
+// otherwise it is a weighted round robin of the zones load balancers of servers, declared along with them.
+func (p *Provider) makeZoneWeightedServicesTCP(client Client, parentNamespace string, service traefikv1alpha1.ServiceTCP, key string, balancer *dynamic.TCPService) (map[string]*dynamic.TCPService, error) {
+	if len(service.ZoneWeights) == 0 {
+		return map[string]*dynamic.TCPService{key: balancer}, nil
+	}
+
+	var zones []string
+	var totalWeight int
+	for zone, weight := range service.ZoneWeights {
+		if errs := validation.IsValidLabelValue(zone); zone == "" || len(errs) > 0 {
+			return nil, fmt.Errorf("invalid zone name %q: %s", zone, strings.Join(errs, ", "))
+		}
+
+		if weight < 0 {
+			return nil, fmt.Errorf("invalid weight %d of zone %q: must be non-negative", weight, zone)
+		}
+
+		zones = append(zones, zone)
+		totalWeight += weight
+	}
+	sort.Strings(zones)
+
+	if totalWeight == 0 {
+		return nil, errors.New("all zones have a zero weight")
+	}
+
+	if p.EndpointSource == endpointSourceEndpoints {
+		return nil, fmt.Errorf("zone weights require the service endpoints to be listed by EndpointSlices, which the %s endpoint source does not use", endpointSourceEndpoints)
+	}
+
+	namespace := parentNamespace
+	if service.Namespace != "" {
+		namespace = service.Namespace
+	}
+
+	endpointSlices, err := client.GetEndpointSlicesForService(namespace, service.Name)
+	if err != nil {
+		return nil, forbiddenError(err, "endpointslices", namespace)
+	}
+
+	if len(endpointSlices) == 0 {
+		return nil, errors.New("zone weights require the service endpoints to be listed by EndpointSlices")
+	}
+
+	addressZones := make(map[string]string)
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.Zone == nil {
+				continue
+			}
+
+			for _, address := range endpoint.Addresses {
+				addressZones[address] = *endpoint.Zone
+			}
+		}
+	}
+
+	zoneServers := make(map[string][]dynamic.TCPServer)
+	for _, server := range balancer.LoadBalancer.Servers {
+		host, _, err := net.SplitHostPort(server.Address)
+		if err != nil {
+			return nil, fmt.Errorf("splitting server address %q: %w", server.Address, err)
+		}
+
+		zone := addressZones[host]
+		if service.ZoneWeights[zone] > 0 {
+			zoneServers[zone] = append(zoneServers[zone], server)
+		}
+	}
+
+	services := make(map[string]*dynamic.TCPService)
+	weighted := &dynamic.TCPWeightedRoundRobin{}
+	for _, zone := range zones {
+		if len(zoneServers[zone]) == 0 {
+			continue
+		}
+
+		zoneBalancer := *balancer.LoadBalancer
+		zoneBalancer.Servers = zoneServers[zone]
+
+		zoneKey := key + "-" + zone
+		services[zoneKey] = &dynamic.TCPService{LoadBalancer: &zoneBalancer}
+
+		weight := service.ZoneWeights[zone]
+		weighted.Services = append(weighted.Services, dynamic.TCPWRRService{Name: zoneKey, Weight: &weight})
+	}
+
+	if len(weighted.Services) == 0 {
+		emptyBalancer := *balancer.LoadBalancer
+		emptyBalancer.Servers = nil
+
+		services[key] = &dynamic.TCPService{LoadBalancer: &emptyBalancer}
+		return services, nil
+	}
+
+	services[key] = &dynamic.TCPService{Weighted: weighted}
+	return services, nil
+}
+
+// terminatedCatchAll identifies the TLS terminated routes matching any SNI which collide,
+// i.e. which are attached to the same entry point with the same priority.
+type terminatedCatchAll struct {
+	entryPoint string
+	priority   int
+}
+
+// rejectCollidingCatchAlls rejects the TLS terminated routes matching any SNI which collide on an entry point,
+// as which of them serves the connections would be undetermined, and returns the rejected routers.
+func rejectCollidingCatchAlls(ctx context.Context, conf *dynamic.TCPConfiguration, catchAlls map[terminatedCatchAll][]string) []string {
+	var rejected []string
+	for key, routers := range catchAlls {
+		if len(routers) < 2 {
+			continue
+		}
+
+		for _, router := range routers {
+			// The router may collide on several entry points.
+			if _, ok := conf.Routers[router]; !ok {
+				continue
+			}
+
+			log.Ctx(ctx).Error().
+				Str(logs.RouterName, router).
+				Str(logs.EntryPointName, key.entryPoint).
+				Int("priority", key.priority).
+				Strs("collidingRouters", routers).
+				Msg("TLS terminated routes matching any SNI collide on the entry point, the routes are rejected, distinct HostSNIs or priorities are required")
+
+			delete(conf.Routers, router)
+			deleteRouteServices(conf, router)
+			delete(conf.Services, router+"-handshake-failure")
+
+			rejected = append(rejected, router)
+		}
+	}
+
+	return rejected
+}
+
+// mixedTLSModeSNI identifies the TLS routes claiming a HostSNI on an entry point.
+type mixedTLSModeSNI struct {
+	entryPoint string
+	hostSNI    string
+}
+
+// mixedTLSModeRouters holds the TLS passthrough and the TLS terminated routers claiming a HostSNI on an entry point.
+type mixedTLSModeRouters struct {
+	passthrough []string
+	terminated  []string
+}
+
+// with returns the routers with the given one added, according to its TLS mode.
+func (m mixedTLSModeRouters) with(router string, passthrough bool) mixedTLSModeRouters {
+	if passthrough {
+		if !slices.Contains(m.passthrough, router) {
+			m.passthrough = append(m.passthrough, router)
+		}
+		return m
+	}
+
+	if !slices.Contains(m.terminated, router) {
+		m.terminated = append(m.terminated, router)
+	}
+	return m
+}
+
+// resolveMixedTLSModeConflicts resolves, according to the MixedTLSModeConflict option, the HostSNIs claimed on an entry point
+// by both TLS passthrough and TLS terminated routes, as whether their connections are terminated would only depend on the priorities of the routes,
+// and returns the rejected routers.
+// The routes of distinct HostSNIs share an entry point whatever their TLS mode,
+// each connection being terminated or passed through according to the route matching its ClientHello.
+func (p *Provider) resolveMixedTLSModeConflicts(ctx context.Context, conf *dynamic.TCPConfiguration, tlsModes map[mixedTLSModeSNI]mixedTLSModeRouters) []string {
+	var rejected []string
+	for key, routers := range tlsModes {
+		if len(routers.passthrough) == 0 || len(routers.terminated) == 0 {
+			continue
+		}
+
+		var rejecting []string
+		switch p.MixedTLSModeConflict {
+		case mixedTLSModeConflictPassthrough:
+			rejecting = routers.terminated
+		case mixedTLSModeConflictTerminate:
+			rejecting = routers.passthrough
+		default:
+			rejecting = append(slices.Clone(routers.passthrough), routers.terminated...)
+		}
+
+		for _, router := range rejecting {
+			// The router may conflict on several HostSNIs or entry points.
+			if _, ok := conf.Routers[router]; !ok {
+				continue
+			}
+
+			log.Ctx(ctx).Error().
+				Str(logs.RouterName, router).
+				Str(logs.EntryPointName, key.entryPoint).
+				Str("hostSNI", key.hostSNI).
+				Strs("passthroughRouters", routers.passthrough).
+				Strs("terminatedRouters", routers.terminated).
+				Msg("TLS passthrough and TLS terminated routes claim the same HostSNI on the entry point, the route is rejected (see MixedTLSModeConflict option)")
+
+			delete(conf.Routers, router)
+			deleteRouteServices(conf, router)
+			delete(conf.Services, router+"-handshake-failure")
+
+			rejected = append(rejected, router)
+		}
+	}
+
+	return rejected
+}
+
+// deleteRouteServices deletes the named service of a rejected route, and its weighted services if any.
+func deleteRouteServices(conf *dynamic.TCPConfiguration, serviceName string) {
+	if svc := conf.Services[serviceName]; svc != nil && svc.Weighted != nil {
+		for _, wrrService := range svc.Weighted.Services {
+			delete(conf.Services, wrrService.Name)
+		}
+	}
+	delete(conf.Services, serviceName)
+}
+
+// matchesEndpointsPort reports whether the given endpoints port, of the given endpoints ports, is the one of the given Service port.
+// The ports are matched by name, except for a single-port Service whose port is unnamed,
+// which matches the sole endpoints port whatever its name, e.g. of endpoints managed by hand, or by another controller.
+func matchesEndpointsPort(service *corev1.Service, svcPort *corev1.ServicePort, ports []corev1.EndpointPort, port corev1.EndpointPort) bool {
+	if svcPort.Name == port.Name {
+		return true
+	}
+
+	return svcPort.Name == "" && len(service.Spec.Ports) == 1 && len(ports) == 1
+}
+
+// forbiddenError returns, for a forbidden error of the Kubernetes API, an error naming the permission missing to Traefik,
+// which is otherwise reported as a generic failure, and the given error as is otherwise.
+func forbiddenError(err error, resource, namespace string) error {
+	if !kerror.IsForbidden(err) {
+		return err
+	}
+
+	return fmt.Errorf("missing RBAC permission: Traefik is not allowed to get %[1]s in namespace %[2]q, "+
+		"grant the get, list and watch verbs on %[1]s in namespace %[2]q to its service account, with a Role or a ClusterRole: %[3]w", resource, namespace, err)
+}
+
+// conflictingServiceOptions returns the connection options of the other load balancer which differ from the ones of the first load balancer.
+func conflictingServiceOptions(first, other *dynamic.TCPServersLoadBalancer) []string {
+	var options []string
+
+	if (first.ProxyProtocol == nil) != (other.ProxyProtocol == nil) ||
+		first.ProxyProtocol != nil && first.ProxyProtocol.Version != other.ProxyProtocol.Version {
+		options = append(options, "proxyProtocol")
+	}
+
+	if first.ServersTransport != other.ServersTransport {
+		options = append(options, "serversTransport")
+	}
+
+	if (first.TerminationDelay == nil) != (other.TerminationDelay == nil) ||
+		first.TerminationDelay != nil && *first.TerminationDelay != *other.TerminationDelay {
+		options = append(options, "terminationDelay")
+	}
+
+	if first.HalfClose != other.HalfClose {
+		options = append(options, "halfClose")
+	}
+
+	if (first.NoDelay == nil) != (other.NoDelay == nil) ||
+		first.NoDelay != nil && *first.NoDelay != *other.NoDelay {
+		options = append(options, "noDelay")
+	}
+
+	if first.IdleTimeout != other.IdleTimeout {
+		options = append(options, "idleTimeout")
+	}
+
+	if first.MaxConnectionDuration != other.MaxConnectionDuration {
+		options = append(options, "maxConnectionDuration")
+	}
+
+	if first.PrefixFrame != other.PrefixFrame {
+		options = append(options, "prefixFrame")
+	}
+
+	return options
+}
+
+// applyServiceOptions sets the connection options of the first load balancer on the other load balancer.
+func applyServiceOptions(other, first *dynamic.TCPServersLoadBalancer) {
+	other.ProxyProtocol = first.ProxyProtocol
+	other.ServersTransport = first.ServersTransport
+	other.TerminationDelay = first.TerminationDelay
+	other.HalfClose = first.HalfClose
+	other.NoDelay = first.NoDelay
+	other.IdleTimeout = first.IdleTimeout
+	other.MaxConnectionDuration = first.MaxConnectionDuration
+	other.PrefixFrame = first.PrefixFrame
+}
+
+// isExternalNameAllowed reports whether the given ExternalName target is allowed by the ExternalNameAllowList option.
+// An IP target is allowed when it is within one of the listed CIDRs or IPs,
+// and a hostname target is allowed when it is listed, as hostnames are only resolved when dialing.
+func (p *Provider) isExternalNameAllowed(externalName string) (bool, error) {
+	if len(p.ExternalNameAllowList) == 0 {
+		return true, nil
+	}
+
+	var cidrs []string
+	for _, entry := range p.ExternalNameAllowList {
+		if strings.Contains(entry, "/") || net.ParseIP(entry) != nil {
+			cidrs = append(cidrs, entry)
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSuffix(entry, "."), strings.TrimSuffix(externalName, ".")) {
+			return true, nil
+		}
+	}
+
+	targetIP := net.ParseIP(externalName)
+	if targetIP == nil || len(cidrs) == 0 {
+		return false, nil
+	}
+
+	checker, err := ip.NewChecker(cidrs)
+	if err != nil {
+		return false, fmt.Errorf("invalid ExternalName allow list: %w", err)
+	}
+
+	return checker.ContainsIP(targetIP), nil
+}
+
+// errEndpointsNotFound is returned when a service has neither EndpointSlices, nor Endpoints.
+var errEndpointsNotFound = errors.New("endpoints not found")
+
+// loadEndpointSubsets returns the endpoint subsets of the named service,
+// built from its EndpointSlices when it has some, and from its Endpoints otherwise.
+// With the EndpointsFallback option, the Endpoints are also used when the EndpointSlices have no ready address while the Endpoints have some.
+// The EndpointSource option pins the source to either the Endpoints, or the EndpointSlices.
+func (p *Provider) loadEndpointSubsets(ctx context.Context, client Client, namespace, name string) ([]corev1.EndpointSubset, error) {
+	if p.EndpointSource == endpointSourceEndpoints {
+		return loadEndpoints(client, namespace, name)
+	}
+
+	endpointSlices, err := client.GetEndpointSlicesForService(namespace, name)
+	if err != nil {
+		return nil, forbiddenError(err, "endpointslices", namespace)
+	}
+
+	if len(endpointSlices) > 0 {
+		// The lister returns the EndpointSlices in no particular order,
+		// they are sorted not to generate a different configuration on each sync.
+		sort.Slice(endpointSlices, func(i, j int) bool {
+			return endpointSlices[i].Name < endpointSlices[j].Name
+		})
+
+		subsets := p.endpointSlicesSubsets(endpointSlices)
+		if !p.EndpointsFallback || hasReadyAddresses(subsets) {
+			return subsets, nil
+		}
+
+		endpoints, endpointsExists, err := client.GetEndpoints(namespace, name)
+		if err != nil || !endpointsExists || !hasReadyAddresses(endpoints.Subsets) {
+			return subsets, nil
+		}
+
+		log.Ctx(ctx).Warn().
+			Str("serviceName", name).
+			Str("serviceNamespace", namespace).
+			Msg("Using the Endpoints of the Service, as its EndpointSlices have no ready address while its Endpoints have some, they may lag behind")
+
+		return endpoints.Subsets, nil
+	}
+
+	if p.EndpointSource == endpointSourceEndpointSlices {
+		return nil, errEndpointsNotFound
+	}
+
+	return loadEndpoints(client, namespace, name)
+}
+
+// loadEndpoints returns the endpoint subsets of the Endpoints of the named service.
+func loadEndpoints(client Client, namespace, name string) ([]corev1.EndpointSubset, error) {
+	endpoints, endpointsExists, err := client.GetEndpoints(namespace, name)
+	if err != nil {
+		return nil, forbiddenError(err, "endpoints", namespace)
+	}
+
+	if !endpointsExists {
+		return nil, errEndpointsNotFound
+	}
+
+	return endpoints.Subsets, nil
+}
+
+// hasReadyAddresses reports whether one of the given endpoint subsets has a ready address.
+func hasReadyAddresses(subsets []corev1.EndpointSubset) bool {
+	for _, subset := range subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// endpointSlicesSubsets converts the given EndpointSlices to endpoint subsets,
+// where the endpoints satisfying the EndpointConditions option are the ready addresses,
+// and the other ones are the not ready addresses.
+func (p *Provider) endpointSlicesSubsets(endpointSlices []*discoveryv1.EndpointSlice) []corev1.EndpointSubset {
+	var subsets []corev1.EndpointSubset
+	for _, endpointSlice := range endpointSlices {
+		// FQDN endpoints are deprecated, and not supported.
+		if endpointSlice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+
+		var subset corev1.EndpointSubset
+		for _, port := range endpointSlice.Ports {
+			if port.Port == nil {
+				continue
+			}
+
+			endpointPort := corev1.EndpointPort{Port: *port.Port}
+			if port.Name != nil {
+				endpointPort.Name = *port.Name
+			}
+			if port.Protocol != nil {
+				endpointPort.Protocol = *port.Protocol
+			}
+
+			subset.Ports = append(subset.Ports, endpointPort)
+		}
+
+		for _, endpoint := range endpointSlice.Endpoints {
+			usable := p.isEndpointUsable(endpoint.Conditions)
+
+			for _, address := range endpoint.Addresses {
+				addr := corev1.EndpointAddress{IP: address, NodeName: endpoint.NodeName, TargetRef: endpoint.TargetRef}
+
+				if usable {
+					subset.Addresses = append(subset.Addresses, addr)
+				} else {
+					subset.NotReadyAddresses = append(subset.NotReadyAddresses, addr)
+				}
+			}
+		}
+
+		subsets = append(subsets, subset)
+	}
+
+	return subsets
+}
+
+// isEndpointUsable reports whether one of the conditions listed by the EndpointConditions option,
+// Ready by default, is true for the endpoint.
+// As the Kubernetes API specifies, an unknown Ready condition is interpreted as ready,
+// and an unknown Serving condition defers to the Ready condition.
+func (p *Provider) isEndpointUsable(conditions discoveryv1.EndpointConditions) bool {
+	ready := conditions.Ready == nil || *conditions.Ready
+
+	serving := ready
+	if conditions.Serving != nil {
+		serving = *conditions.Serving
+	}
+
+	accepted := p.EndpointConditions
+	if len(accepted) == 0 {
+		accepted = []string{endpointConditionReady}
+	}
+
+	for _, condition := range accepted {
+		switch condition {
+		case endpointConditionReady:
+			if ready {
+				return true
+			}
+		case endpointConditionServing:
+			if serving {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// endpointServers returns the servers for the given endpoint addresses,
+// restricted to the addresses whose target pods pass the filter, if any.
+func endpointServers(client Client, namespace string, addrs []corev1.EndpointAddress, port int32, filter podFilter) ([]dynamic.TCPServer, error) {
+	var servers []dynamic.TCPServer
+	for _, addr := range addrs {
+		if filter.enabled() {
+			match, err := filter.matches(client, namespace, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			if !match {
+				continue
+			}
+		}
+
+		servers = append(servers, dynamic.TCPServer{
+			Address: net.JoinHostPort(addr.IP, strconv.Itoa(int(port))),
+		})
+	}
+
+	return servers, nil
+}
+
+// deduplicateServers removes the servers listed more than once with the same address, keeping the first ones,
+// and returns the number of removed servers.
+func deduplicateServers(servers []dynamic.TCPServer) ([]dynamic.TCPServer, int) {
+	seen := make(map[string]struct{}, len(servers))
+
+	var deduplicated []dynamic.TCPServer
+	for _, server := range servers {
+		if _, ok := seen[server.Address]; ok {
+			continue
+		}
+
+		seen[server.Address] = struct{}{}
+		deduplicated = append(deduplicated, server)
+	}
+
+	return deduplicated, len(servers) - len(deduplicated)
+}
+
+// portProtocol returns the given port protocol, defaulting to TCP as the Kubernetes API does.
+func portProtocol(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return protocol
+}
+
+// podFilter restricts the servers to the endpoints whose target pods match the selector, and have the readiness gate.
+type podFilter struct {
+	selector      labels.Selector
+	readinessGate *traefikv1alpha1.ReadinessGate
+}
+
+// enabled reports whether the filter requires getting the target pods of the endpoints.
+func (f podFilter) enabled() bool {
+	return f.selector != nil || f.readinessGate != nil
+}
+
+// matches reports whether the pod targeted by the given endpoint address passes the filter.
+// Addresses which are not backed by a pod never match.
+func (f podFilter) matches(client Client, namespace string, addr corev1.EndpointAddress) (bool, error) {
+	if addr.TargetRef == nil || addr.TargetRef.Kind != "Pod" {
+		return false, nil
+	}
+
+	pod, exists, err := client.GetPod(namespace, addr.TargetRef.Name)
+	if err != nil {
+		return false, fmt.Errorf("getting pod %s/%s: %w", namespace, addr.TargetRef.Name, err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	if f.selector != nil && !f.selector.Matches(labels.Set(pod.Labels)) {
+		return false, nil
+	}
+
+	return f.readinessGate == nil || hasReadinessGate(pod, f.readinessGate), nil
+}
+
+// hasReadinessGate reports whether the given pod has the custom readiness signal.
+func hasReadinessGate(pod *corev1.Pod, gate *traefikv1alpha1.ReadinessGate) bool {
+	if gate.Annotation != "" && pod.Annotations[gate.Annotation] != "true" {
+		return false
+	}
+
+	if gate.ConditionType == "" {
+		return true
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if string(condition.Type) == gate.ConditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+func (p *Provider) makeTCPServersTransportKey(parentNamespace string, serversTransportName string) (string, error) {
+	if serversTransportName == "" {
+		return "", nil
+	}
+
+	if !p.AllowCrossNamespace && strings.HasSuffix(serversTransportName, providerNamespaceSeparator+providerName) {
+		// Since we are not able to know if another namespace is in the name (namespace-name@kubernetescrd),
 		// if the provider namespace kubernetescrd is used,
 		// we don't allow this format to avoid cross namespace references.
 		return "", fmt.Errorf("invalid reference to serversTransport %s: namespace-name@kubernetescrd format is not allowed when crossnamespace is disallowed", serversTransportName)
@@ -341,7 +1639,7 @@ func (p *Provider) makeTCPServersTransportKey(parentNamespace string, serversTra
 }
 
 // getTLSTCP mutates tlsConfigs.
-func getTLSTCP(ctx context.Context, ingressRoute *traefikv1alpha1.IngressRouteTCP, k8sClient Client, tlsConfigs map[string]*tls.CertAndStores) error {
+func (p *Provider) getTLSTCP(ctx context.Context, ingressRoute *traefikv1alpha1.IngressRouteTCP, k8sClient Client, tlsConfigs map[string]*tls.CertAndStores) error {
 	if ingressRoute.Spec.TLS == nil {
 		return nil
 	}
@@ -352,7 +1650,7 @@ func getTLSTCP(ctx context.Context, ingressRoute *traefikv1alpha1.IngressRouteTC
 
 	configKey := ingressRoute.Namespace + "/" + ingressRoute.Spec.TLS.SecretName
 	if _, tlsExists := tlsConfigs[configKey]; !tlsExists {
-		tlsConf, err := getTLS(k8sClient, ingressRoute.Spec.TLS.SecretName, ingressRoute.Namespace)
+		tlsConf, err := p.getTLSWithRetry(ctx, k8sClient, ingressRoute.Spec.TLS.SecretName, ingressRoute.Namespace)
 		if err != nil {
 			return err
 		}
@@ -360,5 +1658,131 @@ func getTLSTCP(ctx context.Context, ingressRoute *traefikv1alpha1.IngressRouteTC
 		tlsConfigs[configKey] = tlsConf
 	}
 
+	warnOnCertificateSNIMismatch(ctx, ingressRoute, tlsConfigs[configKey])
+
 	return nil
 }
+
+// warnOnCertificateSNIMismatch warns about the routes whose HostSNI values are none covered by the SANs of the certificate.
+// The certificate is served anyway, but the clients are likely to reject it.
+func warnOnCertificateSNIMismatch(ctx context.Context, ingressRoute *traefikv1alpha1.IngressRouteTCP, certAndStores *tls.CertAndStores) {
+	logger := log.Ctx(ctx)
+
+	content, err := certAndStores.Certificate.CertFile.Read()
+	if err != nil {
+		logger.Debug().Err(err).Msg("Unable to read the certificate to check its SANs against the route rules")
+		return
+	}
+
+	certificate, err := parseLeafCertificate(content)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Unable to parse the certificate to check its SANs against the route rules")
+		return
+	}
+
+	for _, route := range ingressRoute.Spec.Routes {
+		// The rule errors are reported when the routes are loaded.
+		rule, err := routeTCPRule(route)
+		if err != nil {
+			continue
+		}
+
+		// The rule parsing errors are reported when the router is built.
+		hostSNIs, err := tcpmuxer.ParseHostSNI(rule)
+		if err != nil || len(hostSNIs) == 0 {
+			continue
+		}
+
+		covered := slices.ContainsFunc(hostSNIs, func(hostSNI string) bool {
+			return hostSNI == "*" || certificate.VerifyHostname(hostSNI) == nil
+		})
+		if covered {
+			continue
+		}
+
+		logger.Warn().
+			Str("rule", rule).
+			Strs("certificateSANs", certificate.DNSNames).
+			Msgf("None of the HostSNI values of the route rule are covered by the certificate of the secret %s, clients are likely to get certificate mismatches", ingressRoute.Spec.TLS.SecretName)
+	}
+}
+
+// routeTCPRule returns the rule of the route, which is either its match rule,
+// or its list of match rules combined with the OR operator, each of them being validated independently.
+func routeTCPRule(route traefikv1alpha1.RouteTCP) (string, error) {
+	if len(route.Matches) == 0 {
+		if len(route.Match) == 0 {
+			return "", errors.New("empty match rule")
+		}
+
+		return route.Match, nil
+	}
+
+	if len(route.Match) > 0 {
+		return "", errors.New("match and matches cannot be both defined")
+	}
+
+	rules := make([]string, 0, len(route.Matches))
+	for i, match := range route.Matches {
+		if strings.TrimSpace(match) == "" {
+			return "", fmt.Errorf("matches[%d]: empty match rule", i)
+		}
+
+		if _, err := tcpmuxer.ParseHostSNI(match); err != nil {
+			return "", fmt.Errorf("matches[%d]: invalid rule %q: %w", i, match, err)
+		}
+
+		rules = append(rules, match)
+	}
+
+	if len(rules) == 1 {
+		return rules[0], nil
+	}
+
+	return "(" + strings.Join(rules, ") || (") + ")", nil
+}
+
+// parseLeafCertificate parses the first certificate of the given PEM encoded chain.
+func parseLeafCertificate(content []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// deduplicateIngressRouteTCPs removes the IngressRouteTCP objects listed more than once under the same UID,
+// as it happens when the same object is listed through several CRD versions during a version migration.
+// For each UID, the object with the highest generation is kept, and the first listed one on equality.
+// The order of the list is otherwise preserved.
+func deduplicateIngressRouteTCPs(ctx context.Context, ingressRouteTCPs []*traefikv1alpha1.IngressRouteTCP) []*traefikv1alpha1.IngressRouteTCP {
+	indexes := make(map[types.UID]int)
+
+	var result []*traefikv1alpha1.IngressRouteTCP
+	for _, ingressRouteTCP := range ingressRouteTCPs {
+		if ingressRouteTCP.UID == "" {
+			result = append(result, ingressRouteTCP)
+			continue
+		}
+
+		index, ok := indexes[ingressRouteTCP.UID]
+		if !ok {
+			indexes[ingressRouteTCP.UID] = len(result)
+			result = append(result, ingressRouteTCP)
+			continue
+		}
+
+		log.Ctx(ctx).Debug().
+			Str("ingress", ingressRouteTCP.Name).
+			Str("namespace", ingressRouteTCP.Namespace).
+			Str("uid", string(ingressRouteTCP.UID)).
+			Msg("IngressRouteTCP listed more than once, merging")
+
+		if ingressRouteTCP.Generation > result[index].Generation {
+			result[index] = ingressRouteTCP
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,141 @@
+package crd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// localNodeNameEnv is the environment variable holding the name of the node Traefik runs on by default,
+// as usually set from the downward API in the DaemonSet deployments.
+const localNodeNameEnv = "NODE_NAME"
+
+// LocalNodeShedding configures the exclusion of the endpoints of the node Traefik runs on from the TCP services,
+// while the load of the node exceeds a threshold.
+type LocalNodeShedding struct {
+	NodeName      string          `description:"Name of the node Traefik runs on, defaults to the value of the NODE_NAME environment variable." json:"nodeName,omitempty" toml:"nodeName,omitempty" yaml:"nodeName,omitempty" export:"true"`
+	LoadThreshold float64         `description:"Load average over one minute of the node, per CPU available to Traefik, above which the endpoints of the node are excluded." json:"loadThreshold,omitempty" toml:"loadThreshold,omitempty" yaml:"loadThreshold,omitempty" export:"true"`
+	CheckInterval ptypes.Duration `description:"Interval at which the load of the node is checked." json:"checkInterval,omitempty" toml:"checkInterval,omitempty" yaml:"checkInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (s *LocalNodeShedding) SetDefaults() {
+	s.LoadThreshold = 1
+	s.CheckInterval = ptypes.Duration(10 * time.Second)
+}
+
+// localNodePressureEvent triggers a configuration refresh when the pressure on the local node starts or ends.
+type localNodePressureEvent struct{}
+
+// localNodeName returns the name of the node Traefik runs on.
+func (s *LocalNodeShedding) localNodeName() (string, error) {
+	if s.NodeName != "" {
+		return s.NodeName, nil
+	}
+
+	if name := os.Getenv(localNodeNameEnv); name != "" {
+		return name, nil
+	}
+
+	return "", fmt.Errorf("local node shedding requires the node name, from the nodeName option or the %s environment variable", localNodeNameEnv)
+}
+
+// watchLocalNodePressure checks the load of the local node at the check interval,
+// and sends an event on the returned channel whenever it starts, or stops, exceeding the threshold.
+func (p *Provider) watchLocalNodePressure(ctx context.Context) <-chan interface{} {
+	events := make(chan interface{}, 1)
+
+	loadSignal := p.loadSignal
+	if loadSignal == nil {
+		loadSignal = nodeLoadPerCPU
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(p.LocalNodeShedding.CheckInterval))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			load, err := loadSignal()
+			if err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("Unable to read the load of the local node")
+				continue
+			}
+
+			underPressure := load > p.LocalNodeShedding.LoadThreshold
+			if p.localNodeUnderPressure.Swap(underPressure) == underPressure {
+				continue
+			}
+
+			if underPressure {
+				log.Ctx(ctx).Warn().Float64("load", load).Str("nodeName", p.localNodeName).
+					Msgf("Local node load exceeds the threshold of %v, excluding its endpoints from the TCP services", p.LocalNodeShedding.LoadThreshold)
+			} else {
+				log.Ctx(ctx).Info().Float64("load", load).Str("nodeName", p.localNodeName).
+					Msg("Local node load is back under the threshold, including its endpoints in the TCP services again")
+			}
+
+			select {
+			case events <- localNodePressureEvent{}:
+			default:
+				// A refresh is already pending.
+			}
+		}
+	}()
+
+	return events
+}
+
+// shedLocalNode reports whether the endpoints of the local node are currently excluded.
+func (p *Provider) shedLocalNode() bool {
+	return p.LocalNodeShedding != nil && p.localNodeUnderPressure.Load()
+}
+
+// splitLocalNodeAddresses splits the given endpoint addresses between the ones of the local node, and the other ones.
+func (p *Provider) splitLocalNodeAddresses(addrs []corev1.EndpointAddress) (others, local []corev1.EndpointAddress) {
+	for _, addr := range addrs {
+		if addr.NodeName != nil && *addr.NodeName == p.localNodeName {
+			local = append(local, addr)
+			continue
+		}
+
+		others = append(others, addr)
+	}
+
+	return others, local
+}
+
+// nodeLoadPerCPU returns the load average over one minute of the node, divided by the number of CPUs available to Traefik.
+// As /proc/loadavg is not namespaced, it is the load of the whole node, even from a container.
+func nodeLoadPerCPU() (float64, error) {
+	content, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return 0, errors.New("empty /proc/loadavg")
+	}
+
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing /proc/loadavg: %w", err)
+	}
+
+	return load / float64(runtime.NumCPU()), nil
+}
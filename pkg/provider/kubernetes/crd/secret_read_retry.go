@@ -0,0 +1,72 @@
+package crd
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/tls"
+	kerror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// SecretReadRetry configures the retry of the reads of the TLS secrets of the TCP routes failing transiently,
+// e.g. during an unavailability of the Kubernetes API, instead of breaking their TLS configuration for the whole sync.
+type SecretReadRetry struct {
+	Attempts        int             `description:"Maximum number of attempts to read a secret, the first one included." json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+	InitialInterval ptypes.Duration `description:"Wait time before the first retry, doubled on each retry." json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (r *SecretReadRetry) SetDefaults() {
+	r.Attempts = 3
+	r.InitialInterval = ptypes.Duration(time.Second)
+}
+
+// getTLSWithRetry returns the TLS certificate of the given secret,
+// retrying the transient failures to read it when the SecretReadRetry option is set.
+// The secrets which do not exist, or are invalid, are not retried.
+func (p *Provider) getTLSWithRetry(ctx context.Context, k8sClient Client, secretName, namespace string) (*tls.CertAndStores, error) {
+	if p.SecretReadRetry == nil {
+		return getTLS(k8sClient, secretName, namespace)
+	}
+
+	var certAndStores *tls.CertAndStores
+	operation := func() error {
+		var err error
+		certAndStores, err = getTLS(k8sClient, secretName, namespace)
+		if err != nil && !isTransientAPIError(err) {
+			return backoff.Permanent(err)
+		}
+
+		return err
+	}
+
+	notify := func(err error, delay time.Duration) {
+		log.Ctx(ctx).Warn().Err(err).
+			Str("secret", namespace+"/"+secretName).
+			Msgf("Transient failure reading the TLS secret, retrying in %s", delay)
+	}
+
+	exponential := backoff.NewExponentialBackOff()
+	exponential.InitialInterval = time.Duration(p.SecretReadRetry.InitialInterval)
+	exponential.Multiplier = 2
+
+	retries := backoff.WithMaxRetries(exponential, uint64(max(p.SecretReadRetry.Attempts-1, 0)))
+	if err := backoff.RetryNotify(operation, backoff.WithContext(retries, ctx), notify); err != nil {
+		return nil, err
+	}
+
+	return certAndStores, nil
+}
+
+// isTransientAPIError reports whether the given error of the Kubernetes API is transient, i.e. the request may succeed if retried.
+func isTransientAPIError(err error) bool {
+	return kerror.IsServerTimeout(err) ||
+		kerror.IsTimeout(err) ||
+		kerror.IsTooManyRequests(err) ||
+		kerror.IsInternalError(err) ||
+		kerror.IsServiceUnavailable(err) ||
+		kerror.IsUnexpectedServerError(err)
+}
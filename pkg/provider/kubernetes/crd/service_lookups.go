@@ -0,0 +1,167 @@
+package crd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	traefikv1alpha1 "github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd/traefikio/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// serviceLookup holds the results of the lookups of a Kubernetes Service and of its endpoints.
+type serviceLookup struct {
+	service       *corev1.Service
+	serviceExists bool
+	serviceErr    error
+
+	endpointSlicesLooked bool
+	endpointSlices       []*discoveryv1.EndpointSlice
+	endpointSlicesErr    error
+
+	endpointsLooked bool
+	endpoints       *corev1.Endpoints
+	endpointsExists bool
+	endpointsErr    error
+}
+
+// lookupClient is a Client serving the prefetched Service and endpoints lookups,
+// the other lookups being forwarded to the wrapped Client.
+type lookupClient struct {
+	Client
+
+	lookups map[types.NamespacedName]*serviceLookup
+}
+
+func (c lookupClient) GetService(namespace, name string) (*corev1.Service, bool, error) {
+	lookup, ok := c.lookups[types.NamespacedName{Namespace: namespace, Name: name}]
+	if !ok {
+		return c.Client.GetService(namespace, name)
+	}
+
+	return lookup.service, lookup.serviceExists, lookup.serviceErr
+}
+
+func (c lookupClient) GetEndpointSlicesForService(namespace, serviceName string) ([]*discoveryv1.EndpointSlice, error) {
+	lookup, ok := c.lookups[types.NamespacedName{Namespace: namespace, Name: serviceName}]
+	if !ok || !lookup.endpointSlicesLooked {
+		return c.Client.GetEndpointSlicesForService(namespace, serviceName)
+	}
+
+	// The EndpointSlices are sorted in place by their consumers, the cached ones are not shared.
+	return append([]*discoveryv1.EndpointSlice(nil), lookup.endpointSlices...), lookup.endpointSlicesErr
+}
+
+func (c lookupClient) GetEndpoints(namespace, name string) (*corev1.Endpoints, bool, error) {
+	lookup, ok := c.lookups[types.NamespacedName{Namespace: namespace, Name: name}]
+	if !ok || !lookup.endpointsLooked {
+		return c.Client.GetEndpoints(namespace, name)
+	}
+
+	return lookup.endpoints, lookup.endpointsExists, lookup.endpointsErr
+}
+
+// prefetchServiceLookups looks up the Services targeted by the given IngressRouteTCPs, and their endpoints,
+// with at most MaxConcurrentLookups concurrent lookups, and returns a Client serving the results.
+// The configuration is still built sequentially from the results, in the order of the IngressRouteTCPs,
+// the lookups being only performed ahead of time.
+func (p *Provider) prefetchServiceLookups(ctx context.Context, client Client, ingressRouteTCPs []*traefikv1alpha1.IngressRouteTCP) Client {
+	if p.MaxConcurrentLookups < 1 {
+		return client
+	}
+
+	lookups := make(map[types.NamespacedName]*serviceLookup)
+	var names []types.NamespacedName
+
+	addService := func(parentNamespace string, service traefikv1alpha1.ServiceTCP) {
+		namespace := parentNamespace
+		if len(service.Namespace) > 0 {
+			// The disallowed cross namespace references are reported when the service is created.
+			if !isNamespaceAllowed(p.AllowCrossNamespace, parentNamespace, service.Namespace) {
+				return
+			}
+			namespace = service.Namespace
+		}
+
+		name := types.NamespacedName{Namespace: namespace, Name: service.Name}
+		if _, ok := lookups[name]; ok {
+			return
+		}
+
+		lookups[name] = &serviceLookup{}
+		names = append(names, name)
+	}
+
+	for _, ingressRouteTCP := range ingressRouteTCPs {
+		if !shouldProcessIngress(p.IngressClass, ingressRouteTCP.Annotations[annotationKubernetesIngressClass]) {
+			continue
+		}
+
+		for _, route := range ingressRouteTCP.Spec.Routes {
+			for _, service := range route.Services {
+				addService(ingressRouteTCP.Namespace, service)
+			}
+		}
+
+		if tls := ingressRouteTCP.Spec.TLS; tls != nil && !tls.Passthrough && tls.HandshakeFailureService != nil {
+			addService(ingressRouteTCP.Namespace, *tls.HandshakeFailureService)
+		}
+	}
+
+	if len(names) == 0 {
+		return client
+	}
+
+	queue := make(chan types.NamespacedName)
+
+	var wg sync.WaitGroup
+	for range min(p.MaxConcurrentLookups, len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Each lookup is only written by the worker it is queued to.
+			for name := range queue {
+				p.lookupService(client, name, lookups[name])
+			}
+		}()
+	}
+
+	for _, name := range names {
+		queue <- name
+	}
+	close(queue)
+
+	wg.Wait()
+
+	log.Ctx(ctx).Debug().Msgf("Looked up %d services with at most %d concurrent lookups", len(names), p.MaxConcurrentLookups)
+
+	return lookupClient{Client: client, lookups: lookups}
+}
+
+// lookupService looks up the named Service, and its endpoints unless it is an ExternalName Service.
+// The Endpoints are only looked up when the Service has no EndpointSlices, or with the EndpointsFallback option,
+// as the TCP services only use them in these cases, and only the source pinned by the EndpointSource option is looked up.
+func (p *Provider) lookupService(client Client, name types.NamespacedName, lookup *serviceLookup) {
+	lookup.service, lookup.serviceExists, lookup.serviceErr = client.GetService(name.Namespace, name.Name)
+	if lookup.serviceErr != nil || !lookup.serviceExists || lookup.service.Spec.Type == corev1.ServiceTypeExternalName {
+		return
+	}
+
+	if p.EndpointSource != endpointSourceEndpoints {
+		lookup.endpointSlicesLooked = true
+		lookup.endpointSlices, lookup.endpointSlicesErr = client.GetEndpointSlicesForService(name.Namespace, name.Name)
+		if lookup.endpointSlicesErr != nil || (len(lookup.endpointSlices) > 0 && !p.EndpointsFallback) {
+			return
+		}
+	}
+
+	if p.EndpointSource == endpointSourceEndpointSlices {
+		return
+	}
+
+	lookup.endpointsLooked = true
+	lookup.endpoints, lookup.endpointsExists, lookup.endpointsErr = client.GetEndpoints(name.Namespace, name.Name)
+}
@@ -25,7 +25,10 @@ type IngressRouteTCPSpec struct {
 type RouteTCP struct {
 	// Match defines the router's rule.
 	// More info: https://doc.traefik.io/traefik/v3.0/routing/routers/#rule_1
-	Match string `json:"match"`
+	Match string `json:"match,omitempty"`
+	// Matches defines a list of rules, combined with the OR operator into the router's rule.
+	// Each rule is validated independently, and Matches cannot be defined along with Match.
+	Matches []string `json:"matches,omitempty"`
 	// Priority defines the router's priority.
 	// More info: https://doc.traefik.io/traefik/v3.0/routing/routers/#priority_1
 	Priority int `json:"priority,omitempty"`
@@ -59,6 +62,13 @@ type TLSTCP struct {
 	// Domains defines the list of domains that will be used to issue certificates.
 	// More info: https://doc.traefik.io/traefik/v3.0/routing/routers/#domains
 	Domains []types.Domain `json:"domains,omitempty"`
+	// CloseOnCertificateChange defines whether the active connections are closed when the certificate they were served expires, or is rotated,
+	// so that the clients handshake again with the current certificate.
+	CloseOnCertificateChange bool `json:"closeOnCertificateChange,omitempty"`
+	// HandshakeFailureService defines the service to which the raw connections whose TLS handshake fails are forwarded,
+	// the bytes read during the handshake being replayed to it, which helps to diagnose client compatibility issues.
+	// By default, the connections whose TLS handshake fails are closed.
+	HandshakeFailureService *ServiceTCP `json:"handshakeFailureService,omitempty"`
 }
 
 // ServiceTCP defines an upstream TCP service to proxy traffic to.
@@ -98,6 +108,87 @@ type ServiceTCP struct {
 	// It allows services to be reachable when Traefik runs externally from the Kubernetes cluster but within the same network of the nodes.
 	// By default, NodePortLB is false.
 	NodePortLB bool `json:"nodePortLB,omitempty"`
+	// PodSelector defines a label selector restricting the servers to the Kubernetes Service endpoints
+	// whose target pods match it.
+	// It requires the list and watch permissions on pods in the Kubernetes Service namespace.
+	PodSelector string `json:"podSelector,omitempty"`
+	// ReadinessGate defines a custom readiness signal restricting the servers to the Kubernetes Service endpoints
+	// whose target pods have it, on top of the Kubernetes readiness, e.g. for the pods to complete their warmup.
+	// It requires the list and watch permissions on pods in the Kubernetes Service namespace.
+	ReadinessGate *ReadinessGate `json:"readinessGate,omitempty"`
+	// Sticky defines the sticky sessions configuration.
+	// When the client certificate stickiness is enabled, the connections presenting the same verified client certificate
+	// are forwarded to the same server.
+	Sticky *dynamic.TCPSticky `json:"sticky,omitempty"`
+	// HealthCheck defines the health check of the servers.
+	// Without send and expect payloads, the health check only dials the servers,
+	// otherwise it sends the payload and validates that the response starts with the expected one.
+	HealthCheck *dynamic.TCPServerHealthCheck `json:"healthCheck,omitempty"`
+	// HalfClose defines whether the proxy propagates the half-close of a connection by one of its peers to the other peer,
+	// instead of fully terminating the connection after the termination delay.
+	// By default, HalfClose is false.
+	HalfClose bool `json:"halfClose,omitempty"`
+	// NoDelay defines whether Nagle's algorithm is disabled (TCP_NODELAY) on both the client and the server connections.
+	// Disabling it lowers the latency of the small writes, enabling it improves the throughput of the bulk transfers.
+	// By default, the option of the connections is left to the platform default, which disables Nagle's algorithm.
+	NoDelay *bool `json:"noDelay,omitempty"`
+	// ZoneWeights defines the weights of the availability zones of the Kubernetes Service endpoints,
+	// the connections are distributed across the zones by weight, and within a zone by round robin.
+	// The endpoints of the zones which are not listed, or whose zone is unknown, are not used.
+	// It requires the Kubernetes Service endpoints to be listed by EndpointSlices.
+	ZoneWeights map[string]int `json:"zoneWeights,omitempty"`
+	// Strategy defines the load balancing strategy between the servers.
+	// Supported values are: roundRobin (default), and consistentHashing,
+	// which forwards the connections of a client IP to the same server, only remapping the clients of the servers which are added or removed.
+	// +kubebuilder:validation:Enum=roundRobin;consistentHashing
+	Strategy string `json:"strategy,omitempty"`
+	// HashSeed defines a seed mixed into the hashes assigning the clients to the servers,
+	// with the consistentHashing strategy, and with the client certificate stickiness.
+	// Changing the seed reshuffles the assignment of the clients to the servers.
+	HashSeed string `json:"hashSeed,omitempty"`
+	// PerAttemptDialTimeout defines the timeout of each attempt to dial a server.
+	// When set, the connections fail over to the next servers when a server cannot be dialed.
+	PerAttemptDialTimeout *intstr.IntOrString `json:"perAttemptDialTimeout,omitempty"`
+	// ConnectTimeout defines the overall time budget to connect to a server, across all the dial attempts of a connection.
+	// It requires PerAttemptDialTimeout, and cannot be lower than it.
+	ConnectTimeout *intstr.IntOrString `json:"connectTimeout,omitempty"`
+	// IdleTimeout defines the duration after which the connections on which no data is transferred in either direction are closed.
+	// By default, the connections are never closed for idleness.
+	IdleTimeout *intstr.IntOrString `json:"idleTimeout,omitempty"`
+	// MaxConnectionDuration defines the duration after which the connections are closed, whether data is transferred or not.
+	// Unlike IdleTimeout, it is not postponed by the activity of the connections.
+	// By default, the connections are not limited in duration.
+	MaxConnectionDuration *intstr.IntOrString `json:"maxConnectionDuration,omitempty"`
+	// MaxConnectionsPerServer defines the maximum number of connections forwarded concurrently to each server,
+	// the servers at their maximum being skipped by the load balancing until one of their connections is closed.
+	// By default, the connections per server are not limited.
+	// +kubebuilder:validation:Minimum=0
+	MaxConnectionsPerServer int `json:"maxConnectionsPerServer,omitempty"`
+	// Audit defines whether an audit entry is logged for each connection forwarded to a server, before connecting to it,
+	// capturing the client address, the SNI, the router, and the server.
+	// The audit entries are logged regardless of the log level.
+	// By default, Audit is false.
+	Audit bool `json:"audit,omitempty"`
+	// PrefixFrame defines the Go template of a metadata frame written to the server connections before the data of the client,
+	// for the servers to know the routing context of the connections.
+	// The template data are the attributes of the connection: router, and sni for the TLS connections.
+	// By default, no frame is written.
+	PrefixFrame string `json:"prefixFrame,omitempty"`
+	// Priority defines the priority of the connections of the service under connection pressure, from -10 to 10,
+	// the connections of the lowest priority services being rejected first by the TCP admission.
+	// By default, Priority is 0.
+	// +kubebuilder:validation:Minimum=-10
+	// +kubebuilder:validation:Maximum=10
+	Priority int `json:"priority,omitempty"`
+}
+
+// ReadinessGate holds the custom readiness signal of the pods targeted by the Kubernetes Service endpoints.
+// When both the condition type and the annotation are defined, the pods must have both.
+type ReadinessGate struct {
+	// ConditionType defines the type of the pod condition whose status must be True.
+	ConditionType string `json:"conditionType,omitempty"`
+	// Annotation defines the name of the pod annotation whose value must be "true".
+	Annotation string `json:"annotation,omitempty"`
 }
 
 // +genclient
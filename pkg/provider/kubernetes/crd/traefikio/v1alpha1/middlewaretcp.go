@@ -34,6 +34,10 @@ type MiddlewareTCPSpec struct {
 	// This middleware accepts/refuses connections based on the client IP.
 	// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/ipallowlist/
 	IPAllowList *dynamic.TCPIPAllowList `json:"ipAllowList,omitempty"`
+	// ConnAuth defines the ConnAuth middleware configuration.
+	// This middleware accepts/refuses connections based on the decision of a webhook.
+	// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/connauth/
+	ConnAuth *dynamic.TCPConnAuth `json:"connAuth,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
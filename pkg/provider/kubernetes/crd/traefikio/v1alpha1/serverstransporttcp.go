@@ -31,6 +31,9 @@ type ServersTransportTCPSpec struct {
 	DialTimeout *intstr.IntOrString `json:"dialTimeout,omitempty"`
 	// DialKeepAlive is the interval between keep-alive probes for an active network connection. If zero, keep-alive probes are sent with a default value (currently 15 seconds), if supported by the protocol and operating system. Network protocols or operating systems that do not support keep-alives ignore this field. If negative, keep-alive probes are disabled.
 	DialKeepAlive *intstr.IntOrString `json:"dialKeepAlive,omitempty"`
+	// LocalAddress defines the local IP address the connections to the backend servers originate from.
+	// It must be assigned to an interface of the host.
+	LocalAddress string `json:"localAddress,omitempty"`
 	// TerminationDelay defines the delay to wait before fully terminating the connection, after one connected peer has closed its writing capability.
 	TerminationDelay *intstr.IntOrString `json:"terminationDelay,omitempty"`
 	// TLS defines the TLS configuration
@@ -897,6 +897,11 @@ func (in *MiddlewareTCPSpec) DeepCopyInto(out *MiddlewareTCPSpec) {
 		*out = new(dynamic.TCPIPAllowList)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConnAuth != nil {
+		in, out := &in.ConnAuth, &out.ConnAuth
+		*out = new(dynamic.TCPConnAuth)
+		**out = **in
+	}
 	return
 }
 
@@ -1003,6 +1008,22 @@ func (in *RateLimit) DeepCopy() *RateLimit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessGate) DeepCopyInto(out *ReadinessGate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessGate.
+func (in *ReadinessGate) DeepCopy() *ReadinessGate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResponseForwarding) DeepCopyInto(out *ResponseForwarding) {
 	*out = *in
@@ -1067,6 +1088,11 @@ func (in *Route) DeepCopy() *Route {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTCP) DeepCopyInto(out *RouteTCP) {
 	*out = *in
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]ServiceTCP, len(*in))
@@ -1348,6 +1374,53 @@ func (in *ServiceTCP) DeepCopyInto(out *ServiceTCP) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ReadinessGate != nil {
+		in, out := &in.ReadinessGate, &out.ReadinessGate
+		*out = new(ReadinessGate)
+		**out = **in
+	}
+	if in.Sticky != nil {
+		in, out := &in.Sticky, &out.Sticky
+		*out = new(dynamic.TCPSticky)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(dynamic.TCPServerHealthCheck)
+		**out = **in
+	}
+	if in.NoDelay != nil {
+		in, out := &in.NoDelay, &out.NoDelay
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ZoneWeights != nil {
+		in, out := &in.ZoneWeights, &out.ZoneWeights
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PerAttemptDialTimeout != nil {
+		in, out := &in.PerAttemptDialTimeout, &out.PerAttemptDialTimeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.ConnectTimeout != nil {
+		in, out := &in.ConnectTimeout, &out.ConnectTimeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxConnectionDuration != nil {
+		in, out := &in.MaxConnectionDuration, &out.MaxConnectionDuration
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 	return
 }
 
@@ -1692,6 +1765,11 @@ func (in *TLSTCP) DeepCopyInto(out *TLSTCP) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HandshakeFailureService != nil {
+		in, out := &in.HandshakeFailureService, &out.HandshakeFailureService
+		*out = new(ServiceTCP)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -109,7 +109,10 @@ func (p *Provider) loadHTTPRoutes(ctx context.Context, client Client, gatewayLis
 				Parents: parentStatuses,
 			},
 		}
-		if err := client.UpdateHTTPRouteStatus(ctx, ktypes.NamespacedName{Namespace: route.Namespace, Name: route.Name}, status); err != nil {
+		err := p.writeStatus(ctx, route.Namespace, func() error {
+			return client.UpdateHTTPRouteStatus(ctx, ktypes.NamespacedName{Namespace: route.Namespace, Name: route.Name}, status)
+		})
+		if err != nil {
 			logger.Error().
 				Err(err).
 				Msg("Unable to update HTTPRoute status")
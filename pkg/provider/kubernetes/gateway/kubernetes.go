@@ -56,6 +56,7 @@ type Provider struct {
 	ThrottleDuration    ptypes.Duration     `description:"Kubernetes refresh throttle duration" json:"throttleDuration,omitempty" toml:"throttleDuration,omitempty" yaml:"throttleDuration,omitempty" export:"true"`
 	ExperimentalChannel bool                `description:"Toggles Experimental Channel resources support (TCPRoute, TLSRoute...)." json:"experimentalChannel,omitempty" toml:"experimentalChannel,omitempty" yaml:"experimentalChannel,omitempty" export:"true"`
 	StatusAddress       *StatusAddress      `description:"Defines the Kubernetes Gateway status address." json:"statusAddress,omitempty" toml:"statusAddress,omitempty" yaml:"statusAddress,omitempty" export:"true"`
+	StatusNamespaces    []string            `description:"Kubernetes namespaces in which the resource statuses are written. All the watched namespaces by default." json:"statusNamespaces,omitempty" toml:"statusNamespaces,omitempty" yaml:"statusNamespaces,omitempty" export:"true"`
 
 	EntryPoints map[string]Entrypoint `json:"-" toml:"-" yaml:"-" label:"-" file:"-"`
 
@@ -65,6 +66,7 @@ type Provider struct {
 	groupKindBackendFuncs map[string]map[string]BuildBackendFunc
 
 	lastConfiguration safe.Safe
+	statusNamespaces  statusNamespaces
 
 	routerTransform k8s.RouterTransform
 }
@@ -366,7 +368,10 @@ func (p *Provider) loadConfigurationFromGateways(ctx context.Context, client Cli
 		}
 
 		gatewayStatus, errG := p.makeGatewayStatus(gateway, listeners, addresses)
-		if err = client.UpdateGatewayStatus(gateway, gatewayStatus); err != nil {
+		err = p.writeStatus(ctx, gateway.Namespace, func() error {
+			return client.UpdateGatewayStatus(gateway, gatewayStatus)
+		})
+		if err != nil {
 			logger.Error().
 				Err(err).
 				Msg("Unable to update Gateway status")
@@ -18,10 +18,12 @@ import (
 	"github.com/traefik/traefik/v3/pkg/tls"
 	"github.com/traefik/traefik/v3/pkg/types"
 	corev1 "k8s.io/api/core/v1"
+	kerror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
+	ktesting "k8s.io/client-go/testing"
 	"k8s.io/utils/ptr"
 	gatev1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatev1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
@@ -3188,6 +3190,87 @@ func TestLoadTCPRoutes(t *testing.T) {
 	}
 }
 
+func TestLoadTCPRoutes_statusNamespaces(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		statusNamespaces []string
+		forbidden        bool
+		expectedWrites   []int
+	}{
+		{
+			desc:           "statuses written in all the namespaces",
+			expectedWrites: []int{2, 2},
+		},
+		{
+			desc:             "namespace not listed",
+			statusNamespaces: []string{"other"},
+			expectedWrites:   []int{0, 0},
+		},
+		{
+			// The first forbidden write, of the TCPRoute status, skips the next status writes in the namespace.
+			desc:           "forbidden namespace",
+			forbidden:      true,
+			expectedWrites: []int{1, 0},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{
+				EntryPoints:         map[string]Entrypoint{"tcp": {Address: ":9000"}},
+				ExperimentalChannel: true,
+				StatusNamespaces:    test.statusNamespaces,
+			}
+
+			k8sObjects, gwObjects := readResources(t, []string{"services.yml", "tcproute/simple.yml"})
+
+			kubeClient := kubefake.NewSimpleClientset(k8sObjects...)
+			gwClient := newGatewaySimpleClientSet(t, gwObjects...)
+
+			// Counts the status writes of the Gateway and the TCPRoute, in the default namespace.
+			var writes int
+			gwClient.PrependReactor("update", "*", func(action ktesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "status" || action.GetNamespace() != "default" {
+					return false, nil, nil
+				}
+
+				writes++
+				if test.forbidden {
+					return true, nil, kerror.NewForbidden(action.GetResource().GroupResource(), "", errors.New("RBAC denied"))
+				}
+
+				return false, nil, nil
+			})
+
+			client := newClientImpl(kubeClient, gwClient)
+			client.experimentalChannel = true
+
+			eventCh, err := client.WatchAll(nil, make(chan struct{}))
+			require.NoError(t, err)
+
+			// just wait for the first event
+			<-eventCh
+
+			var loadedWrites []int
+			for range test.expectedWrites {
+				writes = 0
+
+				conf := p.loadConfigurationFromGateways(context.Background(), client)
+				require.NotNil(t, conf)
+
+				// The routes are loaded whether their statuses are written or not.
+				assert.Len(t, conf.TCP.Routers, 1)
+
+				loadedWrites = append(loadedWrites, writes)
+			}
+
+			assert.Equal(t, test.expectedWrites, loadedWrites)
+		})
+	}
+}
+
 func TestLoadTLSRoutes(t *testing.T) {
 	testCases := []struct {
 		desc         string
@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	kerror "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// statusNamespaces tracks the namespaces in which the provider is forbidden to write the resource statuses.
+type statusNamespaces struct {
+	mu        sync.Mutex
+	forbidden map[string]struct{}
+}
+
+// writeStatus writes the status of a resource of the given namespace with the given write function,
+// unless the statuses are not written in the namespace, either because it is not listed in the StatusNamespaces option,
+// or because a previous status write has been forbidden in it.
+// When forbidden, the status write is skipped with a single warning,
+// the next status writes in the namespace being skipped until the provider is restarted.
+func (p *Provider) writeStatus(ctx context.Context, namespace string, write func() error) error {
+	if len(p.StatusNamespaces) > 0 && !slices.Contains(p.StatusNamespaces, namespace) {
+		return nil
+	}
+
+	p.statusNamespaces.mu.Lock()
+	_, forbidden := p.statusNamespaces.forbidden[namespace]
+	p.statusNamespaces.mu.Unlock()
+
+	if forbidden {
+		return nil
+	}
+
+	err := write()
+	if !kerror.IsForbidden(err) {
+		return err
+	}
+
+	p.statusNamespaces.mu.Lock()
+	defer p.statusNamespaces.mu.Unlock()
+
+	if p.statusNamespaces.forbidden == nil {
+		p.statusNamespaces.forbidden = make(map[string]struct{})
+	}
+
+	if _, forbidden := p.statusNamespaces.forbidden[namespace]; !forbidden {
+		p.statusNamespaces.forbidden[namespace] = struct{}{}
+
+		log.Ctx(ctx).Warn().Err(err).
+			Str("namespace", namespace).
+			Msg("Not allowed to write the statuses in the namespace, the next status writes in it are skipped")
+	}
+
+	return nil
+}
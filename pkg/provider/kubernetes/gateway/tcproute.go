@@ -85,7 +85,10 @@ func (p *Provider) loadTCPRoutes(ctx context.Context, client Client, gatewayList
 				Parents: parentStatuses,
 			},
 		}
-		if err := client.UpdateTCPRouteStatus(ctx, ktypes.NamespacedName{Namespace: route.Namespace, Name: route.Name}, routeStatus); err != nil {
+		err := p.writeStatus(ctx, route.Namespace, func() error {
+			return client.UpdateTCPRouteStatus(ctx, ktypes.NamespacedName{Namespace: route.Namespace, Name: route.Name}, routeStatus)
+		})
+		if err != nil {
 			logger.Error().
 				Err(err).
 				Msg("Unable to update TCPRoute status")
@@ -87,7 +87,10 @@ func (p *Provider) loadTLSRoutes(ctx context.Context, client Client, gatewayList
 				Parents: parentStatuses,
 			},
 		}
-		if err := client.UpdateTLSRouteStatus(ctx, ktypes.NamespacedName{Namespace: route.Namespace, Name: route.Name}, routeStatus); err != nil {
+		err := p.writeStatus(ctx, route.Namespace, func() error {
+			return client.UpdateTLSRouteStatus(ctx, ktypes.NamespacedName{Namespace: route.Namespace, Name: route.Name}, routeStatus)
+		})
+		if err != nil {
 			logger.Error().
 				Err(err).
 				Msg("Unable to update TLSRoute status")
@@ -6,8 +6,11 @@ import (
 	"slices"
 	"strings"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/connauth"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/inflightconn"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipallowlist"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipwhitelist"
@@ -24,6 +27,14 @@ const (
 // Builder the middleware builder.
 type Builder struct {
 	configs map[string]*runtime.TCPMiddlewareInfo
+	// inFlightClients reports the clients holding connections of the InFlightConn middlewares, when set.
+	inFlightClients gokitmetrics.Gauge
+	// inFlightQueueWait observes the queue waits of the InFlightConn middlewares, when set.
+	inFlightQueueWait metrics.ScalableHistogram
+	// inFlightQueueTimeouts counts the queue timeouts of the InFlightConn middlewares, when set.
+	inFlightQueueTimeouts gokitmetrics.Counter
+	// ipDecisionLookups counts the lookups in the caches of the SourceRange decisions of the IPAllowList and IPWhiteList middlewares, when set.
+	ipDecisionLookups gokitmetrics.Counter
 }
 
 // NewBuilder creates a new Builder.
@@ -31,6 +42,23 @@ func NewBuilder(configs map[string]*runtime.TCPMiddlewareInfo) *Builder {
 	return &Builder{configs: configs}
 }
 
+// SetInFlightClientsGauge sets the gauge of the clients holding connections of the InFlightConn middlewares.
+func (b *Builder) SetInFlightClientsGauge(gauge gokitmetrics.Gauge) {
+	b.inFlightClients = gauge
+}
+
+// SetInFlightQueueMetrics sets the histogram of the queue waits, and the counter of the queue timeouts, of the InFlightConn middlewares.
+func (b *Builder) SetInFlightQueueMetrics(queueWait metrics.ScalableHistogram, queueTimeouts gokitmetrics.Counter) {
+	b.inFlightQueueWait = queueWait
+	b.inFlightQueueTimeouts = queueTimeouts
+}
+
+// SetIPDecisionCacheLookupsCounter sets the counter of the lookups in the caches of the SourceRange decisions
+// of the IPAllowList and IPWhiteList middlewares.
+func (b *Builder) SetIPDecisionCacheLookupsCounter(counter gokitmetrics.Counter) {
+	b.ipDecisionLookups = counter
+}
+
 // BuildChain creates a middleware chain.
 func (b *Builder) BuildChain(ctx context.Context, middlewares []string) *tcp.Chain {
 	chain := tcp.NewChain()
@@ -93,7 +121,11 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 	// InFlightConn
 	if config.InFlightConn != nil {
 		middleware = func(next tcp.Handler) (tcp.Handler, error) {
-			return inflightconn.New(ctx, next, *config.InFlightConn, middlewareName)
+			return inflightconn.New(ctx, next, *config.InFlightConn, middlewareName, inflightconn.Metrics{
+				Clients:       b.inFlightClients,
+				QueueWait:     b.inFlightQueueWait,
+				QueueTimeouts: b.inFlightQueueTimeouts,
+			})
 		}
 	}
 
@@ -102,14 +134,21 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		log.Warn().Msg("IPWhiteList is deprecated, please use IPAllowList instead.")
 
 		middleware = func(next tcp.Handler) (tcp.Handler, error) {
-			return ipwhitelist.New(ctx, next, *config.IPWhiteList, middlewareName)
+			return ipwhitelist.New(ctx, next, *config.IPWhiteList, middlewareName, b.sourceRangeDecisionLookups())
 		}
 	}
 
 	// IPAllowList
 	if config.IPAllowList != nil {
 		middleware = func(next tcp.Handler) (tcp.Handler, error) {
-			return ipallowlist.New(ctx, next, *config.IPAllowList, middlewareName)
+			return ipallowlist.New(ctx, next, *config.IPAllowList, middlewareName, b.sourceRangeDecisionLookups())
+		}
+	}
+
+	// ConnAuth
+	if config.ConnAuth != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return connauth.New(ctx, next, *config.ConnAuth, middlewareName)
 		}
 	}
 
@@ -119,3 +158,12 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 
 	return middleware, nil
 }
+
+// sourceRangeDecisionLookups returns the counter of the lookups in the caches of the SourceRange decisions, if set.
+func (b *Builder) sourceRangeDecisionLookups() gokitmetrics.Counter {
+	if b.ipDecisionLookups == nil {
+		return nil
+	}
+
+	return b.ipDecisionLookups.With("type", "SourceRange")
+}
@@ -0,0 +1,100 @@
+package tcp
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// concurrencySampleInterval is the interval at which the concurrent connections of the TCP routers are sampled.
+const concurrencySampleInterval = 10 * time.Second
+
+// ConcurrencySampler tracks the concurrent connections of each TCP router,
+// and periodically observes them on a histogram, e.g. to help sizing their maximum connections.
+// A nil ConcurrencySampler tracks nothing.
+type ConcurrencySampler struct {
+	interval  time.Duration
+	histogram gokitmetrics.Histogram
+
+	mu      sync.Mutex
+	routers map[string]*atomic.Int64
+	// timer is only armed while there are tracked routers.
+	timer *time.Timer
+}
+
+// NewConcurrencySampler creates a new ConcurrencySampler observing the samples on the given histogram, labeled by router.
+func NewConcurrencySampler(histogram gokitmetrics.Histogram) *ConcurrencySampler {
+	return &ConcurrencySampler{
+		interval:  concurrencySampleInterval,
+		histogram: histogram,
+		routers:   make(map[string]*atomic.Int64),
+	}
+}
+
+// track counts the connections served by the next handler as concurrent connections of the named router.
+func (s *ConcurrencySampler) track(routerName string, next tcp.Handler) tcp.Handler {
+	if s == nil {
+		return next
+	}
+
+	s.mu.Lock()
+	counter, ok := s.routers[routerName]
+	if !ok {
+		counter = &atomic.Int64{}
+		s.routers[routerName] = counter
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.interval, s.tick)
+	}
+	s.mu.Unlock()
+
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		counter.Add(1)
+		defer counter.Add(-1)
+
+		next.ServeTCP(conn)
+	})
+}
+
+// retain stops sampling the routers which are not part of the given ones anymore, e.g. after a configuration reload.
+func (s *ConcurrencySampler) retain(routerNames map[string]struct{}) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for routerName := range s.routers {
+		if _, ok := routerNames[routerName]; !ok {
+			delete(s.routers, routerName)
+		}
+	}
+}
+
+func (s *ConcurrencySampler) tick() {
+	s.sample()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.routers) == 0 {
+		s.timer = nil
+		return
+	}
+
+	s.timer.Reset(s.interval)
+}
+
+// sample observes the current concurrent connections of each tracked router.
+func (s *ConcurrencySampler) sample() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for routerName, counter := range s.routers {
+		s.histogram.With("router", routerName).Observe(float64(counter.Load()))
+	}
+}
@@ -0,0 +1,79 @@
+package tcp
+
+import (
+	"sync"
+	"testing"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// samplesHistogram records the observed samples by router.
+type samplesHistogram struct {
+	mu      *sync.Mutex
+	samples map[string][]float64
+	router  string
+}
+
+func (h samplesHistogram) With(labelValues ...string) gokitmetrics.Histogram {
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "router" {
+			h.router = labelValues[i+1]
+		}
+	}
+
+	return h
+}
+
+func (h samplesHistogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples[h.router] = append(h.samples[h.router], value)
+}
+
+func TestConcurrencySampler(t *testing.T) {
+	histogram := samplesHistogram{mu: &sync.Mutex{}, samples: make(map[string][]float64)}
+	sampler := NewConcurrencySampler(histogram)
+
+	release := make(chan struct{})
+	served := make(chan struct{})
+	blocking := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served <- struct{}{}
+		<-release
+	})
+
+	foo := sampler.track("foo@kubernetescrd", blocking)
+	bar := sampler.track("bar@kubernetescrd", blocking)
+
+	var wg sync.WaitGroup
+	for _, handler := range []tcp.Handler{foo, foo, foo, bar} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			handler.ServeTCP(nil)
+		}()
+		<-served
+	}
+
+	sampler.sample()
+
+	close(release)
+	wg.Wait()
+
+	sampler.sample()
+
+	// The routers which are not part of the configuration anymore are not sampled.
+	sampler.retain(map[string]struct{}{"foo@kubernetescrd": {}})
+	sampler.sample()
+
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	require.Len(t, histogram.samples, 2)
+	assert.Equal(t, []float64{3, 0, 0}, histogram.samples["foo@kubernetescrd"])
+	assert.Equal(t, []float64{1, 0}, histogram.samples["bar@kubernetescrd"])
+}
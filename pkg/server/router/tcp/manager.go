@@ -8,7 +8,9 @@ import (
 	"math"
 	"net/http"
 	"strings"
+	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/logs"
@@ -53,6 +55,73 @@ type Manager struct {
 	httpsHandlers      map[string]http.Handler
 	tlsManager         *traefiktls.Manager
 	conf               *runtime.Configuration
+
+	// tlsHandshakeLimiters are indexed by entry point name.
+	tlsHandshakeLimiters map[string]*tcp.TLSHandshakeLimiter
+	// sniLengthLimits are indexed by entry point name.
+	sniLengthLimits map[string]*SNILengthLimit
+	// sniCacheConfigs are indexed by entry point name.
+	sniCacheConfigs map[string]*SNICacheConfig
+	// maxClientHelloSizes are indexed by entry point name.
+	maxClientHelloSizes map[string]int
+	// signaturePeekTimeouts are indexed by entry point name.
+	signaturePeekTimeouts map[string]time.Duration
+	// routingSummaries are indexed by entry point name.
+	routingSummaries map[string]*RoutingSummary
+	// certificateTrackers are indexed by router name.
+	certificateTrackers map[string]*tcp.CertificateTracker
+
+	concurrencySampler *ConcurrencySampler
+
+	// ipDecisionLookups, if set, counts the lookups in the caches of the ClientIP matchers decisions.
+	ipDecisionLookups gokitmetrics.Counter
+}
+
+// SetTLSHandshakeLimiters sets the limiters of the TLS handshakes of the TCP routers, indexed by entry point name.
+func (m *Manager) SetTLSHandshakeLimiters(limiters map[string]*tcp.TLSHandshakeLimiter) {
+	m.tlsHandshakeLimiters = limiters
+}
+
+// SetSNILengthLimits sets the limits of the length of the SNI of the TLS connections, indexed by entry point name.
+func (m *Manager) SetSNILengthLimits(limits map[string]*SNILengthLimit) {
+	m.sniLengthLimits = limits
+}
+
+// SetSNICacheConfigs sets the configurations of the caches of the TCP TLS routing decisions by SNI, indexed by entry point name.
+func (m *Manager) SetSNICacheConfigs(configs map[string]*SNICacheConfig) {
+	m.sniCacheConfigs = configs
+}
+
+// SetMaxClientHelloSizes sets the maximum sizes of the TLS records read to get the ClientHello of the connections, indexed by entry point name.
+func (m *Manager) SetMaxClientHelloSizes(sizes map[string]int) {
+	m.maxClientHelloSizes = sizes
+}
+
+// SetSignaturePeekTimeouts sets the maximum durations spent peeking the first bytes of the non-TLS connections, indexed by entry point name.
+func (m *Manager) SetSignaturePeekTimeouts(timeouts map[string]time.Duration) {
+	m.signaturePeekTimeouts = timeouts
+}
+
+// SetRoutingSummaries sets the summaries of the connections routed by the TCP routers, indexed by entry point name.
+func (m *Manager) SetRoutingSummaries(summaries map[string]*RoutingSummary) {
+	m.routingSummaries = summaries
+}
+
+// SetConcurrencySampler sets the sampler of the concurrent connections of the TCP routers.
+func (m *Manager) SetConcurrencySampler(sampler *ConcurrencySampler) {
+	m.concurrencySampler = sampler
+}
+
+// SetIPDecisionCacheLookupsCounter sets the counter of the lookups in the caches of the ClientIP matchers decisions.
+func (m *Manager) SetIPDecisionCacheLookupsCounter(counter gokitmetrics.Counter) {
+	m.ipDecisionLookups = counter
+}
+
+// SetCertificateTrackers sets the trackers of the TLS connections certificates of the TCP routers, indexed by router name.
+// The trackers of the routers closing their connections on certificate change are added to it,
+// and the ones of the routers which no longer do are removed from it.
+func (m *Manager) SetCertificateTrackers(trackers map[string]*tcp.CertificateTracker) {
+	m.certificateTrackers = trackers
 }
 
 func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.TCPRouterInfo {
@@ -63,6 +132,18 @@ func (m *Manager) getTCPRouters(ctx context.Context, entryPoints []string) map[s
 	return make(map[string]map[string]*runtime.TCPRouterInfo)
 }
 
+// builtRouterNames returns the names of the given routers which are not disabled.
+func builtRouterNames(routers map[string]*runtime.TCPRouterInfo) map[string]struct{} {
+	routerNames := make(map[string]struct{})
+	for routerName, routerInfo := range routers {
+		if routerInfo.Status != runtime.StatusDisabled {
+			routerNames[routerName] = struct{}{}
+		}
+	}
+
+	return routerNames
+}
+
 func (m *Manager) getHTTPRouters(ctx context.Context, entryPoints []string, tls bool) map[string]map[string]*runtime.RouterInfo {
 	if m.conf != nil {
 		return m.conf.GetRoutersByEntryPoints(ctx, entryPoints, tls)
@@ -83,28 +164,71 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 		logger := log.Ctx(rootCtx).With().Str(logs.EntryPointName, entryPointName).Logger()
 		ctx := logger.WithContext(rootCtx)
 
-		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
+		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName], m.tlsHandshakeLimiters[entryPointName])
 		if err != nil {
 			logger.Error().Err(err).Send()
 			continue
 		}
+		handler.SetSNILengthLimit(m.sniLengthLimits[entryPointName])
+		handler.SetSNICache(m.sniCacheConfigs[entryPointName])
+		handler.SetMaxClientHelloSize(m.maxClientHelloSizes[entryPointName])
+		handler.SetSignaturePeekTimeout(m.signaturePeekTimeouts[entryPointName])
+		handler.SetRoutingSummary(m.routingSummaries[entryPointName])
+		handler.SetRouterNames(builtRouterNames(routers))
 		entryPointHandlers[entryPointName] = handler
 	}
+
+	builtRouters := make(map[string]struct{})
+	for _, routers := range entryPointsRouters {
+		for routerName := range builtRouterNames(routers) {
+			builtRouters[routerName] = struct{}{}
+		}
+	}
+	m.concurrencySampler.retain(builtRouters)
+	m.retainCertificateTrackers(entryPointsRouters)
+
 	return entryPointHandlers
 }
 
+// retainCertificateTrackers removes the trackers of the routers which no longer close their connections on certificate change,
+// e.g. after a configuration reload.
+// The connections still open keep being tracked by the removed trackers, until they are closed.
+func (m *Manager) retainCertificateTrackers(entryPointsRouters map[string]map[string]*runtime.TCPRouterInfo) {
+	if m.certificateTrackers == nil {
+		return
+	}
+
+	routerNames := make(map[string]struct{})
+	for _, routers := range entryPointsRouters {
+		for routerName, routerInfo := range routers {
+			if routerInfo.TLS != nil && routerInfo.TLS.CloseOnCertificateChange {
+				routerNames[routerName] = struct{}{}
+			}
+		}
+	}
+
+	for routerName := range m.certificateTrackers {
+		if _, ok := routerNames[routerName]; !ok {
+			delete(m.certificateTrackers, routerName)
+		}
+	}
+}
+
 type nameAndConfig struct {
 	routerName string // just so we have it as additional information when logging
 	TLSConfig  *tls.Config
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler) (*Router, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, configsHTTP map[string]*runtime.RouterInfo, handlerHTTP, handlerHTTPS http.Handler, tlsHandshakeLimiter *tcp.TLSHandshakeLimiter) (*Router, error) {
 	// Build a new Router.
 	router, err := NewRouter()
 	if err != nil {
 		return nil, err
 	}
 
+	// The routes are added below, with their ClientIP matchers caches.
+	router.SetIPDecisionCacheLookups(m.ipDecisionLookups)
+
 	router.SetHTTPHandler(handlerHTTP)
 
 	// Even though the error is seemingly ignored (aside from logging it),
@@ -255,13 +379,14 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		router.AddHTTPTLSConfig(hostSNI, defaultTLSConf)
 	}
 
-	m.addTCPHandlers(ctx, configs, router)
+	m.addTCPHandlers(ctx, configs, router, tlsHandshakeLimiter)
 
 	return router, nil
 }
 
 // addTCPHandlers creates the TCP handlers defined in configs, and adds them to router.
-func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, router *Router) {
+// The TLS handshakes of the routers terminating TLS are limited by the given limiter, if any.
+func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, router *Router, tlsHandshakeLimiter *tcp.TLSHandshakeLimiter) {
 	for routerName, routerConfig := range configs {
 		logger := log.Ctx(ctx).With().Str(logs.RouterName, routerName).Logger()
 		ctxRouter := logger.WithContext(provider.AddInContext(ctx, routerName))
@@ -310,7 +435,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 
 		var handler tcp.Handler
 		if routerConfig.TLS == nil || routerConfig.TLS.Passthrough {
-			handler, err = m.buildTCPHandler(ctxRouter, routerConfig)
+			handler, err = m.buildTCPHandler(ctxRouter, routerName, routerConfig)
 			if err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error().Err(err).Send()
@@ -321,7 +446,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 		if routerConfig.TLS == nil {
 			logger.Debug().Msgf("Adding route for %q", routerConfig.Rule)
 
-			if err := router.muxerTCP.AddRoute(routerConfig.Rule, routerConfig.RuleSyntax, routerConfig.Priority, handler); err != nil {
+			if err := router.muxerTCP.AddRoute(routerConfig.Rule, routerConfig.RuleSyntax, routerConfig.Priority, withRoutingSummary(router, routerName, handler)); err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error().Err(err).Send()
 			}
@@ -331,7 +456,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 		if routerConfig.TLS.Passthrough {
 			logger.Debug().Msgf("Adding Passthrough route for %q", routerConfig.Rule)
 
-			if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.RuleSyntax, routerConfig.Priority, handler); err != nil {
+			if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.RuleSyntax, routerConfig.Priority, withRoutingSummary(router, routerName, handler)); err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error().Err(err).Send()
 			}
@@ -373,6 +498,8 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 			continue
 		}
 
+		routerConfig.ResolvedTLS = resolvedTLSInfo(tlsOptionsName, tlsConf)
+
 		// Now that the Rule is not just about the Host, we could theoretically have a config like:
 		//	router1:
 		//		rule: HostSNI(foo.com) && ClientIP(IP1)
@@ -385,21 +512,46 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 		// This seems to be the case so far with the existing matchers (HostSNI, and ClientIP), so it's all good.
 		// Otherwise, we would have to do as for HTTPS, i.e. disallow different TLS configs for the same HostSNIs.
 
-		handler, err = m.buildTCPHandler(ctxRouter, routerConfig)
+		handler, err = m.buildTCPHandler(ctxRouter, routerName, routerConfig)
 		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error().Err(err).Send()
 			continue
 		}
 
+		var certificateTracker *tcp.CertificateTracker
+		if routerConfig.TLS.CloseOnCertificateChange && m.certificateTrackers != nil {
+			certificateTracker = m.certificateTrackers[routerName]
+			if certificateTracker == nil {
+				certificateTracker = tcp.NewCertificateTracker()
+				m.certificateTrackers[routerName] = certificateTracker
+			}
+
+			// Closes the connections served with a certificate which has since been rotated.
+			certificateTracker.Update(tlsConf)
+		}
+
+		var handshakeFailureHandler tcp.Handler
+		if routerConfig.TLS.HandshakeFailureService != "" {
+			handshakeFailureHandler, err = m.serviceManager.BuildTCP(ctxRouter, routerConfig.TLS.HandshakeFailureService)
+			if err != nil {
+				routerConfig.AddError(err, true)
+				logger.Error().Err(err).Send()
+				continue
+			}
+		}
+
 		handler = &tcp.TLSHandler{
-			Next:   handler,
-			Config: tlsConf,
+			Next:                    handler,
+			Config:                  tlsConf,
+			Limiter:                 tlsHandshakeLimiter,
+			CertificateTracker:      certificateTracker,
+			HandshakeFailureHandler: handshakeFailureHandler,
 		}
 
 		logger.Debug().Msgf("Adding TLS route for %q", routerConfig.Rule)
 
-		if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.RuleSyntax, routerConfig.Priority, handler); err != nil {
+		if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.RuleSyntax, routerConfig.Priority, withRoutingSummary(router, routerName, handler)); err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error().Err(err).Send()
 			continue
@@ -407,7 +559,7 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 	}
 }
 
-func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
+func (m *Manager) buildTCPHandler(ctx context.Context, routerName string, router *runtime.TCPRouterInfo) (tcp.Handler, error) {
 	var qualifiedNames []string
 	for _, name := range router.Middlewares {
 		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
@@ -425,5 +577,57 @@ func (m *Manager) buildTCPHandler(ctx context.Context, router *runtime.TCPRouter
 
 	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
 
-	return tcp.NewChain().Extend(*mHandler).Then(sHandler)
+	handler, err := tcp.NewChain().Extend(*mHandler).Then(sHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoder := m.serviceManager.CloseReasonEncoder(ctx, router.Service); encoder != nil {
+		handler = withCloseReasonEncoder(encoder, handler)
+	}
+
+	serviceName := provider.GetQualifiedName(ctx, router.Service)
+
+	return m.concurrencySampler.track(routerName, withRoutingAttributes(routerName, serviceName, handler)), nil
+}
+
+// withCloseReasonEncoder sets the close reason encoder of the service of the router on the connections it routes,
+// for the frames to be written to the connections as seen by the router, i.e. after the TLS termination if any.
+func withCloseReasonEncoder(encoder tcp.CloseReasonEncoder, next tcp.Handler) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		if attributes := tcp.GetConnAttributes(conn); attributes != nil {
+			attributes.SetCloseReasonEncoder(encoder, conn)
+		}
+
+		next.ServeTCP(conn)
+	})
+}
+
+// withRoutingAttributes sets the names of the router and of its service as attributes of the connections it routes,
+// e.g. for the services to label their metrics by router, and the middlewares by service.
+func withRoutingAttributes(routerName, serviceName string, next tcp.Handler) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		if attributes := tcp.GetConnAttributes(conn); attributes != nil {
+			attributes.Set(tcp.RouterAttribute, routerName)
+			attributes.Set(tcp.ServiceAttribute, serviceName)
+		}
+
+		next.ServeTCP(conn)
+	})
+}
+
+// resolvedTLSInfo describes the given TLS configuration, built from the TLS options of the given qualified name.
+func resolvedTLSInfo(optionsName string, conf *tls.Config) *runtime.TCPRouterTLSInfo {
+	info := &runtime.TCPRouterTLSInfo{
+		Options:        optionsName,
+		MinVersion:     traefiktls.GetVersionName(conf.MinVersion),
+		MaxVersion:     traefiktls.GetVersionName(conf.MaxVersion),
+		ClientAuthType: conf.ClientAuth.String(),
+	}
+
+	for _, cipherSuite := range conf.CipherSuites {
+		info.CipherSuites = append(info.CipherSuites, traefiktls.CipherSuitesReversed[cipherSuite])
+	}
+
+	return info
 }
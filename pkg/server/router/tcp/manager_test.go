@@ -690,3 +690,139 @@ func TestDomainFronting(t *testing.T) {
 		})
 	}
 }
+
+func TestResolvedTLS(t *testing.T) {
+	service := &runtime.TCPServiceInfo{
+		TCPService: &dynamic.TCPService{
+			LoadBalancer: &dynamic.TCPServersLoadBalancer{
+				Servers: []dynamic.TCPServer{{Address: "127.0.0.1:8085"}},
+			},
+		},
+	}
+
+	conf := &runtime.Configuration{
+		TCPServices: map[string]*runtime.TCPServiceInfo{"foo-service@myprovider": service},
+		TCPRouters: map[string]*runtime.TCPRouterInfo{
+			"terminated@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "HostSNI(`foo.bar`)",
+					TLS:         &dynamic.RouterTCPTLSConfig{Options: "foo"},
+				},
+			},
+			"default@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "HostSNI(`bar.foo`)",
+					TLS:         &dynamic.RouterTCPTLSConfig{},
+				},
+			},
+			"passthrough@myprovider": {
+				TCPRouter: &dynamic.TCPRouter{
+					EntryPoints: []string{"web"},
+					Service:     "foo-service",
+					Rule:        "HostSNI(`baz.foo`)",
+					TLS:         &dynamic.RouterTCPTLSConfig{Passthrough: true},
+				},
+			},
+		},
+	}
+
+	dialerManager := tcp2.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
+	serviceManager := tcp.NewManager(conf, dialerManager)
+
+	tlsManager := traefiktls.NewManager()
+	tlsManager.UpdateConfigs(context.Background(), map[string]traefiktls.Store{}, map[string]traefiktls.Options{
+		"default": {},
+		"foo@myprovider": {
+			MinVersion:   "VersionTLS12",
+			MaxVersion:   "VersionTLS13",
+			CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+			ClientAuth:   traefiktls.ClientAuth{ClientAuthType: "RequireAnyClientCert"},
+		},
+	}, []*traefiktls.CertAndStores{})
+
+	middlewaresBuilder := tcpmiddleware.NewBuilder(conf.TCPMiddlewares)
+
+	routerManager := NewManager(conf, serviceManager, middlewaresBuilder, nil, nil, tlsManager)
+	_ = routerManager.BuildHandlers(context.Background(), []string{"web"})
+
+	// The TLS options of the terminated routers are resolved by their qualified name.
+	assert.Equal(t, &runtime.TCPRouterTLSInfo{
+		Options:        "foo@myprovider",
+		MinVersion:     "VersionTLS12",
+		MaxVersion:     "VersionTLS13",
+		CipherSuites:   []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		ClientAuthType: "RequireAnyClientCert",
+	}, conf.TCPRouters["terminated@myprovider"].ResolvedTLS)
+
+	assert.Equal(t, &runtime.TCPRouterTLSInfo{
+		Options:        traefiktls.DefaultTLSConfigName,
+		ClientAuthType: "NoClientCert",
+	}, conf.TCPRouters["default@myprovider"].ResolvedTLS)
+
+	assert.Nil(t, conf.TCPRouters["passthrough@myprovider"].ResolvedTLS)
+}
+
+func TestCertificateTrackers(t *testing.T) {
+	dialerManager := tcp2.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
+
+	tlsManager := traefiktls.NewManager()
+	tlsManager.UpdateConfigs(context.Background(), map[string]traefiktls.Store{}, map[string]traefiktls.Options{"default": {}}, []*traefiktls.CertAndStores{})
+
+	trackers := make(map[string]*tcp2.CertificateTracker)
+
+	buildHandlers := func(routers map[string]*runtime.TCPRouterInfo) {
+		conf := &runtime.Configuration{
+			TCPServices: map[string]*runtime.TCPServiceInfo{
+				"foo-service@myprovider": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "127.0.0.1:8085"}},
+						},
+					},
+				},
+			},
+			TCPRouters: routers,
+		}
+
+		routerManager := NewManager(conf, tcp.NewManager(conf, dialerManager), tcpmiddleware.NewBuilder(conf.TCPMiddlewares), nil, nil, tlsManager)
+		routerManager.SetCertificateTrackers(trackers)
+		_ = routerManager.BuildHandlers(context.Background(), []string{"web"})
+	}
+
+	tlsRouter := func(rule string, closeOnCertificateChange bool) *runtime.TCPRouterInfo {
+		return &runtime.TCPRouterInfo{
+			TCPRouter: &dynamic.TCPRouter{
+				EntryPoints: []string{"web"},
+				Service:     "foo-service",
+				Rule:        rule,
+				TLS:         &dynamic.RouterTCPTLSConfig{CloseOnCertificateChange: closeOnCertificateChange},
+			},
+		}
+	}
+
+	buildHandlers(map[string]*runtime.TCPRouterInfo{
+		"foo@myprovider": tlsRouter("HostSNI(`foo.bar`)", true),
+		"bar@myprovider": tlsRouter("HostSNI(`bar.foo`)", true),
+	})
+	require.Contains(t, trackers, "foo@myprovider")
+	require.Contains(t, trackers, "bar@myprovider")
+
+	fooTracker := trackers["foo@myprovider"]
+
+	// The trackers are kept across the reloads, as long as their routers close their connections on certificate change.
+	buildHandlers(map[string]*runtime.TCPRouterInfo{
+		"foo@myprovider": tlsRouter("HostSNI(`foo.bar`)", true),
+		"bar@myprovider": tlsRouter("HostSNI(`bar.foo`)", false),
+	})
+	assert.Same(t, fooTracker, trackers["foo@myprovider"])
+	assert.NotContains(t, trackers, "bar@myprovider")
+
+	buildHandlers(map[string]*runtime.TCPRouterInfo{})
+	assert.Empty(t, trackers)
+}
@@ -59,7 +59,7 @@ func (r *Router) servePostgres(conn tcp.WriteCloser) {
 		return
 	}
 
-	hello, err := clientHelloInfo(br)
+	hello, err := clientHelloInfo(br, r.maxClientHelloSize)
 	if err != nil {
 		conn.Close()
 		return
@@ -164,3 +164,8 @@ func (c *postgresConn) Write(p []byte) (n int, err error) {
 
 	return 1, nil
 }
+
+// NetConn returns the underlying connection.
+func (c *postgresConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
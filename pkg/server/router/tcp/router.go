@@ -12,13 +12,22 @@ import (
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 const defaultBufSize = 4096
 
+// defaultMaxClientHelloSize is the default maximum number of bytes of the TLS records read to get the ClientHello of a connection.
+const defaultMaxClientHelloSize = 64 * 1024
+
+// defaultSignaturePeekTimeout is the default maximum duration spent peeking the first bytes of a non-TLS connection,
+// when routes may match on them.
+const defaultSignaturePeekTimeout = time.Second
+
 // Router is a TCP router.
 type Router struct {
 	// Contains TCP routes.
@@ -44,6 +53,33 @@ type Router struct {
 	// hostHTTPTLSConfig contains TLS configs keyed by SNI.
 	// A nil config is the hint to set up a brokenTLSRouter.
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+
+	// sniLengthLimit, if set, rejects the TLS connections whose SNI is too long.
+	sniLengthLimit *SNILengthLimit
+
+	// routingSummary, if set, records the connections routed by the TCP routers.
+	routingSummary *RoutingSummary
+
+	// sniCache, if set, caches the TCP TLS routing decisions by SNI.
+	sniCache *sniCache
+
+	// routerNames are the names of the TCP routers the connections are routed by.
+	routerNames map[string]struct{}
+
+	// maxClientHelloSize is the maximum number of bytes of the TLS records read to get the ClientHello of a connection.
+	maxClientHelloSize int
+
+	// signaturePeekTimeout is the maximum duration spent peeking the first bytes of a non-TLS connection,
+	// when routes may match on them.
+	signaturePeekTimeout time.Duration
+}
+
+// SNILengthLimit is the limit of the length of the SNI of the TLS connections of an entry point.
+type SNILengthLimit struct {
+	// MaxLength is the maximum length of the SNI.
+	MaxLength int
+	// Rejects counts the connections closed for an SNI exceeding MaxLength.
+	Rejects gokitmetrics.Counter
 }
 
 // NewRouter returns a new TCP router.
@@ -64,9 +100,11 @@ func NewRouter() (*Router, error) {
 	}
 
 	return &Router{
-		muxerTCP:    *muxTCP,
-		muxerTCPTLS: *muxTCPTLS,
-		muxerHTTPS:  *muxHTTPS,
+		muxerTCP:             *muxTCP,
+		muxerTCPTLS:          *muxTCPTLS,
+		muxerHTTPS:           *muxHTTPS,
+		maxClientHelloSize:   defaultMaxClientHelloSize,
+		signaturePeekTimeout: defaultSignaturePeekTimeout,
 	}, nil
 }
 
@@ -88,6 +126,8 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	// In the case of a non-TLS TCP client (that does not "send" first),
 	// we would block forever on clientHelloInfo,
 	// which is why we want to detect and handle that case first and foremost.
+	// The connections that may be routed on their first bytes, with the Signature matcher,
+	// are handled below, after peeking them.
 	if r.muxerTCP.HasRoutes() && !r.muxerTCPTLS.HasRoutes() && !r.muxerHTTPS.HasRoutes() {
 		connData, err := tcpmuxer.NewConnData("", conn, nil)
 		if err != nil {
@@ -96,7 +136,11 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 			return
 		}
 
-		handler, _ := r.muxerTCP.Match(connData)
+		var handler tcp.Handler
+		if !r.muxerTCP.NeedsSignature(connData) {
+			handler, _ = r.muxerTCP.Match(connData)
+		}
+
 		// If there is a handler matching the connection metadata,
 		// we let it handle the connection.
 		if handler != nil {
@@ -132,12 +176,27 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
-	hello, err := clientHelloInfo(br)
+	hello, err := clientHelloInfo(br, r.maxClientHelloSize)
 	if err != nil {
 		conn.Close()
 		return
 	}
 
+	if hello.isTLS && r.sniLengthLimit != nil && len(hello.serverName) > r.sniLengthLimit.MaxLength {
+		log.Debug().
+			Str("remoteAddr", conn.RemoteAddr().String()).
+			Int("sniLength", len(hello.serverName)).
+			Msgf("Closing TLS connection whose SNI exceeds the maximum length of %d", r.sniLengthLimit.MaxLength)
+
+		r.sniLengthLimit.Rejects.Add(1)
+		conn.Close()
+		return
+	}
+
+	if attributes := tcp.GetConnAttributes(conn); attributes != nil && hello.serverName != "" {
+		attributes.Set(tcp.SNIAttribute, hello.serverName)
+	}
+
 	// Remove read/write deadline and delegate this to underlying TCP server (for now only handled by HTTP Server)
 	if err := conn.SetDeadline(time.Time{}); err != nil {
 		log.Error().Err(err).Msg("Error while setting deadline")
@@ -151,6 +210,10 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	}
 
 	if !hello.isTLS {
+		if r.muxerTCP.NeedsSignature(connData) {
+			hello.peeked = r.peekSignature(conn, br, &connData)
+		}
+
 		handler, _ := r.muxerTCP.Match(connData)
 		switch {
 		case handler != nil:
@@ -158,6 +221,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		case r.httpForwarder != nil:
 			r.httpForwarder.ServeTCP(r.GetConn(conn, hello.peeked))
 		default:
+			r.routingSummary.RecordUnmatched()
 			conn.Close()
 		}
 		return
@@ -184,7 +248,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	}
 
 	// Contains also TCP TLS passthrough routes.
-	handlerTCPTLS, catchAllTCPTLS := r.muxerTCPTLS.Match(connData)
+	handlerTCPTLS, catchAllTCPTLS := r.matchTCPTLS(connData, hello.serverName)
 	if handlerTCPTLS != nil && !catchAllTCPTLS {
 		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
 		return
@@ -210,9 +274,29 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
+	r.routingSummary.RecordUnmatched()
 	conn.Close()
 }
 
+// matchTCPTLS returns the handler of the TCP TLS route matching the connection,
+// from the SNI cache when the routes only match on the SNI.
+func (r *Router) matchTCPTLS(connData tcpmuxer.ConnData, serverName string) (tcp.Handler, bool) {
+	if r.sniCache == nil || !r.muxerTCPTLS.MatchesSNIOnly() {
+		return r.muxerTCPTLS.Match(connData)
+	}
+
+	serverName = types.CanonicalDomain(serverName)
+
+	if handler, catchAll, ok := r.sniCache.get(serverName); ok {
+		return handler, catchAll
+	}
+
+	handler, catchAll := r.muxerTCPTLS.Match(connData)
+	r.sniCache.add(serverName, handler, catchAll)
+
+	return handler, catchAll
+}
+
 // acmeTLSALPNHandler returns a special handler to solve ACME-TLS/1 challenges.
 func (r *Router) acmeTLSALPNHandler() tcp.Handler {
 	if r.httpsTLSConfig == nil {
@@ -238,6 +322,66 @@ func (r *Router) AddHTTPTLSConfig(sniHost string, config *tls.Config) {
 	r.hostHTTPTLSConfig[sniHost] = config
 }
 
+// SetSNILengthLimit sets the limit of the length of the SNI of the TLS connections.
+func (r *Router) SetSNILengthLimit(limit *SNILengthLimit) {
+	r.sniLengthLimit = limit
+}
+
+// SetMaxClientHelloSize sets the maximum number of bytes of the TLS records read to get the ClientHello of a connection.
+// A zero or negative size sets the default one.
+func (r *Router) SetMaxClientHelloSize(size int) {
+	if size <= 0 {
+		size = defaultMaxClientHelloSize
+	}
+
+	r.maxClientHelloSize = size
+}
+
+// SetSignaturePeekTimeout sets the maximum duration spent peeking the first bytes of a non-TLS connection,
+// when routes may match on them.
+// A zero or negative timeout sets the default one.
+func (r *Router) SetSignaturePeekTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = defaultSignaturePeekTimeout
+	}
+
+	r.signaturePeekTimeout = timeout
+}
+
+// SetRoutingSummary sets the summary of the connections routed by the TCP routers.
+func (r *Router) SetRoutingSummary(summary *RoutingSummary) {
+	r.routingSummary = summary
+}
+
+// SetSNICache sets up the cache of the TCP TLS routing decisions by SNI.
+func (r *Router) SetSNICache(config *SNICacheConfig) {
+	if config == nil || config.MaxSize <= 0 {
+		r.sniCache = nil
+		return
+	}
+
+	r.sniCache = newSNICache(config)
+}
+
+// SetIPDecisionCacheLookups sets the counter of the lookups in the caches of the ClientIP matchers decisions,
+// of the routes added afterward.
+func (r *Router) SetIPDecisionCacheLookups(lookups gokitmetrics.Counter) {
+	r.muxerTCP.SetIPDecisionCacheLookups(lookups)
+	r.muxerTCPTLS.SetIPDecisionCacheLookups(lookups)
+	r.muxerHTTPS.SetIPDecisionCacheLookups(lookups)
+}
+
+// SetRouterNames sets the names of the TCP routers the connections are routed by.
+func (r *Router) SetRouterNames(routerNames map[string]struct{}) {
+	r.routerNames = routerNames
+}
+
+// HasRouter reports whether the connections are routed by the named TCP router.
+func (r *Router) HasRouter(routerName string) bool {
+	_, ok := r.routerNames[routerName]
+	return ok
+}
+
 // GetConn creates a connection proxy with a peeked string.
 func (r *Router) GetConn(conn tcp.WriteCloser, peeked string) tcp.WriteCloser {
 	// TODO should it really be on Router ?
@@ -343,6 +487,16 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
+// Attributes returns the attributes of the underlying connection, if any.
+func (c *Conn) Attributes() *tcp.ConnAttributes {
+	return tcp.GetConnAttributes(c.WriteCloser)
+}
+
+// NetConn returns the underlying connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
 type clientHello struct {
 	serverName string   // SNI server name
 	protos     []string // ALPN protocols list
@@ -352,8 +506,10 @@ type clientHello struct {
 
 // clientHelloInfo returns various data from the clientHello handshake,
 // without consuming any bytes from br.
+// As the ClientHello may be fragmented across several TLS records,
+// the records are read until the ClientHello is complete, up to maxSize bytes.
 // It returns an error if it can't peek the first byte from the connection.
-func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
+func clientHelloInfo(br *bufio.Reader, maxSize int) (*clientHello, error) {
 	hdr, err := br.Peek(1)
 	if err != nil {
 		var opErr *net.OpError
@@ -367,7 +523,6 @@ func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 	// where the MSB is set and the first record is always < 256 bytes long.
 	// Therefore, typ == 0x80 strongly suggests an SSLv2 client.
 	const recordTypeSSLv2 = 0x80
-	const recordTypeHandshake = 0x16
 	if hdr[0] != recordTypeHandshake {
 		if hdr[0] == recordTypeSSLv2 {
 			// we consider SSLv2 as TLS, and it will be refused by real TLS handshake.
@@ -381,22 +536,14 @@ func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 		}, nil // Not TLS.
 	}
 
-	const recordHeaderLen = 5
-	hdr, err = br.Peek(recordHeaderLen)
-	if err != nil {
+	if _, err = br.Peek(recordHeaderLen); err != nil {
 		log.Error().Err(err).Msg("Error while Peeking hello")
 		return &clientHello{
 			peeked: getPeeked(br),
 		}, nil
 	}
 
-	recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
-
-	if recordHeaderLen+recLen > defaultBufSize {
-		br = bufio.NewReaderSize(br, recordHeaderLen+recLen)
-	}
-
-	helloBytes, err := br.Peek(recordHeaderLen + recLen)
+	br, helloBytes, err := peekClientHelloRecords(br, maxSize)
 	if err != nil {
 		log.Error().Err(err).Msg("Error while Hello")
 		return &clientHello{
@@ -424,6 +571,132 @@ func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 	}, nil
 }
 
+const (
+	recordTypeHandshake = 0x16
+	recordHeaderLen     = 5
+	// handshakeHeaderLen is the length of the header of the handshake messages: their type and 24 bits length.
+	handshakeHeaderLen = 4
+)
+
+// peekClientHelloRecords peeks the TLS handshake records starting the connection, until they hold a complete ClientHello.
+// The records are peeked up to maxSize bytes, and only the ones peeked so far are returned
+// when the ClientHello is longer, or when the connection is closed before it is complete.
+// It returns the reader the records are buffered in, which grows br when they do not fit in it.
+func peekClientHelloRecords(br *bufio.Reader, maxSize int) (*bufio.Reader, []byte, error) {
+	var size, fragmentsLen int
+	var handshakeHdr []byte
+
+	for {
+		if size > 0 && size+recordHeaderLen > maxSize {
+			log.Debug().Msgf("TLS ClientHello exceeding the maximum size of %d bytes, routing on its first %d bytes", maxSize, size)
+			return br, peekRecords(br, size), nil
+		}
+
+		// The header of the next record may not fit in the reader, e.g. when the previous record fills it.
+		br = growReader(br, size+recordHeaderLen, maxSize)
+
+		hdr, err := br.Peek(size + recordHeaderLen)
+		if err != nil {
+			if size > 0 {
+				// The connection is routed on the records peeked so far.
+				return br, peekRecords(br, size), nil
+			}
+			return br, nil, err
+		}
+
+		hdr = hdr[size:]
+		if hdr[0] != recordTypeHandshake {
+			return br, peekRecords(br, size), nil
+		}
+
+		recLen := int(hdr[3])<<8 | int(hdr[4]) // ignoring version in hdr[1:3]
+		recordSize := recordHeaderLen + recLen
+		if size > 0 && size+recordSize > maxSize {
+			log.Debug().Msgf("TLS ClientHello exceeding the maximum size of %d bytes, routing on its first %d bytes", maxSize, size)
+			return br, peekRecords(br, size), nil
+		}
+
+		br = growReader(br, size+recordSize, maxSize)
+
+		records, err := br.Peek(size + recordSize)
+		if err != nil {
+			if size > 0 {
+				return br, peekRecords(br, size), nil
+			}
+			return br, nil, err
+		}
+
+		fragment := records[size+recordHeaderLen:]
+		if len(handshakeHdr) < handshakeHeaderLen {
+			handshakeHdr = append(handshakeHdr, fragment[:min(len(fragment), handshakeHeaderLen-len(handshakeHdr))]...)
+		}
+
+		size += recordSize
+		fragmentsLen += recLen
+
+		if len(handshakeHdr) == handshakeHeaderLen {
+			helloLen := handshakeHeaderLen + (int(handshakeHdr[1])<<16 | int(handshakeHdr[2])<<8 | int(handshakeHdr[3]))
+			if fragmentsLen >= helloLen {
+				return br, records, nil
+			}
+		}
+	}
+}
+
+// growReader returns br, or a reader buffering it, when size bytes do not fit in br.
+// The reader grows at least twice as large, not to be replaced for each of the small records of a fragmented ClientHello.
+func growReader(br *bufio.Reader, size, maxSize int) *bufio.Reader {
+	if size <= br.Size() {
+		return br
+	}
+
+	return bufio.NewReaderSize(br, max(size, min(2*br.Size(), maxSize)))
+}
+
+// peekRecords returns the first size bytes, already buffered by br.
+func peekRecords(br *bufio.Reader, size int) []byte {
+	records, _ := br.Peek(size)
+	return records
+}
+
+// peekSignature peeks the first bytes of a non-TLS connection, until a non catch-all TCP route matches them,
+// tcpmuxer.MaxSignatureLength bytes are peeked, or the signature peek timeout elapses.
+// It returns all the peeked bytes, to be restored to the connection before proxying.
+func (r *Router) peekSignature(conn tcp.WriteCloser, br *bufio.Reader, connData *tcpmuxer.ConnData) string {
+	if err := conn.SetReadDeadline(time.Now().Add(r.signaturePeekTimeout)); err != nil {
+		log.Error().Err(err).Msg("Error while setting read deadline")
+	}
+
+	defer func() {
+		if err := conn.SetReadDeadline(time.Time{}); err != nil {
+			log.Error().Err(err).Msg("Error while setting read deadline")
+		}
+	}()
+
+	peeked := getPeeked(br)
+	for {
+		connData.SetPeeked(peeked)
+
+		if handler, catchAll := r.muxerTCP.Match(*connData); handler != nil && !catchAll {
+			return peeked
+		}
+
+		if len(peeked) >= tcpmuxer.MaxSignatureLength {
+			return peeked
+		}
+
+		// Either the client does not send more bytes before the timeout, or closes the connection,
+		// in which case the connection is routed on the bytes peeked so far.
+		if _, err := br.Peek(len(peeked) + 1); err != nil {
+			peeked = getPeeked(br)
+			connData.SetPeeked(peeked)
+			return peeked
+		}
+
+		peeked = getPeeked(br)
+	}
+}
+
 func getPeeked(br *bufio.Reader) string {
 	peeked, err := br.Peek(br.Buffered())
 	if err != nil {
@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
@@ -12,9 +13,11 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
@@ -593,7 +596,7 @@ func Test_Routing(t *testing.T) {
 				router(dynConf)
 			}
 
-			router, err := manager.buildEntryPointHandler(context.Background(), dynConf.TCPRouters, dynConf.Routers, nil, nil)
+			router, err := manager.buildEntryPointHandler(context.Background(), dynConf.TCPRouters, dynConf.Routers, nil, nil, nil)
 			require.NoError(t, err)
 
 			epListener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -1038,6 +1041,246 @@ func TestPostgres(t *testing.T) {
 	require.Equal(t, []byte("OK"), b)
 }
 
+func TestRouter_SNILengthLimit(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		serverName string
+		expected   bool
+	}{
+		{
+			desc:       "SNI within the limit",
+			serverName: "foo.com",
+			expected:   true,
+		},
+		{
+			desc:       "SNI exceeding the limit",
+			serverName: strings.Repeat("a", 30) + ".com",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			handled := make(chan struct{}, 1)
+			err = router.muxerTCPTLS.AddRoute("HostSNI(`*`)", "", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+				handled <- struct{}{}
+				_ = conn.Close()
+			}))
+			require.NoError(t, err)
+
+			rejects := generic.NewCounter("rejects")
+			router.SetSNILengthLimit(&SNILengthLimit{MaxLength: 20, Rejects: rejects})
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				router.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			// The handshake never completes, either because the connection is rejected, or because the handler closes it.
+			_ = tls.Client(conn, &tls.Config{ServerName: test.serverName, InsecureSkipVerify: true}).Handshake()
+
+			if test.expected {
+				<-handled
+				assert.InDelta(t, 0, rejects.Value(), 0)
+				return
+			}
+
+			assert.Empty(t, handled)
+			assert.InDelta(t, 1, rejects.Value(), 0)
+		})
+	}
+}
+
+func TestRouter_Signature(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	routed := make(chan string, 1)
+	err = router.muxerTCP.AddRoute("Signature(`^SSH-`)", "", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		defer conn.Close()
+
+		// The peeked bytes are restored to the connection.
+		banner := make([]byte, len("SSH-2.0-OpenSSH_9.6\r\n"))
+		_, err := io.ReadFull(conn, banner)
+		if err != nil {
+			routed <- err.Error()
+			return
+		}
+
+		routed <- "ssh: " + string(banner)
+	}))
+	require.NoError(t, err)
+
+	err = router.muxerTCPTLS.AddRoute("HostSNI(`foo.com`)", "", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		defer conn.Close()
+
+		recordType := make([]byte, 1)
+		_, err := io.ReadFull(conn, recordType)
+		if err != nil {
+			routed <- err.Error()
+			return
+		}
+
+		routed <- fmt.Sprintf("tls: %#x", recordType)
+	}))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go router.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = fmt.Fprint(conn, "SSH-2.0-OpenSSH_9.6\r\n")
+	require.NoError(t, err)
+	assert.Equal(t, "ssh: SSH-2.0-OpenSSH_9.6\r\n", <-routed)
+
+	// The first bytes are peeked until the signature matches.
+	conn, err = net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = fmt.Fprint(conn, "SS")
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = fmt.Fprint(conn, "H-2.0-OpenSSH_9.6\r\n")
+	require.NoError(t, err)
+	assert.Equal(t, "ssh: SSH-2.0-OpenSSH_9.6\r\n", <-routed)
+
+	conn, err = net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// The handshake never completes, as the handler closes the connection.
+	_ = tls.Client(conn, &tls.Config{ServerName: "foo.com", InsecureSkipVerify: true}).Handshake()
+	assert.Equal(t, "tls: 0x16", <-routed)
+}
+
+func TestRouter_SignatureNotNeeded(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	router.SetSignaturePeekTimeout(50 * time.Millisecond)
+
+	err = router.muxerTCP.AddRoute("Signature(`^SSH-`) && ClientIP(`192.168.0.0/16`)", "", 2, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		conn.Close()
+	}))
+	require.NoError(t, err)
+
+	// The server sends first, the client never does.
+	err = router.muxerTCP.AddRoute("ClientIP(`127.0.0.1`)", "", 1, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		defer conn.Close()
+
+		_, _ = fmt.Fprint(conn, "220 smtp.example.com ESMTP\r\n")
+	}))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go router.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// No Signature route may match the client, so its connection is routed without peeking its first bytes.
+	err = conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "220 smtp.example.com ESMTP\r\n", banner)
+}
+
+func TestRouter_SignaturePeekTimeout(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	router.SetSignaturePeekTimeout(50 * time.Millisecond)
+
+	err = router.muxerTCP.AddRoute("Signature(`^SSH-`)", "", 2, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		conn.Close()
+	}))
+	require.NoError(t, err)
+
+	routed := make(chan time.Time, 1)
+	err = router.muxerTCP.AddRoute("HostSNI(`*`)", "", 1, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		defer conn.Close()
+
+		routed <- time.Now()
+	}))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go router.ServeTCP(conn.(*net.TCPConn))
+		}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	sent := time.Now()
+	_, err = fmt.Fprint(conn, "SS")
+	require.NoError(t, err)
+
+	// The connection is routed on the bytes peeked before the timeout, well before the default one.
+	select {
+	case at := <-routed:
+		assert.Less(t, at.Sub(sent), defaultSignaturePeekTimeout)
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection not routed")
+	}
+}
+
 func NewMockConn() *MockConn {
 	return &MockConn{
 		dataRead:  make(chan []byte),
@@ -1092,3 +1335,128 @@ func (m *MockConn) CloseWrite() error {
 	close(m.dataWrite)
 	return nil
 }
+
+func Test_clientHelloInfo_fragmented(t *testing.T) {
+	record := clientHelloRecord(t, "foo.example.com", []string{"h2", "http/1.1"})
+
+	testCases := []struct {
+		desc           string
+		fragmentLen    int
+		maxSize        int
+		readerSize     int
+		truncate       int
+		expectedSNI    string
+		expectedProtos []string
+	}{
+		{
+			desc:           "ClientHello in a single record",
+			maxSize:        defaultMaxClientHelloSize,
+			expectedSNI:    "foo.example.com",
+			expectedProtos: []string{"h2", "http/1.1"},
+		},
+		{
+			desc:           "ClientHello fragmented across records",
+			fragmentLen:    50,
+			maxSize:        defaultMaxClientHelloSize,
+			expectedSNI:    "foo.example.com",
+			expectedProtos: []string{"h2", "http/1.1"},
+		},
+		{
+			desc:           "ClientHello fragmented across records with a 1 byte handshake header fragment",
+			fragmentLen:    1,
+			maxSize:        defaultMaxClientHelloSize,
+			expectedSNI:    "foo.example.com",
+			expectedProtos: []string{"h2", "http/1.1"},
+		},
+		{
+			desc:           "ClientHello fragmented across records, the first one filling the reader",
+			fragmentLen:    50,
+			maxSize:        defaultMaxClientHelloSize,
+			readerSize:     recordHeaderLen + 50,
+			expectedSNI:    "foo.example.com",
+			expectedProtos: []string{"h2", "http/1.1"},
+		},
+		{
+			desc:        "fragmented ClientHello exceeding the maximum size",
+			fragmentLen: 50,
+			maxSize:     128,
+		},
+		{
+			desc:        "fragmented ClientHello truncated by the client",
+			fragmentLen: 50,
+			maxSize:     defaultMaxClientHelloSize,
+			truncate:    2 * (recordHeaderLen + 50),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			data := record
+			if test.fragmentLen > 0 {
+				data = fragmentRecord(record, test.fragmentLen)
+			}
+			if test.truncate > 0 {
+				data = data[:test.truncate]
+			}
+
+			// The bytes are received one by one, as from a network splitting them across small segments.
+			readerSize := defaultBufSize
+			if test.readerSize > 0 {
+				readerSize = test.readerSize
+			}
+			br := bufio.NewReaderSize(iotest.OneByteReader(bytes.NewReader(data)), readerSize)
+
+			hello, err := clientHelloInfo(br, test.maxSize)
+			require.NoError(t, err)
+
+			assert.True(t, hello.isTLS)
+			assert.Equal(t, test.expectedSNI, hello.serverName)
+			assert.Equal(t, test.expectedProtos, hello.protos)
+			// The peeked bytes are restored to the connection, they start it.
+			assert.True(t, strings.HasPrefix(string(data), hello.peeked))
+			if test.expectedSNI != "" {
+				assert.Equal(t, string(data), hello.peeked)
+			}
+		})
+	}
+}
+
+// clientHelloRecord returns the TLS record of the ClientHello sent by a client with the given SNI and ALPN protocols.
+func clientHelloRecord(t *testing.T, serverName string, protos []string) []byte {
+	t.Helper()
+
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() { _ = serverConn.Close() })
+
+	go func() {
+		_ = tls.Client(clientConn, &tls.Config{ServerName: serverName, NextProtos: protos, InsecureSkipVerify: true}).Handshake()
+	}()
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	hdr := make([]byte, recordHeaderLen)
+	_, err := io.ReadFull(serverConn, hdr)
+	require.NoError(t, err)
+
+	record := make([]byte, recordHeaderLen+(int(hdr[3])<<8|int(hdr[4])))
+	copy(record, hdr)
+	_, err = io.ReadFull(serverConn, record[recordHeaderLen:])
+	require.NoError(t, err)
+
+	return record
+}
+
+// fragmentRecord splits the handshake message of the given TLS record across records of fragmentLen bytes.
+func fragmentRecord(record []byte, fragmentLen int) []byte {
+	var fragmented []byte
+	for message := record[recordHeaderLen:]; len(message) > 0; {
+		fragment := message[:min(fragmentLen, len(message))]
+		message = message[len(fragment):]
+
+		fragmented = append(fragmented, record[0], record[1], record[2], byte(len(fragment)>>8), byte(len(fragment)))
+		fragmented = append(fragmented, fragment...)
+	}
+
+	return fragmented
+}
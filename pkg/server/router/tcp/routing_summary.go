@@ -0,0 +1,105 @@
+package tcp
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// RoutingSummary periodically logs the number of connections routed by each TCP router of an entry point,
+// instead of logging each routing decision.
+// A nil RoutingSummary records nothing.
+type RoutingSummary struct {
+	interval time.Duration
+	logger   zerolog.Logger
+
+	mu        sync.Mutex
+	routed    map[string]int
+	unmatched int
+	// timer is only armed while there are recorded connections, so that nothing is logged for the intervals without any.
+	timer *time.Timer
+}
+
+// NewRoutingSummary creates a new RoutingSummary of the given entry point, logged at the given interval.
+func NewRoutingSummary(entryPointName string, interval time.Duration) *RoutingSummary {
+	return &RoutingSummary{
+		interval: interval,
+		logger:   log.With().Str(logs.EntryPointName, entryPointName).Logger(),
+		routed:   make(map[string]int),
+	}
+}
+
+// Record records a connection routed to the named router.
+func (s *RoutingSummary) Record(routerName string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routed[routerName]++
+	s.schedule()
+}
+
+// RecordUnmatched records a connection closed because it did not match any router.
+func (s *RoutingSummary) RecordUnmatched() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unmatched++
+	s.schedule()
+}
+
+func (s *RoutingSummary) schedule() {
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.interval, s.flush)
+	}
+}
+
+// flush logs the connections recorded during the last interval, and resets them.
+func (s *RoutingSummary) flush() {
+	s.mu.Lock()
+	routed, unmatched := s.routed, s.unmatched
+	s.routed = make(map[string]int)
+	s.unmatched = 0
+	s.timer = nil
+	s.mu.Unlock()
+
+	routerNames := make([]string, 0, len(routed))
+	for routerName := range routed {
+		routerNames = append(routerNames, routerName)
+	}
+	sort.Strings(routerNames)
+
+	for _, routerName := range routerNames {
+		s.logger.Info().
+			Str(logs.RouterName, routerName).
+			Int("connections", routed[routerName]).
+			Msgf("%d connections routed to router %s in the last %s", routed[routerName], routerName, s.interval)
+	}
+
+	if unmatched > 0 {
+		s.logger.Info().
+			Int("connections", unmatched).
+			Msgf("%d connections closed without matching any router in the last %s", unmatched, s.interval)
+	}
+}
+
+// withRoutingSummary records the connections routed to the named router on the routing summary of the entry point router, if any.
+func withRoutingSummary(router *Router, routerName string, next tcp.Handler) tcp.Handler {
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		router.routingSummary.Record(routerName)
+
+		next.ServeTCP(conn)
+	})
+}
@@ -0,0 +1,89 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the logger and reads of the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines(t *testing.T) []map[string]any {
+	t.Helper()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+
+	return lines
+}
+
+func TestRoutingSummary(t *testing.T) {
+	var logs syncBuffer
+
+	interval := 100 * time.Millisecond
+	summary := NewRoutingSummary("web", interval)
+	summary.logger = zerolog.New(&logs)
+
+	for range 50 {
+		summary.Record("foo@file")
+	}
+	for range 3 {
+		summary.Record("bar@file")
+	}
+	summary.RecordUnmatched()
+
+	// Nothing is logged per connection.
+	assert.Empty(t, logs.lines(t))
+
+	require.Eventually(t, func() bool { return len(logs.lines(t)) > 0 }, 5*interval, 10*time.Millisecond)
+
+	lines := logs.lines(t)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "bar@file", lines[0]["routerName"])
+	assert.InDelta(t, 3, lines[0]["connections"], 0)
+	assert.Equal(t, "foo@file", lines[1]["routerName"])
+	assert.InDelta(t, 50, lines[1]["connections"], 0)
+	assert.Equal(t, "1 connections closed without matching any router in the last 100ms", lines[2]["message"])
+
+	// The next summary only covers the connections of its own interval.
+	summary.Record("foo@file")
+
+	require.Eventually(t, func() bool { return len(logs.lines(t)) > 3 }, 5*interval, 10*time.Millisecond)
+
+	lines = logs.lines(t)
+	require.Len(t, lines, 4)
+	assert.Equal(t, "1 connections routed to router foo@file in the last 100ms", lines[3]["message"])
+
+	// Nothing is logged for the intervals without any connection.
+	time.Sleep(2 * interval)
+	assert.Len(t, logs.lines(t), 4)
+}
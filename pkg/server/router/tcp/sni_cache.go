@@ -0,0 +1,84 @@
+package tcp
+
+import (
+	"container/list"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// SNICacheConfig configures the caches of the TCP TLS routing decisions by SNI of an entry point.
+type SNICacheConfig struct {
+	// MaxSize is the maximum number of cached SNIs.
+	MaxSize int
+	// Lookups counts the lookups in the cache, labeled by result: hit or miss.
+	Lookups gokitmetrics.Counter
+}
+
+// sniCache is a bounded LRU cache of the TCP TLS routing decisions by SNI.
+// As the cached handlers are the ones of a router, a cache lives as long as the router it is created for.
+type sniCache struct {
+	maxSize int
+	hits    gokitmetrics.Counter
+	misses  gokitmetrics.Counter
+
+	mu sync.Mutex
+	// entries index the elements of the recency list by SNI, the most recently used element being at the front.
+	entries map[string]*list.Element
+	recency *list.List
+}
+
+type sniCacheEntry struct {
+	serverName string
+	handler    tcp.Handler
+	catchAll   bool
+}
+
+func newSNICache(config *SNICacheConfig) *sniCache {
+	return &sniCache{
+		maxSize: config.MaxSize,
+		hits:    config.Lookups.With("result", "hit"),
+		misses:  config.Lookups.With("result", "miss"),
+		entries: make(map[string]*list.Element),
+		recency: list.New(),
+	}
+}
+
+// get returns the cached routing decision for the given SNI, and whether it is cached.
+func (c *sniCache) get(serverName string) (tcp.Handler, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[serverName]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false, false
+	}
+
+	c.hits.Add(1)
+	c.recency.MoveToFront(element)
+
+	entry := element.Value.(*sniCacheEntry)
+	return entry.handler, entry.catchAll, true
+}
+
+// add caches the routing decision for the given SNI, evicting the least recently used one when the cache is full.
+func (c *sniCache) add(serverName string, handler tcp.Handler, catchAll bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[serverName]; ok {
+		c.recency.MoveToFront(element)
+		element.Value = &sniCacheEntry{serverName: serverName, handler: handler, catchAll: catchAll}
+		return
+	}
+
+	if c.recency.Len() >= c.maxSize {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*sniCacheEntry).serverName)
+	}
+
+	c.entries[serverName] = c.recency.PushFront(&sniCacheEntry{serverName: serverName, handler: handler, catchAll: catchAll})
+}
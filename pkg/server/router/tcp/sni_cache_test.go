@@ -0,0 +1,68 @@
+package tcp
+
+import (
+	"sync"
+	"testing"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// resultsCounter counts the added values by result.
+type resultsCounter struct {
+	mu      *sync.Mutex
+	results map[string]float64
+	result  string
+}
+
+func (c resultsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "result" {
+			c.result = labelValues[i+1]
+		}
+	}
+
+	return c
+}
+
+func (c resultsCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.results[c.result] += delta
+}
+
+func TestSNICache(t *testing.T) {
+	lookups := resultsCounter{mu: &sync.Mutex{}, results: make(map[string]float64)}
+	cache := newSNICache(&SNICacheConfig{MaxSize: 2, Lookups: lookups})
+
+	foo := tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {})
+
+	_, _, ok := cache.get("foo.com")
+	assert.False(t, ok)
+
+	cache.add("foo.com", foo, false)
+	cache.add("bar.com", nil, false)
+
+	handler, catchAll, ok := cache.get("foo.com")
+	assert.True(t, ok)
+	assert.NotNil(t, handler)
+	assert.False(t, catchAll)
+
+	// The cache is full, the least recently used SNI is evicted.
+	cache.add("baz.com", foo, true)
+	assert.Len(t, cache.entries, 2)
+
+	_, _, ok = cache.get("bar.com")
+	assert.False(t, ok)
+
+	_, _, ok = cache.get("foo.com")
+	assert.True(t, ok)
+
+	_, catchAll, ok = cache.get("baz.com")
+	assert.True(t, ok)
+	assert.True(t, catchAll)
+
+	assert.Equal(t, map[string]float64{"hit": 3, "miss": 2}, lookups.results)
+}
@@ -2,10 +2,13 @@ package server
 
 import (
 	"context"
+	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v3/pkg/server/router"
@@ -33,6 +36,37 @@ type RouterFactory struct {
 
 	dialerManager *tcp.DialerManager
 
+	// tlsHandshakeLimiters are kept across the configuration reloads, as they track the in progress handshakes.
+	tlsHandshakeLimiters map[string]*tcp.TLSHandshakeLimiter
+	// certificateTrackers are kept across the configuration reloads, as they track the certificates served to the active connections.
+	certificateTrackers map[string]*tcp.CertificateTracker
+
+	// routingSummaries are kept across the configuration reloads, as they track the connections routed during the current interval.
+	routingSummaries map[string]*tcprouter.RoutingSummary
+
+	// concurrencySampler is kept across the configuration reloads, as it tracks the concurrent connections of the routers.
+	concurrencySampler *tcprouter.ConcurrencySampler
+
+	sniLengthLimits       map[string]*tcprouter.SNILengthLimit
+	sniCacheConfigs       map[string]*tcprouter.SNICacheConfig
+	maxClientHelloSizes   map[string]int
+	signaturePeekTimeouts map[string]time.Duration
+	idleReapedConns       gokitmetrics.Counter
+	// dialDurations, mirrorComparisons, and serverConns are only set when the service metrics are enabled.
+	dialDurations     metrics.ScalableHistogram
+	mirrorComparisons gokitmetrics.Counter
+	serverConns       gokitmetrics.Gauge
+
+	inFlightClients       gokitmetrics.Gauge
+	inFlightQueueWait     metrics.ScalableHistogram
+	inFlightQueueTimeouts gokitmetrics.Counter
+	ipDecisionLookups     gokitmetrics.Counter
+
+	// admission is kept across the configuration reloads, as it tracks the connections to the TCP services.
+	admission *tcp.Admission
+
+	flowRecorder tcp.FlowRecorder
+
 	cancelPrevState func()
 }
 
@@ -40,7 +74,18 @@ type RouterFactory struct {
 func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *service.ManagerFactory, tlsManager *tls.Manager,
 	observabilityMgr *middleware.ObservabilityMgr, pluginBuilder middleware.PluginsBuilder, dialerManager *tcp.DialerManager,
 ) *RouterFactory {
+	metricsRegistry := observabilityMgr.MetricsRegistry()
+	if metricsRegistry == nil {
+		metricsRegistry = metrics.NewVoidRegistry()
+	}
+
 	var entryPointsTCP, entryPointsUDP []string
+	tlsHandshakeLimiters := make(map[string]*tcp.TLSHandshakeLimiter)
+	sniLengthLimits := make(map[string]*tcprouter.SNILengthLimit)
+	sniCacheConfigs := make(map[string]*tcprouter.SNICacheConfig)
+	maxClientHelloSizes := make(map[string]int)
+	signaturePeekTimeouts := make(map[string]time.Duration)
+	routingSummaries := make(map[string]*tcprouter.RoutingSummary)
 	for name, cfg := range staticConfiguration.EntryPoints {
 		protocol, err := cfg.GetProtocol()
 		if err != nil {
@@ -53,6 +98,67 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		} else {
 			entryPointsTCP = append(entryPointsTCP, name)
 		}
+
+		if cfg.Transport != nil && cfg.Transport.TLSHandshakes != nil && cfg.Transport.TLSHandshakes.MaxConcurrent > 0 {
+			tlsHandshakeLimiters[name] = tcp.NewTLSHandshakeLimiter(
+				cfg.Transport.TLSHandshakes.MaxConcurrent,
+				time.Duration(cfg.Transport.TLSHandshakes.QueueTimeout),
+				metricsRegistry.TLSHandshakesInProgressGauge().With("entrypoint", name),
+				metricsRegistry.TLSHandshakesQueuedGauge().With("entrypoint", name),
+			)
+		}
+
+		if cfg.Transport != nil && cfg.Transport.MaxSNILength > 0 {
+			sniLengthLimits[name] = &tcprouter.SNILengthLimit{
+				MaxLength: cfg.Transport.MaxSNILength,
+				Rejects:   metricsRegistry.TLSSNIRejectsCounter().With("entrypoint", name),
+			}
+		}
+
+		if cfg.Transport != nil && cfg.Transport.SNICacheSize > 0 {
+			sniCacheConfigs[name] = &tcprouter.SNICacheConfig{
+				MaxSize: cfg.Transport.SNICacheSize,
+				Lookups: metricsRegistry.TLSSNICacheLookupsCounter().With("entrypoint", name),
+			}
+		}
+
+		if cfg.Transport != nil && cfg.Transport.MaxClientHelloSize > 0 {
+			maxClientHelloSizes[name] = cfg.Transport.MaxClientHelloSize
+		}
+
+		if cfg.Transport != nil && cfg.Transport.SignaturePeekTimeout > 0 {
+			signaturePeekTimeouts[name] = time.Duration(cfg.Transport.SignaturePeekTimeout)
+		}
+
+		if cfg.Transport != nil && cfg.Transport.RoutingSummaryInterval > 0 {
+			routingSummaries[name] = tcprouter.NewRoutingSummary(name, time.Duration(cfg.Transport.RoutingSummaryInterval))
+		}
+	}
+
+	var dialDurations metrics.ScalableHistogram
+	var mirrorComparisons gokitmetrics.Counter
+	var serverConns gokitmetrics.Gauge
+	if metricsRegistry.IsSvcEnabled() {
+		dialDurations = metricsRegistry.ServiceTCPDialDurationHistogram()
+		mirrorComparisons = metricsRegistry.ServiceTCPComparisonsCounter()
+		serverConns = metricsRegistry.ServiceTCPServerConnsGauge()
+	}
+
+	var idleReapedConns gokitmetrics.Counter
+	var concurrencySampler *tcprouter.ConcurrencySampler
+	if metricsRegistry.IsRouterEnabled() {
+		idleReapedConns = metricsRegistry.TCPRouterIdleReapedConnsCounter()
+		concurrencySampler = tcprouter.NewConcurrencySampler(metricsRegistry.TCPRouterConcurrencyHistogram())
+	}
+
+	var admission *tcp.Admission
+	if staticConfiguration.TCPAdmission != nil {
+		admission = tcp.NewAdmission(
+			staticConfiguration.TCPAdmission.MaxConnections,
+			staticConfiguration.TCPAdmission.PressureThreshold,
+			metricsRegistry.TCPAdmissionConnsGauge(),
+			metricsRegistry.TCPAdmissionRejectsCounter(),
+		)
 	}
 
 	return &RouterFactory{
@@ -63,9 +169,32 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		tlsManager:       tlsManager,
 		pluginBuilder:    pluginBuilder,
 		dialerManager:    dialerManager,
+
+		tlsHandshakeLimiters:  tlsHandshakeLimiters,
+		sniLengthLimits:       sniLengthLimits,
+		sniCacheConfigs:       sniCacheConfigs,
+		maxClientHelloSizes:   maxClientHelloSizes,
+		signaturePeekTimeouts: signaturePeekTimeouts,
+		routingSummaries:      routingSummaries,
+		idleReapedConns:       idleReapedConns,
+		certificateTrackers:   make(map[string]*tcp.CertificateTracker),
+		concurrencySampler:    concurrencySampler,
+		dialDurations:         dialDurations,
+		mirrorComparisons:     mirrorComparisons,
+		serverConns:           serverConns,
+		inFlightClients:       metricsRegistry.TCPInFlightClientsGauge(),
+		inFlightQueueWait:     metricsRegistry.TCPInFlightQueueWaitHistogram(),
+		inFlightQueueTimeouts: metricsRegistry.TCPInFlightQueueTimeoutsCounter(),
+		ipDecisionLookups:     metricsRegistry.TCPIPDecisionCacheLookupsCounter(),
+		admission:             admission,
 	}
 }
 
+// SetFlowRecorder sets the recorder of the flows of the connections forwarded to the servers of the TCP services.
+func (f *RouterFactory) SetFlowRecorder(recorder tcp.FlowRecorder) {
+	f.flowRecorder = recorder
+}
+
 // CreateRouters creates new TCPRouters and UDPRouters.
 func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string]*tcprouter.Router, map[string]udp.Handler) {
 	if f.cancelPrevState != nil {
@@ -89,12 +218,32 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	// TCP
 	svcTCPManager := tcpsvc.NewManager(rtConf, f.dialerManager)
+	svcTCPManager.SetIdleReapedConnsCounter(f.idleReapedConns)
+	svcTCPManager.SetDialDurationHistogram(f.dialDurations)
+	svcTCPManager.SetMirrorComparisonsCounter(f.mirrorComparisons)
+	svcTCPManager.SetServerConnsGauge(f.serverConns)
+	svcTCPManager.SetAdmission(f.admission)
+	svcTCPManager.SetFlowRecorder(f.flowRecorder)
 
 	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares)
+	middlewaresTCPBuilder.SetInFlightClientsGauge(f.inFlightClients)
+	middlewaresTCPBuilder.SetInFlightQueueMetrics(f.inFlightQueueWait, f.inFlightQueueTimeouts)
+	middlewaresTCPBuilder.SetIPDecisionCacheLookupsCounter(f.ipDecisionLookups)
 
 	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
+	rtTCPManager.SetTLSHandshakeLimiters(f.tlsHandshakeLimiters)
+	rtTCPManager.SetSNILengthLimits(f.sniLengthLimits)
+	rtTCPManager.SetSNICacheConfigs(f.sniCacheConfigs)
+	rtTCPManager.SetMaxClientHelloSizes(f.maxClientHelloSizes)
+	rtTCPManager.SetSignaturePeekTimeouts(f.signaturePeekTimeouts)
+	rtTCPManager.SetRoutingSummaries(f.routingSummaries)
+	rtTCPManager.SetCertificateTrackers(f.certificateTrackers)
+	rtTCPManager.SetConcurrencySampler(f.concurrencySampler)
+	rtTCPManager.SetIPDecisionCacheLookupsCounter(f.ipDecisionLookups)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
 
+	svcTCPManager.LaunchHealthCheck(ctx)
+
 	// UDP
 	svcUDPManager := udpsvc.NewManager(rtConf)
 	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager)
@@ -170,6 +170,14 @@ type TCPEntryPoint struct {
 
 // NewTCPEntryPoint creates a new TCPEntryPoint.
 func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hostResolverConfig *types.HostResolverConfig, openConnectionsGauge gokitmetrics.Gauge) (*TCPEntryPoint, error) {
+	if configuration.Transport != nil && configuration.Transport.RemovedRouters != nil {
+		switch configuration.Transport.RemovedRouters.CloseMode {
+		case static.RemovedRoutersGracefulClose, static.RemovedRoutersReset:
+		default:
+			return nil, fmt.Errorf("unsupported close mode of the removed routers connections: %q", configuration.Transport.RemovedRouters.CloseMode)
+		}
+	}
+
 	tracker := newConnectionTracker(openConnectionsGauge)
 
 	listener, err := buildListener(ctx, configuration)
@@ -226,7 +234,12 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 	for {
 		conn, err := e.listener.Accept()
 		if err != nil {
-			logger.Error().Err(err).Send()
+			if errors.Is(err, net.ErrClosed) {
+				// The listener is closed during the shutdown, once the request accept grace period is over.
+				logger.Debug().Msg("Stopped accepting new connections")
+			} else {
+				logger.Error().Err(err).Send()
+			}
 
 			var opErr *net.OpError
 			if errors.As(err, &opErr) && opErr.Temporary() {
@@ -267,7 +280,7 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 				}
 			}
 
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			e.switcher.ServeTCP(tcp.WithConnAttributes(newTrackedConnection(writeCloser, e.tracker)))
 		})
 	}
 }
@@ -368,6 +381,10 @@ func (e *TCPEntryPoint) SwitchRouter(rt *tcprouter.Router) {
 	if e.http3Server != nil {
 		e.http3Server.Switch(rt)
 	}
+
+	if e.transportConfiguration.RemovedRouters != nil {
+		e.tracker.closeRemovedRouters(rt, e.transportConfiguration.RemovedRouters.CloseMode)
+	}
 }
 
 // writeCloserWrapper wraps together a connection, and the concrete underlying
@@ -381,6 +398,11 @@ func (c *writeCloserWrapper) CloseWrite() error {
 	return c.writeCloser.CloseWrite()
 }
 
+// NetConn returns the concrete underlying connection.
+func (c *writeCloserWrapper) NetConn() net.Conn {
+	return c.writeCloser
+}
+
 // writeCloser returns the given connection, augmented with the WriteCloser
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
@@ -480,22 +502,23 @@ func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.List
 
 func newConnectionTracker(openConnectionsGauge gokitmetrics.Gauge) *connectionTracker {
 	return &connectionTracker{
-		conns:                make(map[net.Conn]struct{}),
+		conns:                make(map[net.Conn]*tcp.ConnAttributes),
 		openConnectionsGauge: openConnectionsGauge,
 	}
 }
 
 type connectionTracker struct {
 	connsMu sync.RWMutex
-	conns   map[net.Conn]struct{}
+	// conns are the tracked connections, along with their attributes.
+	conns map[net.Conn]*tcp.ConnAttributes
 
 	openConnectionsGauge gokitmetrics.Gauge
 }
 
 // AddConnection add a connection in the tracked connections list.
-func (c *connectionTracker) AddConnection(conn net.Conn) {
+func (c *connectionTracker) AddConnection(conn net.Conn, attributes *tcp.ConnAttributes) {
 	c.connsMu.Lock()
-	c.conns[conn] = struct{}{}
+	c.conns[conn] = attributes
 	c.connsMu.Unlock()
 
 	if c.openConnectionsGauge != nil {
@@ -540,7 +563,11 @@ func (c *connectionTracker) Shutdown(ctx context.Context) error {
 func (c *connectionTracker) Close() {
 	c.connsMu.Lock()
 	defer c.connsMu.Unlock()
-	for conn := range c.conns {
+	for conn, attributes := range c.conns {
+		if err := attributes.WriteCloseReason(tcp.CloseReasonDraining); err != nil {
+			log.Debug().Err(err).Msg("Error while writing the close reason frame")
+		}
+
 		if err := conn.Close(); err != nil {
 			log.Error().Err(err).Msg("Error while closing connection")
 		}
@@ -548,6 +575,55 @@ func (c *connectionTracker) Close() {
 	}
 }
 
+// closeRemovedRouters closes the connections routed by the TCP routers which are not part of the given router anymore.
+func (c *connectionTracker) closeRemovedRouters(rt *tcprouter.Router, closeMode string) {
+	removed := make(map[net.Conn]*tcp.ConnAttributes)
+
+	c.connsMu.RLock()
+	for conn, attributes := range c.conns {
+		routerName, ok := attributes.Get(tcp.RouterAttribute)
+		if ok && !rt.HasRouter(routerName) {
+			removed[conn] = attributes
+		}
+	}
+	c.connsMu.RUnlock()
+
+	for conn, attributes := range removed {
+		log.Debug().Str("remoteAddr", conn.RemoteAddr().String()).Str("closeMode", closeMode).
+			Msg("Closing connection as its router has been removed")
+
+		// The close reason frame would be discarded by the reset.
+		if closeMode != static.RemovedRoutersReset {
+			if err := attributes.WriteCloseReason(tcp.CloseReasonDraining); err != nil {
+				log.Debug().Err(err).Msg("Error while writing the close reason frame")
+			}
+		}
+
+		if err := closeConn(conn, closeMode); err != nil {
+			log.Debug().Err(err).Msg("Error while closing connection")
+		}
+	}
+}
+
+// closeConn closes the given connection, with a RST if the close mode is reset.
+func closeConn(conn net.Conn, closeMode string) error {
+	if closeMode == static.RemovedRoutersReset {
+		tcpConn := conn
+		if wrapper, ok := conn.(*writeCloserWrapper); ok {
+			tcpConn = wrapper.writeCloser
+		}
+
+		if lingerer, ok := tcpConn.(interface{ SetLinger(sec int) error }); ok {
+			// Discards the pending data, for the connection to be closed with a RST.
+			if err := lingerer.SetLinger(0); err != nil {
+				return err
+			}
+		}
+	}
+
+	return conn.Close()
+}
+
 type stoppable interface {
 	Shutdown(ctx context.Context) error
 	Close() error
@@ -676,16 +752,30 @@ func getConnKey(conn net.Conn) string {
 }
 
 func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *trackedConnection {
-	tracker.AddConnection(conn)
+	attributes := &tcp.ConnAttributes{}
+	tracker.AddConnection(conn, attributes)
 	return &trackedConnection{
 		WriteCloser: conn,
 		tracker:     tracker,
+		attributes:  attributes,
 	}
 }
 
+// trackedConnection carries the attributes of the connection, for the tracker to know the router the connection is routed by.
 type trackedConnection struct {
 	tracker *connectionTracker
 	tcp.WriteCloser
+	attributes *tcp.ConnAttributes
+}
+
+// Attributes returns the attributes of the connection.
+func (t *trackedConnection) Attributes() *tcp.ConnAttributes {
+	return t.attributes
+}
+
+// NetConn returns the tracked connection.
+func (t *trackedConnection) NetConn() net.Conn {
+	return t.WriteCloser
 }
 
 func (t *trackedConnection) Close() error {
@@ -8,6 +8,7 @@ import (
 	"net"
 	"net/http"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -61,6 +62,155 @@ func TestShutdownTCP(t *testing.T) {
 	testShutdown(t, router)
 }
 
+func TestShutdownTCPGraceTimeOut(t *testing.T) {
+	router, err := tcprouter.NewRouter()
+	require.NoError(t, err)
+
+	err = router.AddTCPRoute("HostSNI(`*`)", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		// Echo until the connection is closed.
+		_, _ = io.Copy(conn, conn)
+	}))
+	require.NoError(t, err)
+
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	epConfig.LifeCycle.RequestAcceptGraceTimeout = 0
+	epConfig.LifeCycle.GraceTimeOut = ptypes.Duration(time.Second)
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "127.0.0.1:0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		HTTP2:            &static.HTTP2Config{},
+	}, nil, nil)
+	require.NoError(t, err)
+
+	conn, err := startEntrypoint(entryPoint, router)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		entryPoint.Shutdown(context.Background())
+		close(shutdownDone)
+	}()
+
+	// The active connection is still operational during the grace period.
+	time.Sleep(100 * time.Millisecond)
+
+	_, err = conn.Write([]byte("pong"))
+	require.NoError(t, err)
+
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(buf))
+
+	select {
+	case <-shutdownDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not end after the grace period")
+	}
+
+	// Once the grace period is over, the remaining connections are closed.
+	err = conn.SetReadDeadline(time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	_, err = conn.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestRemovedRouterConnections(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		closeMode string
+		assertErr assert.ErrorAssertionFunc
+	}{
+		{
+			desc:      "graceful close",
+			closeMode: static.RemovedRoutersGracefulClose,
+			assertErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, io.EOF)
+			},
+		},
+		{
+			desc:      "reset",
+			closeMode: static.RemovedRoutersReset,
+			assertErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				return assert.ErrorIs(t, err, syscall.ECONNRESET)
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			router, err := tcprouter.NewRouter()
+			require.NoError(t, err)
+
+			err = router.AddTCPRoute("HostSNI(`*`)", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				tcp.GetConnAttributes(conn).Set(tcp.RouterAttribute, "foo@file")
+
+				// Echo until the connection is closed.
+				_, _ = io.Copy(conn, conn)
+			}))
+			require.NoError(t, err)
+			router.SetRouterNames(map[string]struct{}{"foo@file": {}})
+
+			epConfig := &static.EntryPointsTransport{}
+			epConfig.SetDefaults()
+			epConfig.RemovedRouters = &static.RemovedRouters{CloseMode: test.closeMode}
+
+			entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+				Address:          "127.0.0.1:0",
+				Transport:        epConfig,
+				ForwardedHeaders: &static.ForwardedHeaders{},
+				HTTP2:            &static.HTTP2Config{},
+			}, nil, nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+			conn, err := startEntrypoint(entryPoint, router)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			_, err = conn.Write([]byte("ping"))
+			require.NoError(t, err)
+
+			buf := make([]byte, 4)
+			_, err = io.ReadFull(conn, buf)
+			require.NoError(t, err)
+
+			// The connections of the routers which are still part of the configuration are kept.
+			entryPoint.SwitchRouter(router)
+
+			_, err = conn.Write([]byte("pong"))
+			require.NoError(t, err)
+
+			_, err = io.ReadFull(conn, buf)
+			require.NoError(t, err)
+			assert.Equal(t, "pong", string(buf))
+
+			emptyRouter, err := tcprouter.NewRouter()
+			require.NoError(t, err)
+
+			entryPoint.SwitchRouter(emptyRouter)
+
+			err = conn.SetReadDeadline(time.Now().Add(time.Second))
+			require.NoError(t, err)
+
+			_, err = conn.Read(buf)
+			test.assertErr(t, err)
+		})
+	}
+}
+
 func testShutdown(t *testing.T, router *tcprouter.Router) {
 	t.Helper()
 
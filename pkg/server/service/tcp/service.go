@@ -8,9 +8,13 @@ import (
 	"net"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/healthcheck"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 	"golang.org/x/net/proxy"
@@ -21,17 +25,72 @@ type Manager struct {
 	dialerManager *tcp.DialerManager
 	configs       map[string]*runtime.TCPServiceInfo
 	rand          *rand.Rand // For the initial shuffling of load-balancers.
+	// healthCheckers are indexed by service name,
+	// a service used by several routers has one load balancer, and one health checker, per router.
+	healthCheckers map[string][]*healthcheck.ServiceTCPHealthChecker
+	// idleReapedConns counts the connections closed for exceeding the idle timeout of their service, by router.
+	idleReapedConns gokitmetrics.Counter
+	// dialDurations observes the duration of the dials to the servers, by service and server.
+	dialDurations metrics.ScalableHistogram
+	// serverConns measures the connections open to the servers of the services limiting their connections per server, by service and server.
+	serverConns gokitmetrics.Gauge
+	// mirrorComparisons counts the comparisons of the responses of the mirrors to the ones of the services, by service and result.
+	mirrorComparisons gokitmetrics.Counter
+	// closeReasons are the close reason encoders of the services, indexed by service name.
+	closeReasons map[string]tcp.CloseReasonEncoder
+	// admission, when set, admits the connections to the load balancers by priority under connection pressure.
+	admission *tcp.Admission
+	// flowRecorder, when set, records the flow of each connection forwarded to a server.
+	flowRecorder tcp.FlowRecorder
 }
 
 // NewManager creates a new manager.
 func NewManager(conf *runtime.Configuration, dialerManager *tcp.DialerManager) *Manager {
 	return &Manager{
-		dialerManager: dialerManager,
-		configs:       conf.TCPServices,
-		rand:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		dialerManager:  dialerManager,
+		configs:        conf.TCPServices,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		healthCheckers: make(map[string][]*healthcheck.ServiceTCPHealthChecker),
+		closeReasons:   make(map[string]tcp.CloseReasonEncoder),
 	}
 }
 
+// SetIdleReapedConnsCounter sets the counter of the connections closed for exceeding the idle timeout of their service.
+func (m *Manager) SetIdleReapedConnsCounter(counter gokitmetrics.Counter) {
+	m.idleReapedConns = counter
+}
+
+// SetDialDurationHistogram sets the histogram observing the duration of the dials to the servers of the services.
+func (m *Manager) SetDialDurationHistogram(histogram metrics.ScalableHistogram) {
+	m.dialDurations = histogram
+}
+
+// SetServerConnsGauge sets the gauge measuring the connections open to the servers of the services limiting their connections per server.
+func (m *Manager) SetServerConnsGauge(gauge gokitmetrics.Gauge) {
+	m.serverConns = gauge
+}
+
+// SetMirrorComparisonsCounter sets the counter of the comparisons of the responses of the mirrors to the ones of the services.
+func (m *Manager) SetMirrorComparisonsCounter(counter gokitmetrics.Counter) {
+	m.mirrorComparisons = counter
+}
+
+// SetAdmission sets the admission of the connections to the load balancers, shared by all the services.
+func (m *Manager) SetAdmission(admission *tcp.Admission) {
+	m.admission = admission
+}
+
+// SetFlowRecorder sets the recorder of the flows of the connections forwarded to the servers of the services.
+func (m *Manager) SetFlowRecorder(recorder tcp.FlowRecorder) {
+	m.flowRecorder = recorder
+}
+
+// CloseReasonEncoder returns the encoder of the close reasons of the given service, once built,
+// or nil when the connections of the service are closed without frame.
+func (m *Manager) CloseReasonEncoder(ctx context.Context, serviceName string) tcp.CloseReasonEncoder {
+	return m.closeReasons[provider.GetQualifiedName(ctx, serviceName)]
+}
+
 // BuildTCP Creates a tcp.Handler for a service configuration.
 func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Handler, error) {
 	serviceQualifiedName := provider.GetQualifiedName(rootCtx, serviceName)
@@ -44,7 +103,7 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
 	}
 
-	if conf.LoadBalancer != nil && conf.Weighted != nil {
+	if countServiceTypes(conf.TCPService) > 1 {
 		err := errors.New("cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 		conf.AddError(err, true)
 		return nil, err
@@ -52,7 +111,79 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		var loadBalancer interface {
+			tcp.Handler
+			healthcheck.StatusSetter
+		}
+		// addServer adds a server to the load balancer, the name being used by the health check, if any.
+		var addServer func(name, address string, handler tcp.Handler)
+
+		switch conf.LoadBalancer.Strategy {
+		case "", dynamic.TCPBalancerStrategyRoundRobin:
+			wrr := tcp.NewWRRLoadBalancer(conf.LoadBalancer.Sticky, conf.LoadBalancer.HashSeed)
+			loadBalancer = wrr
+			addServer = func(name, _ string, handler tcp.Handler) {
+				wrr.AddNamedServer(name, handler)
+			}
+
+		case dynamic.TCPBalancerStrategyConsistentHashing:
+			if conf.LoadBalancer.Sticky != nil {
+				logger.Warn().Msg("Sticky is ignored by the consistentHashing strategy, which already forwards the connections of a client IP to the same server")
+			}
+
+			consistentHash := tcp.NewConsistentHashLoadBalancer(conf.LoadBalancer.HashSeed)
+			loadBalancer = consistentHash
+			addServer = func(name, address string, handler tcp.Handler) {
+				// The servers are placed on the hash ring from their address, which is stable across the configuration reloads.
+				consistentHash.AddServer(address, name, handler)
+			}
+
+		default:
+			err := fmt.Errorf("unknown load balancing strategy %q", conf.LoadBalancer.Strategy)
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		if err := validateDialFailover(conf.LoadBalancer); err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		if err := tcp.ValidateAdmissionPriority(conf.LoadBalancer.Priority); err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		if conf.LoadBalancer.MaxConnectionsPerServer < 0 {
+			err := fmt.Errorf("invalid maxConnectionsPerServer %d: must be positive", conf.LoadBalancer.MaxConnectionsPerServer)
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		var prefixFrame *tcp.PrefixFrame
+		if conf.LoadBalancer.PrefixFrame != "" {
+			var err error
+			prefixFrame, err = tcp.NewPrefixFrame(conf.LoadBalancer.PrefixFrame)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+		}
+
+		if len(conf.LoadBalancer.CloseReasonFrames) > 0 {
+			closeReasonFrames, err := tcp.NewCloseReasonFrames(conf.LoadBalancer.CloseReasonFrames)
+			if err != nil {
+				conf.AddError(err, true)
+				return nil, err
+			}
+
+			m.closeReasons[serviceQualifiedName] = closeReasonFrames
+		}
+
+		var failover *tcp.DialFailover
+		if conf.LoadBalancer.PerAttemptDialTimeout > 0 {
+			failover = tcp.NewDialFailover(time.Duration(conf.LoadBalancer.PerAttemptDialTimeout), time.Duration(conf.LoadBalancer.ConnectTimeout))
+		}
 
 		if conf.LoadBalancer.TerminationDelay != nil {
 			log.Ctx(ctx).Warn().Msgf("Service %q load balancer uses `TerminationDelay`, but this option is deprecated, please use ServersTransport configuration instead.", serviceName)
@@ -62,7 +193,16 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 			conf.LoadBalancer.ServersTransport = provider.GetQualifiedName(ctx, conf.LoadBalancer.ServersTransport)
 		}
 
-		for index, server := range shuffle(conf.LoadBalancer.Servers, m.rand) {
+		servers := conf.LoadBalancer.Servers
+		// The servers order is kept with the sticky mode,
+		// so that a client keeps being forwarded to the same server across configuration reloads.
+		if conf.LoadBalancer.Sticky == nil {
+			servers = shuffle(servers, m.rand)
+		}
+
+		healthCheckTargets := make(map[string]healthcheck.TCPTarget)
+
+		for index, server := range servers {
 			srvLogger := logger.With().
 				Int(logs.ServerIndex, index).
 				Str("serverAddress", server.Address).Logger()
@@ -77,6 +217,17 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				return nil, err
 			}
 
+			// The health check dials the servers the same way the proxy does, unless it has its own local address.
+			healthCheckDialer := dialer
+			if conf.LoadBalancer.HealthCheck != nil && conf.LoadBalancer.HealthCheck.LocalAddress != "" {
+				healthCheckDialer, err = tcp.BindLocalAddress(dialer, conf.LoadBalancer.HealthCheck.LocalAddress)
+				if err != nil {
+					err = fmt.Errorf("invalid health check local address: %w", err)
+					conf.AddError(err, true)
+					return nil, err
+				}
+			}
+
 			// Handle TerminationDelay deprecated option.
 			if conf.LoadBalancer.ServersTransport == "" && conf.LoadBalancer.TerminationDelay != nil {
 				dialer = &dialerWrapper{
@@ -85,20 +236,81 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				}
 			}
 
-			handler, err := tcp.NewProxy(server.Address, conf.LoadBalancer.ProxyProtocol, dialer)
+			tcpProxy, err := tcp.NewProxy(server.Address, conf.LoadBalancer.ProxyProtocol, conf.LoadBalancer.HalfClose, dialer)
 			if err != nil {
 				srvLogger.Error().Err(err).Msg("Failed to create server")
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			if conf.LoadBalancer.IdleTimeout > 0 {
+				tcpProxy.SetIdleTimeout(time.Duration(conf.LoadBalancer.IdleTimeout), m.idleReapedConns)
+			}
+
+			if conf.LoadBalancer.NoDelay != nil {
+				tcpProxy.SetNoDelay(*conf.LoadBalancer.NoDelay)
+			}
+
+			if conf.LoadBalancer.MaxConnectionDuration > 0 {
+				tcpProxy.SetMaxConnectionDuration(time.Duration(conf.LoadBalancer.MaxConnectionDuration))
+			}
+
+			if prefixFrame != nil {
+				tcpProxy.SetPrefixFrame(prefixFrame)
+			}
+
+			if m.dialDurations != nil {
+				tcpProxy.SetDialDurationHistogram(m.dialDurations.With("service", serviceQualifiedName, "server", server.Address))
+			}
+
+			var handler tcp.Handler = tcpProxy
+			if failover != nil {
+				handler = failover.AddServer(tcpProxy)
+			}
+
+			if m.flowRecorder != nil {
+				handler = tcp.NewFlowHandler(m.flowRecorder, handler)
+			}
+
+			if conf.LoadBalancer.Audit {
+				handler = tcp.NewAuditHandler(serviceQualifiedName, server.Address, handler)
+			}
+
+			// The connection limit wraps the server as a whole, for the load balancer to skip the server at its limit.
+			if conf.LoadBalancer.MaxConnectionsPerServer > 0 {
+				var serverConns gokitmetrics.Gauge
+				if m.serverConns != nil {
+					serverConns = m.serverConns.With("service", serviceQualifiedName, "server", server.Address)
+				}
+				handler = tcp.NewConnLimitedServer(conf.LoadBalancer.MaxConnectionsPerServer, serverConns, handler)
+			}
+
+			if conf.LoadBalancer.HealthCheck == nil {
+				addServer("", server.Address, handler)
+			} else {
+				serverName := fmt.Sprintf("%s-%d", serviceQualifiedName, index)
+				addServer(serverName, server.Address, handler)
+				healthCheckTargets[serverName] = healthcheck.TCPTarget{Address: server.Address, Dialer: healthCheckDialer}
+			}
 			logger.Debug().Msg("Creating TCP server")
 		}
 
+		if conf.LoadBalancer.HealthCheck != nil && len(healthCheckTargets) > 0 {
+			m.healthCheckers[serviceQualifiedName] = append(m.healthCheckers[serviceQualifiedName], healthcheck.NewServiceTCPHealthChecker(
+				ctx,
+				conf.LoadBalancer.HealthCheck,
+				loadBalancer,
+				healthCheckTargets,
+			))
+		}
+
+		if m.admission != nil {
+			return m.admission.Handler(conf.LoadBalancer.Priority, loadBalancer), nil
+		}
+
 		return loadBalancer, nil
 
 	case conf.Weighted != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		loadBalancer := tcp.NewWRRLoadBalancer(nil, "")
 
 		for _, service := range shuffle(conf.Weighted.Services, m.rand) {
 			handler, err := m.BuildTCP(ctx, service.Name)
@@ -112,6 +324,33 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 		return loadBalancer, nil
 
+	case conf.Mirroring != nil:
+		if conf.Mirroring.Percent < 0 || conf.Mirroring.Percent > 100 {
+			err := fmt.Errorf("invalid mirroring percent %d: must be between 0 and 100", conf.Mirroring.Percent)
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		if conf.Mirroring.MaxCompareSize <= 0 {
+			err := fmt.Errorf("invalid mirroring maxCompareSize %d: must be positive", conf.Mirroring.MaxCompareSize)
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		handler, err := m.BuildTCP(ctx, conf.Mirroring.Service)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to build TCP handler")
+			return nil, err
+		}
+
+		mirror, err := m.BuildTCP(ctx, conf.Mirroring.Mirror)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to build TCP mirror handler")
+			return nil, err
+		}
+
+		return tcp.NewMirroring(serviceQualifiedName, handler, mirror, conf.Mirroring.Percent, int(conf.Mirroring.MaxCompareSize), m.mirrorComparisons), nil
+
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
@@ -119,6 +358,28 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	}
 }
 
+// LaunchHealthCheck launches the health checks.
+func (m *Manager) LaunchHealthCheck(ctx context.Context) {
+	for serviceName, hcs := range m.healthCheckers {
+		logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+		for _, hc := range hcs {
+			go hc.Launch(logger.WithContext(ctx))
+		}
+	}
+}
+
+// countServiceTypes returns the number of types defined by the given service.
+func countServiceTypes(service *dynamic.TCPService) int {
+	var count int
+	for _, defined := range []bool{service.LoadBalancer != nil, service.Weighted != nil, service.Mirroring != nil} {
+		if defined {
+			count++
+		}
+	}
+
+	return count
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
@@ -136,3 +397,27 @@ type dialerWrapper struct {
 func (d dialerWrapper) TerminationDelay() time.Duration {
 	return d.terminationDelay
 }
+
+func (d dialerWrapper) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if contextDialer, ok := d.Dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, network, address)
+	}
+
+	return d.Dialer.Dial(network, address)
+}
+
+// validateDialFailover checks that the connect timeout of the load balancer, if any, leaves room for at least one dial attempt.
+func validateDialFailover(lb *dynamic.TCPServersLoadBalancer) error {
+	switch {
+	case lb.PerAttemptDialTimeout < 0:
+		return fmt.Errorf("perAttemptDialTimeout cannot be negative: %s", time.Duration(lb.PerAttemptDialTimeout))
+	case lb.ConnectTimeout < 0:
+		return fmt.Errorf("connectTimeout cannot be negative: %s", time.Duration(lb.ConnectTimeout))
+	case lb.ConnectTimeout > 0 && lb.PerAttemptDialTimeout == 0:
+		return errors.New("connectTimeout requires perAttemptDialTimeout to be set")
+	case lb.ConnectTimeout > 0 && lb.ConnectTimeout < lb.PerAttemptDialTimeout:
+		return fmt.Errorf("connectTimeout (%s) cannot be lower than perAttemptDialTimeout (%s)", time.Duration(lb.ConnectTimeout), time.Duration(lb.PerAttemptDialTimeout))
+	default:
+		return nil
+	}
+}
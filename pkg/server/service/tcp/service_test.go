@@ -2,12 +2,18 @@ package tcp
 
 import (
 	"context"
+	"net"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -234,6 +240,267 @@ func TestManager_BuildTCP(t *testing.T) {
 			providerName:  "provider-1",
 			expectedError: "TCP dialer not found myServersTransport@provider-1",
 		},
+		{
+			desc:        "consistent hashing strategy",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: dynamic.TCPBalancerStrategyConsistentHashing,
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "unknown strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "leastConn",
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `unknown load balancing strategy "leastConn"`,
+		},
+		{
+			desc:        "priority out of bounds",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Priority: 11,
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "invalid priority 11: must be between -10 and 10",
+		},
+		{
+			desc:        "max connections per server",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							MaxConnectionsPerServer: 2,
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "negative max connections per server",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							MaxConnectionsPerServer: -1,
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "invalid maxConnectionsPerServer -1: must be positive",
+		},
+		{
+			desc:        "dial failover",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							PerAttemptDialTimeout: ptypes.Duration(time.Second),
+							ConnectTimeout:        ptypes.Duration(3 * time.Second),
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "connect timeout lower than the per-attempt dial timeout",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							PerAttemptDialTimeout: ptypes.Duration(time.Second),
+							ConnectTimeout:        ptypes.Duration(500 * time.Millisecond),
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "connectTimeout (500ms) cannot be lower than perAttemptDialTimeout (1s)",
+		},
+		{
+			desc:        "connect timeout without per-attempt dial timeout",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							ConnectTimeout: ptypes.Duration(time.Second),
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "connectTimeout requires perAttemptDialTimeout to be set",
+		},
+		{
+			desc:        "health check with an unassigned local address",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							HealthCheck: &dynamic.TCPServerHealthCheck{
+								// TEST-NET-1 address, never assigned to the host.
+								LocalAddress: "192.0.2.1:4000",
+							},
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "invalid health check local address: 192.0.2.1 is not assigned to any interface of the host",
+		},
+		{
+			desc:        "health check with a local address",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							HealthCheck: &dynamic.TCPServerHealthCheck{
+								LocalAddress: "127.0.0.1",
+							},
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "mirroring",
+			serviceName: "mirroring",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"mirroring@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service:        "primary",
+							Mirror:         "candidate",
+							Percent:        10,
+							MaxCompareSize: 1024,
+						},
+					},
+				},
+				"primary@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+						},
+					},
+				},
+				"candidate@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.13:80"}},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "mirroring with an invalid percent",
+			serviceName: "mirroring",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"mirroring@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service:        "primary",
+							Mirror:         "candidate",
+							Percent:        101,
+							MaxCompareSize: 1024,
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "invalid mirroring percent 101: must be between 0 and 100",
+		},
 	}
 
 	for _, test := range testCases {
@@ -266,3 +533,84 @@ func TestManager_BuildTCP(t *testing.T) {
 		})
 	}
 }
+
+// dialsHistogram counts the observed dial durations by labels.
+type dialsHistogram struct {
+	mu      *sync.Mutex
+	samples map[string]int
+	labels  []string
+}
+
+func (h dialsHistogram) With(labelValues ...string) metrics.ScalableHistogram {
+	h.labels = append(append([]string{}, h.labels...), labelValues...)
+	return h
+}
+
+func (h dialsHistogram) Observe(_ float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.samples[strings.Join(h.labels, ",")]++
+}
+
+func (h dialsHistogram) ObserveFromStart(_ time.Time) {
+	h.Observe(0)
+}
+
+func TestManager_DialDurationHistogram(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	frontListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = frontListener.Close() })
+
+	dialerManager := tcp.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
+
+	manager := NewManager(&runtime.Configuration{
+		TCPServices: map[string]*runtime.TCPServiceInfo{
+			"test@file": {
+				TCPService: &dynamic.TCPService{
+					LoadBalancer: &dynamic.TCPServersLoadBalancer{
+						Servers: []dynamic.TCPServer{{Address: backendListener.Addr().String()}},
+					},
+				},
+			},
+		},
+	}, dialerManager)
+
+	histogram := dialsHistogram{mu: &sync.Mutex{}, samples: make(map[string]int)}
+	manager.SetDialDurationHistogram(histogram)
+
+	handler, err := manager.BuildTCP(context.Background(), "test@file")
+	require.NoError(t, err)
+
+	for range 3 {
+		client, err := net.Dial("tcp", frontListener.Addr().String())
+		require.NoError(t, err)
+
+		conn, err := frontListener.Accept()
+		require.NoError(t, err)
+
+		// The backend closing the connection right away, the proxy returns once the client closes its own.
+		require.NoError(t, client.Close())
+		handler.ServeTCP(conn.(*net.TCPConn))
+	}
+
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	assert.Equal(t, map[string]int{"service,test@file,server," + backendListener.Addr().String(): 3}, histogram.samples)
+}
@@ -0,0 +1,107 @@
+package tcp
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+)
+
+// Bounds of the priorities of the connections admitted by the Admission.
+const (
+	MinAdmissionPriority = -10
+	MaxAdmissionPriority = 10
+)
+
+// Admission admits the connections to the TCP services up to a maximum number of connections.
+// Above the pressure threshold, the room left up to the maximum is shared by priority:
+// the connections of a priority are admitted up to a limit which increases with the priority,
+// so that as the number of connections grows, the lowest priority connections are rejected first,
+// the connections of the highest priority being admitted up to the maximum.
+type Admission struct {
+	maxConnections      int
+	pressureConnections int
+
+	// conns reports the number of admitted connections, and rejects counts the rejected ones, by priority.
+	conns   gokitmetrics.Gauge
+	rejects gokitmetrics.Counter
+
+	mu          sync.Mutex
+	connections int
+	byPriority  map[int]int
+}
+
+// NewAdmission creates a new Admission admitting up to maxConnections connections,
+// the connections being admitted by priority above the pressureThreshold ratio of maxConnections.
+func NewAdmission(maxConnections int, pressureThreshold float64, conns gokitmetrics.Gauge, rejects gokitmetrics.Counter) *Admission {
+	return &Admission{
+		maxConnections:      maxConnections,
+		pressureConnections: int(float64(maxConnections) * pressureThreshold),
+		conns:               conns,
+		rejects:             rejects,
+		byPriority:          make(map[int]int),
+	}
+}
+
+// ValidateAdmissionPriority returns an error if the given priority is out of the admission priorities bounds.
+func ValidateAdmissionPriority(priority int) error {
+	if priority < MinAdmissionPriority || priority > MaxAdmissionPriority {
+		return fmt.Errorf("invalid priority %d: must be between %d and %d", priority, MinAdmissionPriority, MaxAdmissionPriority)
+	}
+
+	return nil
+}
+
+// Handler returns a handler admitting the connections with the given priority before forwarding them to next.
+// The rejected connections are closed, with the limitExceeded close reason.
+func (a *Admission) Handler(priority int, next Handler) Handler {
+	return HandlerFunc(func(conn WriteCloser) {
+		if !a.admit(priority) {
+			log.Debug().Int("priority", priority).Msg("Connection rejected by the TCP admission under connection pressure")
+			CloseWithReason(conn, CloseReasonLimitExceeded)
+			return
+		}
+		defer a.release(priority)
+
+		next.ServeTCP(conn)
+	})
+}
+
+// limit returns the number of connections up to which the connections of the given priority are admitted.
+func (a *Admission) limit(priority int) int {
+	levels := MaxAdmissionPriority - MinAdmissionPriority + 1
+	level := priority - MinAdmissionPriority + 1
+
+	return a.pressureConnections + (a.maxConnections-a.pressureConnections)*level/levels
+}
+
+func (a *Admission) admit(priority int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.connections >= a.limit(priority) {
+		a.rejects.With("priority", strconv.Itoa(priority)).Add(1)
+		return false
+	}
+
+	a.connections++
+	a.byPriority[priority]++
+	a.conns.With("priority", strconv.Itoa(priority)).Set(float64(a.byPriority[priority]))
+
+	return true
+}
+
+func (a *Admission) release(priority int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.connections--
+	a.byPriority[priority]--
+	a.conns.With("priority", strconv.Itoa(priority)).Set(float64(a.byPriority[priority]))
+
+	if a.byPriority[priority] == 0 {
+		delete(a.byPriority, priority)
+	}
+}
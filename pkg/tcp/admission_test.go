@@ -0,0 +1,83 @@
+package tcp
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func TestAdmission_limit(t *testing.T) {
+	admission := NewAdmission(10, 0.5, generic.NewGauge("conns"), &testhelpers.CollectingCounter{})
+
+	assert.Equal(t, 5, admission.limit(MinAdmissionPriority))
+	assert.Equal(t, 7, admission.limit(0))
+	assert.Equal(t, 10, admission.limit(MaxAdmissionPriority))
+}
+
+func TestAdmission_shedsLowPriorityFirst(t *testing.T) {
+	rejects := &testhelpers.CollectingCounter{}
+	admission := NewAdmission(10, 0.5, generic.NewGauge("conns"), rejects)
+
+	// The admitted connections are held until the end of the test, to keep the connection pressure.
+	hold := make(chan struct{})
+	var lowAdmitted, highAdmitted atomic.Int64
+	handler := func(admitted *atomic.Int64) Handler {
+		return HandlerFunc(func(conn WriteCloser) {
+			admitted.Add(1)
+			<-hold
+			_ = conn.Close()
+		})
+	}
+
+	low := admission.Handler(MinAdmissionPriority, handler(&lowAdmitted))
+	high := admission.Handler(MaxAdmissionPriority, handler(&highAdmitted))
+
+	// The connections of both priorities compete concurrently for the admission.
+	var served sync.WaitGroup
+	for i := range 40 {
+		handler := low
+		if i%2 == 0 {
+			handler = high
+		}
+
+		server, client := net.Pipe()
+		t.Cleanup(func() { _ = client.Close() })
+
+		served.Add(1)
+		go func() {
+			defer served.Done()
+
+			handler.ServeTCP(&pipeWriteCloser{Conn: server})
+		}()
+	}
+
+	assert.Eventually(t, func() bool {
+		admission.mu.Lock()
+		defer admission.mu.Unlock()
+
+		return lowAdmitted.Load()+highAdmitted.Load()+int64(rejects.CounterValue) == 40
+	}, 5*time.Second, 10*time.Millisecond)
+
+	t.Logf("Admitted connections: %d low priority, %d high priority", lowAdmitted.Load(), highAdmitted.Load())
+
+	// The low priority connections are admitted up to the pressure threshold only,
+	// while the high priority ones are admitted up to the maximum.
+	assert.LessOrEqual(t, lowAdmitted.Load(), int64(5))
+	assert.GreaterOrEqual(t, highAdmitted.Load(), int64(5))
+	assert.Equal(t, int64(10), lowAdmitted.Load()+highAdmitted.Load())
+
+	close(hold)
+	served.Wait()
+
+	admission.mu.Lock()
+	defer admission.mu.Unlock()
+
+	assert.Zero(t, admission.connections)
+	assert.Empty(t, admission.byPriority)
+}
@@ -0,0 +1,124 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+)
+
+// RouterAttribute is the attribute holding the name of the TCP router the connection is routed by.
+const RouterAttribute = "router"
+
+// ServiceAttribute is the attribute holding the name of the TCP service the connection is routed to.
+const ServiceAttribute = "service"
+
+// SNIAttribute is the attribute holding the SNI of the TLS connection, as sent by the client.
+const SNIAttribute = "sni"
+
+// ConnAttributes holds the attributes of a connection.
+// The attributes live as long as the connection they are attached to,
+// and are safe for concurrent use by the handlers serving the connection.
+type ConnAttributes struct {
+	mu     sync.RWMutex
+	values map[string]string
+
+	// closeReasons writes the close reason frames of the service of the connection, if any.
+	closeReasons *closeReasonWriter
+}
+
+// Set sets the value of the given attribute.
+func (a *ConnAttributes) Set(key, value string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.values == nil {
+		a.values = make(map[string]string)
+	}
+
+	a.values[key] = value
+}
+
+// Get returns the value of the given attribute, and whether it is set.
+func (a *ConnAttributes) Get(key string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	value, ok := a.values[key]
+	return value, ok
+}
+
+// snapshot returns a copy of all the attributes.
+func (a *ConnAttributes) snapshot() map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	values := make(map[string]string, len(a.values))
+	for key, value := range a.values {
+		values[key] = value
+	}
+
+	return values
+}
+
+// attributesCarrier is implemented by the connections carrying attributes.
+// The connection wrappers forward it to keep the attributes of the wrapped connection.
+type attributesCarrier interface {
+	Attributes() *ConnAttributes
+}
+
+// GetConnAttributes returns the attributes of the given connection, or nil if it does not carry any.
+func GetConnAttributes(conn WriteCloser) *ConnAttributes {
+	carrier, ok := conn.(attributesCarrier)
+	if !ok {
+		return nil
+	}
+
+	return carrier.Attributes()
+}
+
+// WithConnAttributes returns the given connection, carrying an empty set of attributes if it does not carry any.
+func WithConnAttributes(conn WriteCloser) WriteCloser {
+	if GetConnAttributes(conn) != nil {
+		return conn
+	}
+
+	return &attributesConn{WriteCloser: conn, attributes: &ConnAttributes{}}
+}
+
+type attributesConn struct {
+	WriteCloser
+
+	attributes *ConnAttributes
+}
+
+// Attributes returns the attributes of the connection.
+func (c *attributesConn) Attributes() *ConnAttributes {
+	return c.attributes
+}
+
+// NetConn returns the wrapped connection.
+func (c *attributesConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// tlsAttributesConn is a TLS connection carrying the attributes of the underlying connection.
+type tlsAttributesConn struct {
+	*tls.Conn
+
+	attributes *ConnAttributes
+}
+
+// Attributes returns the attributes of the connection.
+func (c *tlsAttributesConn) Attributes() *ConnAttributes {
+	return c.attributes
+}
+
+// withTLSAttributes returns the given TLS connection, carrying the attributes of the underlying connection if any.
+func withTLSAttributes(tlsConn *tls.Conn, conn WriteCloser) WriteCloser {
+	attributes := GetConnAttributes(conn)
+	if attributes == nil {
+		return tlsConn
+	}
+
+	return &tlsAttributesConn{Conn: tlsConn, attributes: attributes}
+}
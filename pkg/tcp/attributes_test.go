@@ -0,0 +1,73 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+func TestWithConnAttributes(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+
+	conn := &pipeWriteCloser{Conn: server}
+	assert.Nil(t, GetConnAttributes(conn))
+
+	withAttributes := WithConnAttributes(conn)
+	attributes := GetConnAttributes(withAttributes)
+	require.NotNil(t, attributes)
+
+	attributes.Set("class", "bulk")
+
+	// The attributes are kept when the connection already carries some.
+	assert.Same(t, withAttributes, WithConnAttributes(withAttributes))
+
+	value, ok := GetConnAttributes(withAttributes).Get("class")
+	assert.True(t, ok)
+	assert.Equal(t, "bulk", value)
+
+	_, ok = attributes.Get("unknown")
+	assert.False(t, ok)
+}
+
+func TestTLSHandler_attributes(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+
+	conn := WithConnAttributes(&pipeWriteCloser{Conn: server})
+	GetConnAttributes(conn).Set("class", "bulk")
+
+	values := make(chan string, 1)
+	handler := &TLSHandler{
+		Config: &tls.Config{Certificates: []tls.Certificate{*cert}},
+		Next: HandlerFunc(func(conn WriteCloser) {
+			value, _ := GetConnAttributes(conn).Get("class")
+			values <- value
+		}),
+	}
+
+	handler.ServeTCP(conn)
+
+	assert.Equal(t, "bulk", <-values)
+}
+
+type pipeWriteCloser struct {
+	net.Conn
+}
+
+func (p *pipeWriteCloser) CloseWrite() error {
+	return p.Close()
+}
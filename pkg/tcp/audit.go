@@ -0,0 +1,47 @@
+package tcp
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+)
+
+// AuditHandler logs an audit entry for each connection forwarded to a server of a service, before forwarding it.
+// The entry is logged before connecting to the server, so that it is also logged for the connections which then fail.
+type AuditHandler struct {
+	serviceName string
+	address     string
+	next        Handler
+	logger      zerolog.Logger
+}
+
+// NewAuditHandler creates a new AuditHandler of the server of the given service, at the given address.
+func NewAuditHandler(serviceName, address string, next Handler) *AuditHandler {
+	return &AuditHandler{
+		serviceName: serviceName,
+		address:     address,
+		next:        next,
+		logger:      log.Logger,
+	}
+}
+
+// ServeTCP logs the audit entry of the connection, and forwards it to the server.
+func (h *AuditHandler) ServeTCP(conn WriteCloser) {
+	var router, sni string
+	if attributes := GetConnAttributes(conn); attributes != nil {
+		router, _ = attributes.Get(RouterAttribute)
+		sni, _ = attributes.Get(SNIAttribute)
+	}
+
+	// The audit entries have no level, so that they are logged regardless of the log level.
+	h.logger.Log().
+		Bool("audit", true).
+		Str("remoteAddr", conn.RemoteAddr().String()).
+		Str("sni", sni).
+		Str(logs.RouterName, router).
+		Str(logs.ServiceName, h.serviceName).
+		Str("serverAddress", h.address).
+		Msg("Forwarding TCP connection to the server")
+
+	h.next.ServeTCP(conn)
+}
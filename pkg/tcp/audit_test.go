@@ -0,0 +1,52 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler(t *testing.T) {
+	// The address of a closed listener, on which the connections are refused.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	proxy, err := NewProxy(address, nil, false, tcpDialer{&net.Dialer{}, 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	handler := NewAuditHandler("db@kubernetescrd", address, proxy)
+	// The audit entries are logged even at the default ERROR log level.
+	handler.logger = zerolog.New(&logs).Level(zerolog.ErrorLevel)
+
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+
+	conn := WithConnAttributes(&pipeWriteCloser{Conn: server})
+	GetConnAttributes(conn).Set(RouterAttribute, "db-router@kubernetescrd")
+	GetConnAttributes(conn).Set(SNIAttribute, "db.example.com")
+
+	// The connection to the server fails, but the audit entry is logged anyway.
+	handler.ServeTCP(conn)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+
+	assert.Equal(t, map[string]any{
+		"audit":         true,
+		"remoteAddr":    "pipe",
+		"sni":           "db.example.com",
+		"routerName":    "db-router@kubernetescrd",
+		"serviceName":   "db@kubernetescrd",
+		"serverAddress": address,
+		"message":       "Forwarding TCP connection to the server",
+	}, entry)
+}
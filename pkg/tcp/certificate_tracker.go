@@ -0,0 +1,151 @@
+package tcp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CertificateTracker tracks the TLS connections of a router along with the certificate they were served,
+// to close them when the certificate expires, or when it is rotated.
+// It is kept across the configuration reloads, so that the rotations can be detected.
+type CertificateTracker struct {
+	mu    sync.Mutex
+	conns map[*certificateConn]struct{}
+}
+
+// NewCertificateTracker creates a new CertificateTracker.
+func NewCertificateTracker() *CertificateTracker {
+	return &CertificateTracker{conns: make(map[*certificateConn]struct{})}
+}
+
+type certificateConn struct {
+	// conn is the connection underlying the TLS one,
+	// which is closed directly not to block on sending the TLS close notification to an unresponsive client.
+	conn WriteCloser
+
+	// hello and certificate are the ClientHello of the connection, and the certificate served in response.
+	hello       *tls.ClientHelloInfo
+	certificate *tls.Certificate
+
+	expiry *time.Timer
+}
+
+// Update closes the tracked connections whose certificate is no longer the one served by the given TLS configuration.
+func (c *CertificateTracker) Update(config *tls.Config) {
+	var rotated []*certificateConn
+
+	c.mu.Lock()
+	for tracked := range c.conns {
+		certificate, err := getCertificate(config, tracked.hello)
+		if err == nil && sameCertificate(certificate, tracked.certificate) {
+			continue
+		}
+
+		c.untrack(tracked)
+		rotated = append(rotated, tracked)
+	}
+	c.mu.Unlock()
+
+	for _, tracked := range rotated {
+		log.Debug().
+			Str("remoteAddr", tracked.conn.RemoteAddr().String()).
+			Str("serverName", tracked.hello.ServerName).
+			Msg("Closing TLS connection as its certificate has been rotated")
+
+		_ = tracked.conn.Close()
+	}
+}
+
+// serverConfig returns a TLS configuration recording the ClientHello, and the certificate served in response, of the given connection.
+func (c *CertificateTracker) serverConfig(config *tls.Config, tracked *certificateConn) *tls.Config {
+	serverConfig := config.Clone()
+	serverConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		certificate, err := getCertificate(config, hello)
+
+		tracked.hello = hello
+		tracked.certificate = certificate
+		return certificate, err
+	}
+
+	return serverConfig
+}
+
+// track starts tracking the given connection once handshaked, and closes it when its certificate expires.
+func (c *CertificateTracker) track(tracked *certificateConn) error {
+	if tracked.certificate == nil || len(tracked.certificate.Certificate) == 0 {
+		return errors.New("no certificate served")
+	}
+
+	leaf := tracked.certificate.Leaf
+	if leaf == nil {
+		var err error
+		leaf, err = x509.ParseCertificate(tracked.certificate.Certificate[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conns[tracked] = struct{}{}
+
+	tracked.expiry = time.AfterFunc(time.Until(leaf.NotAfter), func() {
+		log.Debug().
+			Str("remoteAddr", tracked.conn.RemoteAddr().String()).
+			Str("serverName", tracked.hello.ServerName).
+			Msg("Closing TLS connection as its certificate has expired")
+
+		c.release(tracked)
+		_ = tracked.conn.Close()
+	})
+
+	return nil
+}
+
+// release stops tracking the given connection.
+func (c *CertificateTracker) release(tracked *certificateConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.untrack(tracked)
+}
+
+func (c *CertificateTracker) untrack(tracked *certificateConn) {
+	if tracked.expiry != nil {
+		tracked.expiry.Stop()
+	}
+
+	delete(c.conns, tracked)
+}
+
+// getCertificate returns the certificate served by the given configuration in response to the given ClientHello,
+// following the crypto/tls selection.
+func getCertificate(config *tls.Config, hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if config.GetCertificate != nil {
+		certificate, err := config.GetCertificate(hello)
+		if certificate != nil || err != nil {
+			return certificate, err
+		}
+	}
+
+	if len(config.Certificates) == 0 {
+		return nil, errors.New("no certificates configured")
+	}
+
+	return &config.Certificates[0], nil
+}
+
+func sameCertificate(a, b *tls.Certificate) bool {
+	if a == nil || b == nil || len(a.Certificate) == 0 || len(b.Certificate) == 0 {
+		return false
+	}
+
+	return bytes.Equal(a.Certificate[0], b.Certificate[0])
+}
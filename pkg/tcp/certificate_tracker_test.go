@@ -0,0 +1,118 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+func newCertificate(t *testing.T, expiration time.Time) *tls.Certificate {
+	t.Helper()
+
+	certPEM, keyPEM, err := generate.KeyPair("foo.com", expiration)
+	require.NoError(t, err)
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	return &cert
+}
+
+// serveTrackedConn serves a connection through a TLSHandler tracking its certificate,
+// and returns the client side of the connection once handshaked, along with a channel closed when the connection is closed.
+func serveTrackedConn(t *testing.T, tracker *CertificateTracker, config *tls.Config) (*tls.Conn, chan struct{}) {
+	t.Helper()
+
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+
+	closed := make(chan struct{})
+	handler := &TLSHandler{
+		Config:             config,
+		CertificateTracker: tracker,
+		Next: HandlerFunc(func(conn WriteCloser) {
+			defer close(closed)
+
+			// Echoes until the connection is closed.
+			buf := make([]byte, 64)
+			for {
+				n, err := conn.Read(buf)
+				if err != nil {
+					return
+				}
+				if _, err = conn.Write(buf[:n]); err != nil {
+					return
+				}
+			}
+		}),
+	}
+
+	go handler.ServeTCP(&pipeWriteCloser{Conn: server})
+
+	tlsClient := tls.Client(client, &tls.Config{InsecureSkipVerify: true, ServerName: "foo.com"})
+	require.NoError(t, tlsClient.Handshake())
+
+	return tlsClient, closed
+}
+
+func TestCertificateTracker_rotation(t *testing.T) {
+	var current atomic.Pointer[tls.Certificate]
+	current.Store(newCertificate(t, time.Now().Add(time.Hour)))
+
+	config := &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return current.Load(), nil
+		},
+	}
+
+	tracker := NewCertificateTracker()
+
+	client, closed := serveTrackedConn(t, tracker, config)
+
+	// The connection is kept while the certificate does not change.
+	tracker.Update(config)
+
+	_, err := client.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = client.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	current.Store(newCertificate(t, time.Now().Add(time.Hour)))
+	tracker.Update(config)
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("connection not closed on certificate rotation")
+	}
+
+	// The handshakes after the rotation are served the new certificate.
+	client, _ = serveTrackedConn(t, tracker, config)
+	assert.Equal(t, current.Load().Certificate[0], client.ConnectionState().PeerCertificates[0].Raw)
+}
+
+func TestCertificateTracker_expiry(t *testing.T) {
+	config := &tls.Config{
+		Certificates: []tls.Certificate{*newCertificate(t, time.Now().Add(500*time.Millisecond))},
+	}
+
+	_, closed := serveTrackedConn(t, NewCertificateTracker(), config)
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection not closed on certificate expiry")
+	}
+}
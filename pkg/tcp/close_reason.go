@@ -0,0 +1,119 @@
+package tcp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CloseReason is the reason for which Traefik closes a client connection.
+type CloseReason string
+
+// Close reasons.
+const (
+	// CloseReasonLimitExceeded is the reason of the connections rejected by a connection limit.
+	CloseReasonLimitExceeded CloseReason = "limitExceeded"
+	// CloseReasonBackendDown is the reason of the connections which cannot be forwarded to any server of their service.
+	CloseReasonBackendDown CloseReason = "backendDown"
+	// CloseReasonDraining is the reason of the connections closed as their router is removed, or their entry point is shut down.
+	CloseReasonDraining CloseReason = "draining"
+)
+
+// closeReasonWriteTimeout bounds the time spent writing a close reason frame to a client,
+// for the connection to be closed even if the client does not read it.
+const closeReasonWriteTimeout = time.Second
+
+// CloseReasonEncoder encodes the close reasons into the protocol-specific frames written to the clients before closing their connections.
+type CloseReasonEncoder interface {
+	// Encode returns the frame of the given close reason, or nil for the connection to be closed without frame.
+	Encode(reason CloseReason) []byte
+}
+
+// CloseReasonFrames is a CloseReasonEncoder writing a static frame by close reason.
+type CloseReasonFrames map[CloseReason][]byte
+
+// NewCloseReasonFrames creates the CloseReasonFrames from the given frames, indexed by close reason.
+func NewCloseReasonFrames(frames map[string]string) (CloseReasonFrames, error) {
+	closeReasonFrames := make(CloseReasonFrames, len(frames))
+	for reason, frame := range frames {
+		switch CloseReason(reason) {
+		case CloseReasonLimitExceeded, CloseReasonBackendDown, CloseReasonDraining:
+			closeReasonFrames[CloseReason(reason)] = []byte(frame)
+		default:
+			return nil, fmt.Errorf("unknown close reason %q: must be %s, %s, or %s", reason, CloseReasonLimitExceeded, CloseReasonBackendDown, CloseReasonDraining)
+		}
+	}
+
+	return closeReasonFrames, nil
+}
+
+// Encode returns the frame of the given close reason, if any.
+func (f CloseReasonFrames) Encode(reason CloseReason) []byte {
+	return f[reason]
+}
+
+// closeReasonWriter writes the close reason frame of a connection, at most once.
+type closeReasonWriter struct {
+	encoder CloseReasonEncoder
+	conn    WriteCloser
+	once    sync.Once
+}
+
+// SetCloseReasonEncoder sets the encoder of the close reasons of the connection,
+// whose frames are written to the given connection, i.e. the connection as seen by the service, after the TLS termination if any.
+func (a *ConnAttributes) SetCloseReasonEncoder(encoder CloseReasonEncoder, conn WriteCloser) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closeReasons = &closeReasonWriter{encoder: encoder, conn: conn}
+}
+
+// WriteCloseReason writes the frame of the given close reason to the client, when the connection has a close reason encoder.
+// Only the first close reason of a connection is written.
+func (a *ConnAttributes) WriteCloseReason(reason CloseReason) error {
+	a.mu.RLock()
+	closeReasons := a.closeReasons
+	a.mu.RUnlock()
+
+	if closeReasons == nil {
+		return nil
+	}
+
+	frame := closeReasons.encoder.Encode(reason)
+	if len(frame) == 0 {
+		return nil
+	}
+
+	var err error
+	closeReasons.once.Do(func() {
+		if err = closeReasons.conn.SetWriteDeadline(time.Now().Add(closeReasonWriteTimeout)); err != nil {
+			return
+		}
+
+		_, err = closeReasons.conn.Write(frame)
+	})
+
+	return err
+}
+
+// CloseWithReason closes the given connection,
+// after writing the frame of the given close reason to the client when the connection has a close reason encoder.
+func CloseWithReason(conn WriteCloser, reason CloseReason) {
+	writeCloseReason(conn, reason)
+
+	_ = conn.Close()
+}
+
+// writeCloseReason writes the frame of the given close reason to the client of the given connection, if any.
+func writeCloseReason(conn WriteCloser, reason CloseReason) {
+	attributes := GetConnAttributes(conn)
+	if attributes == nil {
+		return
+	}
+
+	if err := attributes.WriteCloseReason(reason); err != nil {
+		log.Debug().Err(err).Str("reason", string(reason)).Msg("Error while writing the close reason frame")
+	}
+}
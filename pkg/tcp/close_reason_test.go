@@ -0,0 +1,95 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseWithReason(t *testing.T) {
+	frames, err := NewCloseReasonFrames(map[string]string{
+		"limitExceeded": "ERR limit exceeded\r\n",
+		"backendDown":   "ERR backend down\r\n",
+		"draining":      "ERR draining\r\n",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		encoder  CloseReasonEncoder
+		reason   CloseReason
+		expected string
+	}{
+		{
+			desc:     "limit exceeded",
+			encoder:  frames,
+			reason:   CloseReasonLimitExceeded,
+			expected: "ERR limit exceeded\r\n",
+		},
+		{
+			desc:     "backend down",
+			encoder:  frames,
+			reason:   CloseReasonBackendDown,
+			expected: "ERR backend down\r\n",
+		},
+		{
+			desc:     "draining",
+			encoder:  frames,
+			reason:   CloseReasonDraining,
+			expected: "ERR draining\r\n",
+		},
+		{
+			desc:    "reason without frame",
+			encoder: CloseReasonFrames{CloseReasonDraining: []byte("ERR draining\r\n")},
+			reason:  CloseReasonBackendDown,
+		},
+		{
+			desc:   "without encoder",
+			reason: CloseReasonBackendDown,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server, client := net.Pipe()
+			t.Cleanup(func() { _ = client.Close() })
+
+			conn := WithConnAttributes(&pipeWriteCloser{Conn: server})
+			if test.encoder != nil {
+				GetConnAttributes(conn).SetCloseReasonEncoder(test.encoder, conn)
+			}
+
+			go CloseWithReason(conn, test.reason)
+
+			// The frame, if any, is written before the connection is closed.
+			data, err := io.ReadAll(client)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestCloseWithReason_loadBalancer(t *testing.T) {
+	server, client := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+
+	conn := WithConnAttributes(&pipeWriteCloser{Conn: server})
+	GetConnAttributes(conn).SetCloseReasonEncoder(CloseReasonFrames{CloseReasonBackendDown: []byte("ERR backend down\r\n")}, conn)
+
+	// A load balancer without servers closes the connections as its backend is down.
+	go NewWRRLoadBalancer(nil, "").ServeTCP(conn)
+
+	data, err := io.ReadAll(client)
+	require.NoError(t, err)
+	assert.Equal(t, "ERR backend down\r\n", string(data))
+}
+
+func TestNewCloseReasonFrames_unknownReason(t *testing.T) {
+	_, err := NewCloseReasonFrames(map[string]string{"overloaded": "ERR\r\n"})
+	assert.Error(t, err)
+}
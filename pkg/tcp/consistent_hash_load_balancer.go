@@ -0,0 +1,151 @@
+package tcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// consistentHashReplicas is the number of points of a server on the hash ring,
+// which evens out the share of the clients of each server.
+const consistentHashReplicas = 100
+
+type ringPoint struct {
+	hash   uint64
+	server *server
+}
+
+// ConsistentHashLoadBalancer is a load balancer for TCP services forwarding the connections of a client IP to the same server.
+// The servers are placed on a hash ring from their key,
+// so that adding or removing a server only remaps the clients of this server.
+type ConsistentHashLoadBalancer struct {
+	hashSeed string
+
+	lock sync.Mutex
+	ring []ringPoint
+	// down holds the names of the servers reported as down by the health check.
+	down map[string]struct{}
+}
+
+// NewConsistentHashLoadBalancer creates a new ConsistentHashLoadBalancer.
+// The given hash seed, when not empty, is mixed into the hashes of the servers and of the clients,
+// so that the assignment of the clients to the servers is specific to the seed.
+func NewConsistentHashLoadBalancer(hashSeed string) *ConsistentHashLoadBalancer {
+	return &ConsistentHashLoadBalancer{
+		hashSeed: hashSeed,
+		down:     make(map[string]struct{}),
+	}
+}
+
+// ServeTCP forwards the connection to the server owning the client IP on the hash ring.
+func (b *ConsistentHashLoadBalancer) ServeTCP(conn WriteCloser) {
+	clientIP := conn.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+
+	b.lock.Lock()
+	next, err := b.next(clientIP)
+	b.lock.Unlock()
+
+	if err != nil {
+		log.Error().Err(err).Msg("Error during load balancing")
+		CloseWithReason(conn, closeReasonOf(err))
+		return
+	}
+
+	next.ServeTCP(conn)
+}
+
+// AddServer adds a server to the hash ring, where it is placed from the given key, e.g. its address.
+// The name is used by the health check to report the server status, and can be empty.
+func (b *ConsistentHashLoadBalancer) AddServer(key, name string, serverHandler Handler) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	srv := &server{Handler: serverHandler, name: name, weight: 1}
+	for i := range consistentHashReplicas {
+		b.ring = append(b.ring, ringPoint{hash: ringHash(b.hashSeed, key+"#"+strconv.Itoa(i)), server: srv})
+	}
+
+	sort.Slice(b.ring, func(i, j int) bool {
+		return b.ring[i].hash < b.ring[j].hash
+	})
+}
+
+// SetStatus sets the status (up or down) of a named server.
+func (b *ConsistentHashLoadBalancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	if up {
+		delete(b.down, childName)
+		return
+	}
+
+	b.down[childName] = struct{}{}
+}
+
+// next returns the first server up following the hash of the given client IP on the ring,
+// skipping the servers at their connection limit.
+func (b *ConsistentHashLoadBalancer) next(clientIP string) (Handler, error) {
+	if len(b.ring) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	hash := ringHash(b.hashSeed, clientIP)
+	start := sort.Search(len(b.ring), func(i int) bool {
+		return b.ring[i].hash >= hash
+	})
+
+	var atLimit bool
+	for i := range len(b.ring) {
+		point := b.ring[(start+i)%len(b.ring)]
+		if _, down := b.down[point.server.name]; point.server.name != "" && down {
+			continue
+		}
+
+		if acquireServer(point.server.Handler) {
+			return point.server, nil
+		}
+		atLimit = true
+	}
+
+	if atLimit {
+		return nil, errAllServersAtLimit
+	}
+
+	return nil, errors.New("all servers are down")
+}
+
+func ringHash(seed, key string) uint64 {
+	return binary.BigEndian.Uint64(seededHash(seed, []byte(key))[:8])
+}
+
+// seededHash returns the SHA-256 hash of the given key, or its HMAC-SHA256 keyed by the given seed when not empty.
+func seededHash(seed string, key []byte) []byte {
+	if seed == "" {
+		sum := sha256.Sum256(key)
+		return sum[:]
+	}
+
+	mac := hmac.New(sha256.New, []byte(seed))
+	mac.Write(key)
+
+	return mac.Sum(nil)
+}
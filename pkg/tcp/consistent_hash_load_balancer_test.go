@@ -0,0 +1,136 @@
+package tcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type remoteAddrConn struct {
+	*fakeConn
+	remoteAddr net.Addr
+}
+
+func (c *remoteAddrConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// serverOf returns the name of the server the given client IP is forwarded to.
+func serverOf(t *testing.T, balancer *ConsistentHashLoadBalancer, clientIP string) string {
+	t.Helper()
+
+	conn := &remoteAddrConn{
+		fakeConn:   &fakeConn{writeCall: make(map[string]int)},
+		remoteAddr: &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 40000},
+	}
+	balancer.ServeTCP(conn)
+
+	require.Len(t, conn.writeCall, 1)
+	for name := range conn.writeCall {
+		return name
+	}
+
+	return ""
+}
+
+func newConsistentHashLoadBalancer(hashSeed string, servers ...string) *ConsistentHashLoadBalancer {
+	balancer := NewConsistentHashLoadBalancer(hashSeed)
+	for _, name := range servers {
+		balancer.AddServer(name+":8080", name, HandlerFunc(func(conn WriteCloser) {
+			_, _ = conn.Write([]byte(name))
+		}))
+	}
+
+	return balancer
+}
+
+func TestConsistentHashLoadBalancer(t *testing.T) {
+	servers := []string{"first", "second", "third", "fourth", "fifth"}
+	balancer := newConsistentHashLoadBalancer("", servers...)
+
+	assignments := make(map[string]string)
+	counts := make(map[string]int)
+	for i := range 1000 {
+		clientIP := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+		assignments[clientIP] = serverOf(t, balancer, clientIP)
+		counts[assignments[clientIP]]++
+
+		// The connections of a client IP are always forwarded to the same server.
+		assert.Equal(t, assignments[clientIP], serverOf(t, balancer, clientIP))
+	}
+
+	// The clients are spread across all the servers.
+	assert.Len(t, counts, len(servers))
+
+	// Adding a server does not depend on the servers order, and only remaps the clients to the new server.
+	scaled := newConsistentHashLoadBalancer("", append([]string{"sixth"}, servers...)...)
+
+	var remapped int
+	for clientIP, name := range assignments {
+		newName := serverOf(t, scaled, clientIP)
+		if newName == name {
+			continue
+		}
+
+		assert.Equal(t, "sixth", newName)
+		remapped++
+	}
+
+	// The new server takes about its share of the clients, 1/6th, instead of reshuffling all of them.
+	assert.Positive(t, remapped)
+	assert.Less(t, remapped, 300)
+}
+
+func TestConsistentHashLoadBalancerWithHashSeed(t *testing.T) {
+	servers := []string{"first", "second", "third", "fourth", "fifth"}
+	seeded := newConsistentHashLoadBalancer("foo", servers...)
+	sameSeed := newConsistentHashLoadBalancer("foo", servers...)
+	otherSeed := newConsistentHashLoadBalancer("bar", servers...)
+
+	var remapped int
+	for i := range 1000 {
+		clientIP := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+
+		// The assignment is deterministic for a given seed.
+		name := serverOf(t, seeded, clientIP)
+		assert.Equal(t, name, serverOf(t, sameSeed, clientIP))
+
+		if serverOf(t, otherSeed, clientIP) != name {
+			remapped++
+		}
+	}
+
+	// Another seed reshuffles the clients, about 4/5th of them being forwarded to another server.
+	assert.Greater(t, remapped, 600)
+}
+
+func TestConsistentHashLoadBalancerWithServerStatus(t *testing.T) {
+	balancer := newConsistentHashLoadBalancer("", "first", "second", "third")
+
+	clientIP := "10.0.0.1"
+	name := serverOf(t, balancer, clientIP)
+
+	// The clients of a server reported as down are forwarded to the next server on the ring, and are back once it is up.
+	balancer.SetStatus(context.Background(), name, false)
+	assert.NotEqual(t, name, serverOf(t, balancer, clientIP))
+
+	balancer.SetStatus(context.Background(), name, true)
+	assert.Equal(t, name, serverOf(t, balancer, clientIP))
+
+	for _, s := range []string{"first", "second", "third"} {
+		balancer.SetStatus(context.Background(), s, false)
+	}
+
+	conn := &remoteAddrConn{
+		fakeConn:   &fakeConn{writeCall: make(map[string]int)},
+		remoteAddr: &net.TCPAddr{IP: net.ParseIP(clientIP), Port: 40000},
+	}
+	balancer.ServeTCP(conn)
+
+	assert.Empty(t, conn.writeCall)
+	assert.Equal(t, 1, conn.closeCall)
+}
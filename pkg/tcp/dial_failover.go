@@ -0,0 +1,97 @@
+package tcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DialFailover makes the servers of a load balancer fail over to the next servers when they cannot be dialed.
+// Each dial attempt is bounded by a per-attempt timeout, and all the attempts of a connection by an overall connect timeout,
+// so that the latency of the failover stays bounded whatever the number of servers.
+type DialFailover struct {
+	lock    sync.RWMutex
+	proxies []*Proxy
+
+	perAttemptDialTimeout time.Duration
+	connectTimeout        time.Duration
+}
+
+// NewDialFailover creates a new DialFailover.
+// A zero connectTimeout means that the attempts are only bounded by the per-attempt timeout.
+func NewDialFailover(perAttemptDialTimeout, connectTimeout time.Duration) *DialFailover {
+	return &DialFailover{
+		perAttemptDialTimeout: perAttemptDialTimeout,
+		connectTimeout:        connectTimeout,
+	}
+}
+
+// AddServer adds a server, and returns the handler to add to the load balancer in its place,
+// which forwards the connections to the server, or to the next added servers when it cannot be dialed.
+func (f *DialFailover) AddServer(server *Proxy) Handler {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.proxies = append(f.proxies, server)
+
+	return &failoverServer{failover: f, index: len(f.proxies) - 1}
+}
+
+type failoverServer struct {
+	failover *DialFailover
+	index    int
+}
+
+// ServeTCP forwards the connection to the first server which can be dialed, starting from the server chosen by the load balancer.
+func (s *failoverServer) ServeTCP(conn WriteCloser) {
+	// needed because of e.g. server.trackedConnection
+	defer conn.Close()
+
+	s.failover.lock.RLock()
+	proxies := s.failover.proxies
+	s.failover.lock.RUnlock()
+
+	var deadline time.Time
+	if s.failover.connectTimeout > 0 {
+		deadline = time.Now().Add(s.failover.connectTimeout)
+	}
+
+	for attempt := range len(proxies) {
+		server := proxies[(s.index+attempt)%len(proxies)]
+
+		timeout := s.failover.perAttemptDialTimeout
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				break
+			}
+
+			timeout = min(timeout, remaining)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		connBackend, err := server.dialBackend(ctx)
+		cancel()
+
+		if err != nil {
+			log.Debug().Err(err).
+				Str("address", server.address).
+				Int("attempt", attempt+1).
+				Msg("Error while dialing backend, failing over to the next server")
+			continue
+		}
+
+		log.Debug().
+			Str("address", server.address).
+			Str("remoteAddr", conn.RemoteAddr().String()).
+			Msg("Handling TCP connection")
+
+		server.forward(conn, connBackend)
+		return
+	}
+
+	log.Error().Msg("Error while dialing backend: no server could be dialed")
+	writeCloseReason(conn, CloseReasonBackendDown)
+}
@@ -0,0 +1,144 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blackholeDialer never connects to the blackhole addresses, and records the timeout of each of their dial attempts.
+type blackholeDialer struct {
+	blackholes map[string]struct{}
+
+	mu       sync.Mutex
+	attempts []time.Duration
+}
+
+func (d *blackholeDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+func (d *blackholeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if _, ok := d.blackholes[address]; !ok {
+		return (&net.Dialer{}).DialContext(ctx, network, address)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil, errors.New("dial attempt without timeout")
+	}
+
+	d.mu.Lock()
+	d.attempts = append(d.attempts, time.Until(deadline))
+	d.mu.Unlock()
+
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (d *blackholeDialer) TerminationDelay() time.Duration {
+	return 0
+}
+
+func TestDialFailover(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write([]byte("PONG"))
+			_ = conn.Close()
+		}
+	}()
+
+	testCases := []struct {
+		desc             string
+		connectTimeout   time.Duration
+		expectedAttempts int
+		expectedResponse string
+	}{
+		{
+			desc:             "fails over to the server which can be dialed",
+			expectedAttempts: 2,
+			expectedResponse: "PONG",
+		},
+		{
+			desc:             "connect timeout exhausted before reaching the server which can be dialed",
+			connectTimeout:   150 * time.Millisecond,
+			expectedAttempts: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			dialer := &blackholeDialer{blackholes: map[string]struct{}{
+				"192.0.2.1:80": {},
+				"192.0.2.2:80": {},
+			}}
+
+			perAttemptDialTimeout := 100 * time.Millisecond
+			failover := NewDialFailover(perAttemptDialTimeout, test.connectTimeout)
+
+			var handler Handler
+			for _, address := range []string{"192.0.2.1:80", "192.0.2.2:80", backendListener.Addr().String()} {
+				proxy, err := NewProxy(address, nil, false, dialer)
+				require.NoError(t, err)
+
+				serverHandler := failover.AddServer(proxy)
+				if handler == nil {
+					handler = serverHandler
+				}
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				handler.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			start := time.Now()
+			response, _ := io.ReadAll(conn)
+			elapsed := time.Since(start)
+
+			assert.Equal(t, test.expectedResponse, string(response))
+
+			dialer.mu.Lock()
+			defer dialer.mu.Unlock()
+
+			require.Len(t, dialer.attempts, test.expectedAttempts)
+			for _, timeout := range dialer.attempts {
+				assert.LessOrEqual(t, timeout, perAttemptDialTimeout)
+			}
+
+			if test.connectTimeout > 0 {
+				// The last attempt only gets the remaining of the connect timeout.
+				assert.Less(t, dialer.attempts[1], perAttemptDialTimeout)
+				assert.Less(t, elapsed, test.connectTimeout+perAttemptDialTimeout)
+			}
+		})
+	}
+}
@@ -1,11 +1,13 @@
 package tcp
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"sync"
 	"time"
 
@@ -35,6 +37,15 @@ func (d tcpDialer) TerminationDelay() time.Duration {
 	return d.terminationDelay
 }
 
+// DialContext dials the given address, the dial being canceled with the given context when the underlying dialer supports it.
+func (d tcpDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if contextDialer, ok := d.Dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, network, address)
+	}
+
+	return d.Dialer.Dial(network, address)
+}
+
 // SpiffeX509Source allows to retrieve a x509 SVID and bundle.
 type SpiffeX509Source interface {
 	x509svid.Source
@@ -110,6 +121,15 @@ func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransp
 		KeepAlive: time.Duration(cfg.DialKeepAlive),
 	}
 
+	if cfg.LocalAddress != "" {
+		localIP, err := lookupLocalIP(cfg.LocalAddress)
+		if err != nil {
+			return fmt.Errorf("invalid local address: %w", err)
+		}
+
+		dialer.LocalAddr = &net.TCPAddr{IP: localIP}
+	}
+
 	var tlsConfig *tls.Config
 
 	if cfg.TLS != nil {
@@ -157,6 +177,111 @@ func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransp
 	return nil
 }
 
+// BindLocalAddress returns a copy of the given dialer, whose connections originate from the given local address,
+// an IP address assigned to an interface of the host, optionally with a port.
+// When a port is given, the connections are reset when closed, for the port to be reusable right away,
+// instead of lingering in the TIME_WAIT state.
+func BindLocalAddress(dialer Dialer, address string) (Dialer, error) {
+	host, port := address, 0
+	if h, p, err := net.SplitHostPort(address); err == nil {
+		host = h
+		port, err = strconv.Atoi(p)
+		if err != nil || port < 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q", p)
+		}
+	}
+
+	localIP, err := lookupLocalIP(host)
+	if err != nil {
+		return nil, err
+	}
+
+	localAddr := &net.TCPAddr{IP: localIP, Port: port}
+
+	d, ok := dialer.(tcpDialer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported dialer %T", dialer)
+	}
+
+	switch baseDialer := d.Dialer.(type) {
+	case *net.Dialer:
+		netDialer := *baseDialer
+		netDialer.LocalAddr = localAddr
+		d.Dialer = &netDialer
+
+	case *tls.Dialer:
+		netDialer := *baseDialer.NetDialer
+		netDialer.LocalAddr = localAddr
+		d.Dialer = &tls.Dialer{NetDialer: &netDialer, Config: baseDialer.Config}
+
+	default:
+		return nil, fmt.Errorf("unsupported dialer %T", d.Dialer)
+	}
+
+	if port != 0 {
+		d.Dialer = resetOnCloseDialer{Dialer: d.Dialer}
+	}
+
+	return d, nil
+}
+
+// resetOnCloseDialer dials connections which are reset when closed.
+type resetOnCloseDialer struct {
+	proxy.Dialer
+}
+
+func (d resetOnCloseDialer) Dial(network, address string) (net.Conn, error) {
+	conn, err := d.Dialer.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return resetOnCloseConn{Conn: conn}, nil
+}
+
+type resetOnCloseConn struct {
+	net.Conn
+}
+
+// NetConn returns the wrapped connection.
+func (c resetOnCloseConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+func (c resetOnCloseConn) Close() error {
+	conn := c.Conn
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+
+	return c.Conn.Close()
+}
+
+// lookupLocalIP parses the given IP address, and checks that it is assigned to an interface of the host.
+func lookupLocalIP(address string) (net.IP, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("%q is not an IP address", address)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("listing interface addresses: %w", err)
+	}
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not assigned to any interface of the host", address)
+}
+
 func createRootCACertPool(rootCAs []types.FileOrContent) *x509.CertPool {
 	if len(rootCAs) == 0 {
 		return nil
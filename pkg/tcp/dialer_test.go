@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"fmt"
 	"io"
 	"math/big"
 	"net"
@@ -135,6 +136,127 @@ func TestConflictingConfig(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestLocalAddress(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	remoteAddrs := make(chan net.Addr, 1)
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		remoteAddrs <- conn.RemoteAddr()
+	}()
+
+	dialerManager := NewDialerManager(nil)
+
+	dynamicConf := map[string]*dynamic.TCPServersTransport{
+		"test": {
+			LocalAddress: "127.0.0.1",
+		},
+		"unassigned": {
+			// TEST-NET-1 address, never assigned to the host.
+			LocalAddress: "192.0.2.1",
+		},
+		"invalid": {
+			LocalAddress: "foobar",
+		},
+	}
+
+	dialerManager.Update(dynamicConf)
+
+	_, err = dialerManager.Get("unassigned", false)
+	require.Error(t, err)
+
+	_, err = dialerManager.Get("invalid", false)
+	require.Error(t, err)
+
+	dialer, err := dialerManager.Get("test", false)
+	require.NoError(t, err)
+
+	conn, err := dialer.Dial("tcp", backendListener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	localAddr, ok := conn.LocalAddr().(*net.TCPAddr)
+	require.True(t, ok)
+	assert.Equal(t, "127.0.0.1", localAddr.IP.String())
+
+	remoteAddr, ok := (<-remoteAddrs).(*net.TCPAddr)
+	require.True(t, ok)
+	assert.Equal(t, "127.0.0.1", remoteAddr.IP.String())
+}
+
+func TestBindLocalAddress(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	remoteAddrs := make(chan net.Addr, 2)
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+
+			remoteAddrs <- conn.RemoteAddr()
+
+			// The client closes the connection first, as the health check does.
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+
+	// A free local port, to bind the connections to.
+	portListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	localPort := portListener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, portListener.Close())
+
+	dialerManager := NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"test": {}})
+
+	dialer, err := dialerManager.Get("test", false)
+	require.NoError(t, err)
+
+	for _, address := range []string{"foobar", "192.0.2.1", "127.0.0.1:foo", "127.0.0.1:70000"} {
+		_, err = BindLocalAddress(dialer, address)
+		assert.Error(t, err, address)
+	}
+
+	boundDialer, err := BindLocalAddress(dialer, fmt.Sprintf("127.0.0.1:%d", localPort))
+	require.NoError(t, err)
+
+	// The connections being reset when closed, the local port can be bound again right away.
+	for range 2 {
+		conn, err := boundDialer.Dial("tcp", backendListener.Addr().String())
+		require.NoError(t, err)
+
+		remoteAddr, ok := (<-remoteAddrs).(*net.TCPAddr)
+		require.True(t, ok)
+		assert.Equal(t, "127.0.0.1", remoteAddr.IP.String())
+		assert.Equal(t, localPort, remoteAddr.Port)
+
+		require.NoError(t, conn.Close())
+	}
+
+	// The given dialer is left untouched.
+	conn, err := dialer.Dial("tcp", backendListener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	remoteAddr, ok := (<-remoteAddrs).(*net.TCPAddr)
+	require.True(t, ok)
+	assert.NotEqual(t, localPort, remoteAddr.Port)
+}
+
 func TestNoTLS(t *testing.T) {
 	backendListener, err := net.Listen("tcp", ":0")
 	require.NoError(t, err)
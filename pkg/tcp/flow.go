@@ -0,0 +1,96 @@
+package tcp
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// FlowRecord is the record of a proxied connection, as seen by its client.
+type FlowRecord struct {
+	Source      net.Addr
+	Destination net.Addr
+	Start       time.Time
+	End         time.Time
+	// BytesIn is the number of bytes received from the client.
+	BytesIn uint64
+	// BytesOut is the number of bytes sent to the client.
+	BytesOut uint64
+	Router   string
+	SNI      string
+}
+
+// FlowRecorder records the flows of the proxied connections.
+type FlowRecorder interface {
+	// Record records the given flow, without blocking.
+	Record(record FlowRecord)
+}
+
+// FlowHandler records the flow of each connection forwarded to a server of a service, once the connection is closed.
+type FlowHandler struct {
+	recorder FlowRecorder
+	next     Handler
+}
+
+// NewFlowHandler creates a new FlowHandler recording the flows with the given recorder.
+func NewFlowHandler(recorder FlowRecorder, next Handler) *FlowHandler {
+	return &FlowHandler{
+		recorder: recorder,
+		next:     next,
+	}
+}
+
+// ServeTCP forwards the connection to the server, and records its flow once it is closed.
+func (h *FlowHandler) ServeTCP(conn WriteCloser) {
+	record := FlowRecord{
+		Source:      conn.RemoteAddr(),
+		Destination: conn.LocalAddr(),
+		Start:       time.Now(),
+	}
+
+	if attributes := GetConnAttributes(conn); attributes != nil {
+		record.Router, _ = attributes.Get(RouterAttribute)
+		record.SNI, _ = attributes.Get(SNIAttribute)
+	}
+
+	counting := &countingConn{WriteCloser: conn}
+	h.next.ServeTCP(counting)
+
+	record.End = time.Now()
+	record.BytesIn = counting.read.Load()
+	record.BytesOut = counting.written.Load()
+
+	h.recorder.Record(record)
+}
+
+// countingConn counts the bytes read from, and written to, the connection.
+type countingConn struct {
+	WriteCloser
+
+	read    atomic.Uint64
+	written atomic.Uint64
+}
+
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.read.Add(uint64(n))
+
+	return n, err
+}
+
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	c.written.Add(uint64(n))
+
+	return n, err
+}
+
+// Attributes returns the attributes of the counted connection.
+func (c *countingConn) Attributes() *ConnAttributes {
+	return GetConnAttributes(c.WriteCloser)
+}
+
+// NetConn returns the counted connection.
+func (c *countingConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
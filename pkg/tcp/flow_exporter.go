@@ -0,0 +1,438 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Fields of the flow records exported by the FlowExporter.
+const (
+	FlowFieldSourceAddress      = "sourceAddress"
+	FlowFieldSourcePort         = "sourcePort"
+	FlowFieldDestinationAddress = "destinationAddress"
+	FlowFieldDestinationPort    = "destinationPort"
+	FlowFieldProtocol           = "protocol"
+	FlowFieldBytesIn            = "bytesIn"
+	FlowFieldBytesOut           = "bytesOut"
+	FlowFieldFlowStart          = "flowStart"
+	FlowFieldFlowEnd            = "flowEnd"
+	FlowFieldRouter             = "router"
+	FlowFieldSNI                = "sni"
+)
+
+// defaultFlowTemplate holds the fields exported when no template is configured,
+// i.e. all the fields but the enterprise-specific ones.
+var defaultFlowTemplate = []string{
+	FlowFieldSourceAddress,
+	FlowFieldSourcePort,
+	FlowFieldDestinationAddress,
+	FlowFieldDestinationPort,
+	FlowFieldProtocol,
+	FlowFieldBytesIn,
+	FlowFieldBytesOut,
+	FlowFieldFlowStart,
+	FlowFieldFlowEnd,
+}
+
+const (
+	ipfixVersion       = 10
+	ipfixTemplateSetID = 2
+	// The flows whose addresses are both IPv4 are exported with the IPv4 template, the other ones with the IPv6 template.
+	ipfixTemplateIDIPv4 = 256
+	ipfixTemplateIDIPv6 = 257
+
+	ipfixMessageHeaderLen = 16
+	ipfixSetHeaderLen     = 4
+	// ipfixMaxMessageSize bounds the size of the messages, for the UDP datagrams not to be fragmented.
+	ipfixMaxMessageSize = 1400
+
+	ipfixEnterpriseBit     = 0x8000
+	ipfixVariableLength    = 65535
+	ipfixMaxVariableLength = 254
+	// ipfixReverseEnterpriseNumber is the enterprise number of the reverse information elements of the biflows (RFC 5103).
+	ipfixReverseEnterpriseNumber = 29305
+	// ipfixTemplateRefreshInterval is the interval at which the templates are sent again,
+	// as a collector may have missed them, or restarted, UDP being unreliable.
+	ipfixTemplateRefreshInterval = time.Minute
+
+	protocolTCP = 6
+)
+
+// flowInformationElement is an IPFIX information element.
+type flowInformationElement struct {
+	id               uint16
+	length           uint16
+	enterpriseNumber uint32
+}
+
+// flowField is a field of the flow records, whose information element depends on the IP version of the flow.
+type flowField struct {
+	ipv4, ipv6 flowInformationElement
+	// enterprise reports whether the information element is specific to the enterprise number of the exporter.
+	enterprise bool
+	appendTo   func(b []byte, f flow) []byte
+}
+
+// flow is a flow record being encoded, with its parsed addresses.
+type flow struct {
+	FlowRecord
+
+	source      netip.AddrPort
+	destination netip.AddrPort
+	ipv6        bool
+}
+
+var flowFields = map[string]flowField{
+	FlowFieldSourceAddress: {
+		ipv4:     flowInformationElement{id: 8, length: 4},
+		ipv6:     flowInformationElement{id: 27, length: 16},
+		appendTo: func(b []byte, f flow) []byte { return appendFlowAddr(b, f.source.Addr(), f.ipv6) },
+	},
+	FlowFieldSourcePort: {
+		ipv4:     flowInformationElement{id: 7, length: 2},
+		appendTo: func(b []byte, f flow) []byte { return binary.BigEndian.AppendUint16(b, f.source.Port()) },
+	},
+	FlowFieldDestinationAddress: {
+		ipv4:     flowInformationElement{id: 12, length: 4},
+		ipv6:     flowInformationElement{id: 28, length: 16},
+		appendTo: func(b []byte, f flow) []byte { return appendFlowAddr(b, f.destination.Addr(), f.ipv6) },
+	},
+	FlowFieldDestinationPort: {
+		ipv4:     flowInformationElement{id: 11, length: 2},
+		appendTo: func(b []byte, f flow) []byte { return binary.BigEndian.AppendUint16(b, f.destination.Port()) },
+	},
+	FlowFieldProtocol: {
+		ipv4:     flowInformationElement{id: 4, length: 1},
+		appendTo: func(b []byte, _ flow) []byte { return append(b, protocolTCP) },
+	},
+	FlowFieldBytesIn: {
+		ipv4:     flowInformationElement{id: 1, length: 8},
+		appendTo: func(b []byte, f flow) []byte { return binary.BigEndian.AppendUint64(b, f.BytesIn) },
+	},
+	FlowFieldBytesOut: {
+		ipv4:     flowInformationElement{id: 1, length: 8, enterpriseNumber: ipfixReverseEnterpriseNumber},
+		appendTo: func(b []byte, f flow) []byte { return binary.BigEndian.AppendUint64(b, f.BytesOut) },
+	},
+	FlowFieldFlowStart: {
+		ipv4:     flowInformationElement{id: 152, length: 8},
+		appendTo: func(b []byte, f flow) []byte { return binary.BigEndian.AppendUint64(b, uint64(f.Start.UnixMilli())) },
+	},
+	FlowFieldFlowEnd: {
+		ipv4:     flowInformationElement{id: 153, length: 8},
+		appendTo: func(b []byte, f flow) []byte { return binary.BigEndian.AppendUint64(b, uint64(f.End.UnixMilli())) },
+	},
+	FlowFieldRouter: {
+		ipv4:       flowInformationElement{id: 1, length: ipfixVariableLength},
+		enterprise: true,
+		appendTo:   func(b []byte, f flow) []byte { return appendFlowString(b, f.Router) },
+	},
+	FlowFieldSNI: {
+		ipv4:       flowInformationElement{id: 2, length: ipfixVariableLength},
+		enterprise: true,
+		appendTo:   func(b []byte, f flow) []byte { return appendFlowString(b, f.SNI) },
+	},
+}
+
+// FlowExporter is a FlowRecorder exporting the flow records, in the IPFIX format (RFC 7011), to a collector over UDP.
+// The records are buffered, and sent in batches at each flush interval.
+type FlowExporter struct {
+	conn             net.Conn
+	fields           []flowField
+	enterpriseNumber uint32
+	flushInterval    time.Duration
+
+	records chan FlowRecord
+	dropped atomic.Uint64
+
+	// sequence is the number of data records sent, as expected in the headers of the IPFIX messages.
+	sequence      uint32
+	templatesSent time.Time
+}
+
+// NewFlowExporter creates a new FlowExporter sending the flow records to the collector at the given UDP address,
+// with the fields of the given template, or the default ones if empty.
+// The enterprise-specific fields, i.e. router and sni, require an enterprise number.
+// Up to bufferSize records are buffered until they are sent, the next ones being dropped.
+func NewFlowExporter(address string, template []string, enterpriseNumber uint32, bufferSize int, flushInterval time.Duration) (*FlowExporter, error) {
+	if len(template) == 0 {
+		template = defaultFlowTemplate
+	}
+
+	var fields []flowField
+	for _, name := range template {
+		field, ok := flowFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown flow record field %q", name)
+		}
+
+		if field.enterprise && enterpriseNumber == 0 {
+			return nil, fmt.Errorf("flow record field %q requires an enterprise number", name)
+		}
+
+		fields = append(fields, field)
+	}
+
+	if bufferSize < 1 {
+		return nil, fmt.Errorf("invalid flow records buffer size %d: must be at least 1", bufferSize)
+	}
+
+	if flushInterval <= 0 {
+		return nil, errors.New("flow records flush interval must be positive")
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("dialing the flow collector: %w", err)
+	}
+
+	return &FlowExporter{
+		conn:             conn,
+		fields:           fields,
+		enterpriseNumber: enterpriseNumber,
+		flushInterval:    flushInterval,
+		records:          make(chan FlowRecord, bufferSize),
+	}, nil
+}
+
+// Record buffers the given flow record, or drops it if the buffer is full.
+func (e *FlowExporter) Record(record FlowRecord) {
+	select {
+	case e.records <- record:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Run sends the buffered flow records at each flush interval, until the given context is done.
+func (e *FlowExporter) Run(ctx context.Context) {
+	defer func() { _ = e.conn.Close() }()
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.flush()
+			return
+		case <-ticker.C:
+			e.flush()
+		}
+	}
+}
+
+// flush sends the buffered flow records, in as many messages as needed.
+func (e *FlowExporter) flush() {
+	if dropped := e.dropped.Swap(0); dropped > 0 {
+		log.Warn().Uint64("dropped", dropped).Msg("Flow records dropped, as the flow records buffer is full")
+	}
+
+	count := len(e.records)
+	if count == 0 {
+		return
+	}
+
+	message := e.newMessage()
+	for range count {
+		templateID, record := e.encodeRecord(<-e.records)
+
+		if !message.add(templateID, record) {
+			e.send(message)
+
+			message = e.newMessage()
+			message.add(templateID, record)
+		}
+	}
+
+	e.send(message)
+}
+
+// newMessage starts a new IPFIX message, with the templates when they are to be sent again.
+func (e *FlowExporter) newMessage() *ipfixMessage {
+	message := &ipfixMessage{buf: make([]byte, ipfixMessageHeaderLen, ipfixMaxMessageSize)}
+
+	if time.Since(e.templatesSent) >= ipfixTemplateRefreshInterval {
+		message.buf = e.appendTemplateSet(message.buf)
+		e.templatesSent = time.Now()
+	}
+
+	return message
+}
+
+// appendTemplateSet appends the template set, defining the IPv4 and the IPv6 templates.
+func (e *FlowExporter) appendTemplateSet(b []byte) []byte {
+	start := len(b)
+	b = binary.BigEndian.AppendUint16(b, ipfixTemplateSetID)
+	b = binary.BigEndian.AppendUint16(b, 0)
+
+	for _, templateID := range []uint16{ipfixTemplateIDIPv4, ipfixTemplateIDIPv6} {
+		b = binary.BigEndian.AppendUint16(b, templateID)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(e.fields)))
+
+		for _, field := range e.fields {
+			element := field.ipv4
+			if templateID == ipfixTemplateIDIPv6 && field.ipv6.id != 0 {
+				element = field.ipv6
+			}
+
+			if field.enterprise {
+				element.enterpriseNumber = e.enterpriseNumber
+			}
+
+			if element.enterpriseNumber == 0 {
+				b = binary.BigEndian.AppendUint16(b, element.id)
+				b = binary.BigEndian.AppendUint16(b, element.length)
+				continue
+			}
+
+			b = binary.BigEndian.AppendUint16(b, element.id|ipfixEnterpriseBit)
+			b = binary.BigEndian.AppendUint16(b, element.length)
+			b = binary.BigEndian.AppendUint32(b, element.enterpriseNumber)
+		}
+	}
+
+	binary.BigEndian.PutUint16(b[start+2:], uint16(len(b)-start))
+
+	return b
+}
+
+// encodeRecord returns the encoded data record of the given flow record, and the ID of its template.
+func (e *FlowExporter) encodeRecord(record FlowRecord) (uint16, []byte) {
+	f := flow{
+		FlowRecord:  record,
+		source:      parseFlowAddr(record.Source),
+		destination: parseFlowAddr(record.Destination),
+	}
+	f.ipv6 = !f.source.Addr().Is4() || !f.destination.Addr().Is4()
+
+	var b []byte
+	for _, field := range e.fields {
+		b = field.appendTo(b, f)
+	}
+
+	if f.ipv6 {
+		return ipfixTemplateIDIPv6, b
+	}
+	return ipfixTemplateIDIPv4, b
+}
+
+// send completes the given message, and sends it to the collector.
+func (e *FlowExporter) send(message *ipfixMessage) {
+	b := message.finish(e.sequence)
+	e.sequence += message.records
+
+	if _, err := e.conn.Write(b); err != nil {
+		log.Debug().Err(err).Msg("Error while sending flow records to the collector")
+	}
+}
+
+// ipfixMessage is an IPFIX message being built, made of data sets of records of the same template.
+type ipfixMessage struct {
+	buf     []byte
+	records uint32
+
+	// setStart is the offset of the data set being built, if any.
+	setStart int
+	setID    uint16
+}
+
+// add adds the given data record of the given template to the message,
+// and returns false, without adding it, if the message would exceed the maximum message size.
+// The first record of a message is always added.
+func (m *ipfixMessage) add(templateID uint16, record []byte) bool {
+	size := len(record)
+	if m.setID != templateID {
+		size += ipfixSetHeaderLen
+	}
+
+	if m.records > 0 && len(m.buf)+size > ipfixMaxMessageSize {
+		return false
+	}
+
+	if m.setID != templateID {
+		m.closeSet()
+
+		m.setStart = len(m.buf)
+		m.setID = templateID
+		m.buf = binary.BigEndian.AppendUint16(m.buf, templateID)
+		m.buf = binary.BigEndian.AppendUint16(m.buf, 0)
+	}
+
+	m.buf = append(m.buf, record...)
+	m.records++
+
+	return true
+}
+
+// closeSet writes the length of the data set being built, if any.
+func (m *ipfixMessage) closeSet() {
+	if m.setID == 0 {
+		return
+	}
+
+	binary.BigEndian.PutUint16(m.buf[m.setStart+2:], uint16(len(m.buf)-m.setStart))
+}
+
+// finish writes the header of the message, with the given sequence number, and returns the message.
+func (m *ipfixMessage) finish(sequence uint32) []byte {
+	m.closeSet()
+
+	binary.BigEndian.PutUint16(m.buf[0:], ipfixVersion)
+	binary.BigEndian.PutUint16(m.buf[2:], uint16(len(m.buf)))
+	binary.BigEndian.PutUint32(m.buf[4:], uint32(time.Now().Unix()))
+	binary.BigEndian.PutUint32(m.buf[8:], sequence)
+	// The observation domain ID is left to zero.
+	binary.BigEndian.PutUint32(m.buf[12:], 0)
+
+	return m.buf
+}
+
+// parseFlowAddr returns the IP and port of the given address, the IPv4-mapped IPv6 addresses being unmapped.
+func parseFlowAddr(addr net.Addr) netip.AddrPort {
+	if addr == nil {
+		return netip.AddrPort{}
+	}
+
+	addrPort, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return netip.AddrPort{}
+	}
+
+	return netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port())
+}
+
+// appendFlowAddr appends the given address, as an IPv6 address for the IPv6 flows.
+func appendFlowAddr(b []byte, addr netip.Addr, ipv6 bool) []byte {
+	if !addr.IsValid() {
+		if ipv6 {
+			return append(b, make([]byte, 16)...)
+		}
+		return append(b, make([]byte, 4)...)
+	}
+
+	if ipv6 {
+		ip := addr.As16()
+		return append(b, ip[:]...)
+	}
+
+	ip := addr.As4()
+	return append(b, ip[:]...)
+}
+
+// appendFlowString appends the given string as a variable-length field, truncated to fit a one byte length.
+func appendFlowString(b []byte, s string) []byte {
+	if len(s) > ipfixMaxVariableLength {
+		s = s[:ipfixMaxVariableLength]
+	}
+
+	b = append(b, byte(len(s)))
+	return append(b, s...)
+}
@@ -0,0 +1,134 @@
+package tcp
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowExporter_recordOnClose(t *testing.T) {
+	collector, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = collector.Close() })
+
+	template := append(slices.Clone(defaultFlowTemplate), FlowFieldRouter, FlowFieldSNI)
+	exporter, err := NewFlowExporter(collector.LocalAddr().String(), template, 42, 10, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go exporter.Run(ctx)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// The server reads the 5 bytes of the client, and answers with 3 bytes before closing the connection.
+	handler := NewFlowHandler(exporter, HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		_, err := io.ReadFull(conn, make([]byte, 5))
+		assert.NoError(t, err)
+
+		_, err = conn.Write([]byte("bar"))
+		assert.NoError(t, err)
+	}))
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		attributesConn := WithConnAttributes(conn.(WriteCloser))
+		attributes := GetConnAttributes(attributesConn)
+		attributes.Set(RouterAttribute, "foo@file")
+		attributes.Set(SNIAttribute, "foo.example.com")
+
+		handler.ServeTCP(attributesConn)
+	}()
+
+	client, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err = client.Write([]byte("hello"))
+	require.NoError(t, err)
+
+	// No record is emitted until the connection is closed.
+	_, err = io.ReadAll(client)
+	require.NoError(t, err)
+
+	require.NoError(t, collector.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, ipfixMaxMessageSize)
+	n, _, err := collector.ReadFrom(buf)
+	require.NoError(t, err)
+
+	message := buf[:n]
+	assert.Equal(t, uint16(ipfixVersion), binary.BigEndian.Uint16(message[0:]))
+	assert.Equal(t, uint16(n), binary.BigEndian.Uint16(message[2:]))
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(message[8:]))
+
+	sets := parseIPFIXSets(t, message[ipfixMessageHeaderLen:])
+	require.Contains(t, sets, uint16(ipfixTemplateSetID))
+	require.Contains(t, sets, uint16(ipfixTemplateIDIPv4))
+
+	record := sets[ipfixTemplateIDIPv4]
+	clientAddr := client.LocalAddr().(*net.TCPAddr)
+	serverAddr := listener.Addr().(*net.TCPAddr)
+
+	assert.Equal(t, clientAddr.IP.To4(), net.IP(record[0:4]))
+	assert.Equal(t, uint16(clientAddr.Port), binary.BigEndian.Uint16(record[4:]))
+	assert.Equal(t, serverAddr.IP.To4(), net.IP(record[6:10]))
+	assert.Equal(t, uint16(serverAddr.Port), binary.BigEndian.Uint16(record[10:]))
+	assert.Equal(t, byte(protocolTCP), record[12])
+	assert.Equal(t, uint64(5), binary.BigEndian.Uint64(record[13:]))
+	assert.Equal(t, uint64(3), binary.BigEndian.Uint64(record[21:]))
+
+	start := binary.BigEndian.Uint64(record[29:])
+	end := binary.BigEndian.Uint64(record[37:])
+	assert.LessOrEqual(t, start, end)
+
+	values := record[45:]
+	require.Equal(t, byte(len("foo@file")), values[0])
+	assert.Equal(t, "foo@file", string(values[1:1+len("foo@file")]))
+
+	values = values[1+len("foo@file"):]
+	require.Equal(t, byte(len("foo.example.com")), values[0])
+	assert.Equal(t, "foo.example.com", string(values[1:]))
+}
+
+func TestNewFlowExporter_invalidTemplate(t *testing.T) {
+	_, err := NewFlowExporter("127.0.0.1:4739", []string{"foo"}, 0, 10, time.Second)
+	assert.Error(t, err)
+
+	// The enterprise-specific fields require an enterprise number.
+	_, err = NewFlowExporter("127.0.0.1:4739", []string{FlowFieldSourceAddress, FlowFieldSNI}, 0, 10, time.Second)
+	assert.Error(t, err)
+}
+
+// parseIPFIXSets returns the content of the given IPFIX sets, by set ID.
+func parseIPFIXSets(t *testing.T, b []byte) map[uint16][]byte {
+	t.Helper()
+
+	sets := make(map[uint16][]byte)
+	for len(b) > 0 {
+		require.GreaterOrEqual(t, len(b), ipfixSetHeaderLen)
+
+		id := binary.BigEndian.Uint16(b[0:])
+		length := int(binary.BigEndian.Uint16(b[2:]))
+		require.LessOrEqual(t, length, len(b))
+
+		sets[id] = b[ipfixSetHeaderLen:length]
+		b = b[length:]
+	}
+
+	return sets
+}
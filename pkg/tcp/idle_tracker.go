@@ -0,0 +1,124 @@
+package tcp
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// idleTracker calls onIdle once no data has been transferred in either direction of a proxied connection for the idle timeout.
+// Both the reads and the writes of data postpone the end of the timeout,
+// so that a connection is not reaped while its data is being written to a slow peer.
+// A nil idleTracker never fires.
+type idleTracker struct {
+	timeout time.Duration
+	onIdle  func()
+
+	mu           sync.Mutex
+	lastActivity time.Time
+	timer        *time.Timer
+	stopped      bool
+	reaped       bool
+}
+
+func newIdleTracker(timeout time.Duration, onIdle func()) *idleTracker {
+	t := &idleTracker{
+		timeout:      timeout,
+		onIdle:       onIdle,
+		lastActivity: time.Now(),
+	}
+
+	t.mu.Lock()
+	t.timer = time.AfterFunc(timeout, t.check)
+	t.mu.Unlock()
+
+	return t
+}
+
+// touch records an activity on the connection, which postpones its reaping.
+func (t *idleTracker) touch() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.lastActivity = time.Now()
+	t.mu.Unlock()
+}
+
+// isReaped reports whether the connection has been reaped for idleness.
+func (t *idleTracker) isReaped() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.reaped
+}
+
+func (t *idleTracker) stop() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stopped = true
+	t.timer.Stop()
+}
+
+// check reaps the connection if it has been idle for the timeout, and otherwise reschedules itself for the end of the timeout.
+func (t *idleTracker) check() {
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		return
+	}
+
+	idle := time.Since(t.lastActivity)
+	if idle < t.timeout {
+		t.timer.Reset(t.timeout - idle)
+		t.mu.Unlock()
+		return
+	}
+
+	t.reaped = true
+	t.mu.Unlock()
+
+	t.onIdle()
+}
+
+// activityReader records an activity on the idle tracker for each read data.
+type activityReader struct {
+	io.Reader
+
+	idle *idleTracker
+}
+
+func (r activityReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.idle.touch()
+	}
+
+	return n, err
+}
+
+// activityWriter records an activity on the idle tracker for each written data.
+type activityWriter struct {
+	io.Writer
+
+	idle *idleTracker
+}
+
+func (w activityWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if n > 0 {
+		w.idle.touch()
+	}
+
+	return n, err
+}
@@ -0,0 +1,351 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+)
+
+// mirrorCompareTimeout is the time given to the mirror, once the connection to the service is terminated,
+// to respond the bytes compared to the ones responded by the service.
+const mirrorCompareTimeout = 5 * time.Second
+
+// Results of the comparisons of the responses of the mirror to the ones of the service.
+const (
+	MirrorResultMatch      = "match"
+	MirrorResultDivergence = "divergence"
+)
+
+// Mirroring forwards the connections to a service, and mirrors a fraction of them to a mirror service,
+// whose responses are compared to the ones of the service.
+// The responses of the service are the only ones sent to the clients,
+// the mirror being fed the bytes of the clients without ever slowing them down.
+type Mirroring struct {
+	serviceName    string
+	handler        Handler
+	mirror         Handler
+	percent        uint64
+	maxCompareSize int
+	// comparisons counts the comparisons of the responses, by service and result.
+	comparisons gokitmetrics.Counter
+	logger      zerolog.Logger
+
+	total atomic.Uint64
+}
+
+// NewMirroring creates a new Mirroring of the given service,
+// mirroring the given percentage of the connections and comparing at most the given number of response bytes.
+func NewMirroring(serviceName string, handler, mirror Handler, percent, maxCompareSize int, comparisons gokitmetrics.Counter) *Mirroring {
+	return &Mirroring{
+		serviceName:    serviceName,
+		handler:        handler,
+		mirror:         mirror,
+		percent:        uint64(percent),
+		maxCompareSize: maxCompareSize,
+		comparisons:    comparisons,
+		logger:         log.Logger,
+	}
+}
+
+// ServeTCP forwards the connection to the service, and to the mirror when the connection is mirrored.
+func (m *Mirroring) ServeTCP(conn WriteCloser) {
+	if !m.mirrored() {
+		m.handler.ServeTCP(conn)
+		return
+	}
+
+	mirrorConn := newMirrorConn(conn, m.maxCompareSize)
+	go m.mirror.ServeTCP(mirrorConn)
+
+	tee := &teeConn{WriteCloser: conn, mirror: mirrorConn, maxSize: m.maxCompareSize}
+	m.handler.ServeTCP(tee)
+
+	// The connection to the service is terminated, whatever the reason, so is the one to the mirror.
+	mirrorConn.closeRequest()
+
+	go m.compare(tee.captured(), mirrorConn)
+}
+
+// mirrored reports whether the next connection is mirrored, the mirrored connections being evenly spread.
+func (m *Mirroring) mirrored() bool {
+	total := m.total.Add(1)
+	return total*m.percent/100 > (total-1)*m.percent/100
+}
+
+// compare compares the response of the mirror to the given response of the service, once the mirror responded as many bytes.
+func (m *Mirroring) compare(response []byte, mirrorConn *mirrorConn) {
+	defer func() { _ = mirrorConn.Close() }()
+
+	mirrorResponse, ok := mirrorConn.awaitResponse(len(response), mirrorCompareTimeout)
+	if !ok {
+		m.logger.Debug().
+			Str(logs.ServiceName, m.serviceName).
+			Str("remoteAddr", mirrorConn.RemoteAddr().String()).
+			Msg("The mirrored connection could not keep up with the client, its response is not compared")
+		return
+	}
+
+	offset, diverges := divergence(response, mirrorResponse)
+	if !diverges {
+		m.record(MirrorResultMatch)
+		return
+	}
+
+	m.record(MirrorResultDivergence)
+
+	m.logger.Warn().
+		Str(logs.ServiceName, m.serviceName).
+		Str("remoteAddr", mirrorConn.RemoteAddr().String()).
+		Int("offset", offset).
+		Int("responseSize", len(response)).
+		Int("mirrorResponseSize", len(mirrorResponse)).
+		Msg("The response of the mirror diverges from the one of the service")
+}
+
+func (m *Mirroring) record(result string) {
+	if m.comparisons != nil {
+		m.comparisons.With("service", m.serviceName, "result", result).Add(1)
+	}
+}
+
+// divergence returns the offset of the first byte the responses differ at, and whether they differ.
+func divergence(response, mirrorResponse []byte) (int, bool) {
+	size := min(len(response), len(mirrorResponse))
+	for i := range size {
+		if response[i] != mirrorResponse[i] {
+			return i, true
+		}
+	}
+
+	return size, len(response) != len(mirrorResponse)
+}
+
+// teeConn feeds the bytes read from the client to the mirror,
+// and captures the first bytes written to the client by the service.
+type teeConn struct {
+	WriteCloser
+
+	mirror  *mirrorConn
+	maxSize int
+
+	mu       sync.Mutex
+	response []byte
+}
+
+func (c *teeConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		c.mirror.feed(p[:n])
+	}
+	if err != nil {
+		c.mirror.closeRequest()
+	}
+
+	return n, err
+}
+
+func (c *teeConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+
+	c.mu.Lock()
+	c.response = appendBounded(c.response, p[:n], c.maxSize)
+	c.mu.Unlock()
+
+	return n, err
+}
+
+// captured returns the captured response of the service.
+func (c *teeConn) captured() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.response
+}
+
+// Attributes returns the attributes of the client connection.
+func (c *teeConn) Attributes() *ConnAttributes {
+	return GetConnAttributes(c.WriteCloser)
+}
+
+// NetConn returns the client connection.
+func (c *teeConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// mirrorConn is the in-memory connection of a mirrored client to the mirror.
+// The bytes of the client are buffered until the mirror reads them,
+// and the first bytes of the response of the mirror are captured, the rest being discarded.
+type mirrorConn struct {
+	client  WriteCloser
+	maxSize int
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// request holds the bytes of the client not yet read by the mirror.
+	request       []byte
+	requestClosed bool
+	// overflowed reports whether the mirror did not read the bytes of the client fast enough.
+	overflowed   bool
+	response     []byte
+	responseDone bool
+	closed       bool
+}
+
+func newMirrorConn(client WriteCloser, maxSize int) *mirrorConn {
+	c := &mirrorConn{client: client, maxSize: maxSize}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// feed buffers the given bytes of the client, the mirror being abandoned when it does not read them fast enough.
+func (c *mirrorConn) feed(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.requestClosed {
+		return
+	}
+
+	if len(c.request)+len(p) > c.maxSize {
+		c.overflowed = true
+		c.requestClosed = true
+		c.request = nil
+		c.cond.Broadcast()
+		return
+	}
+
+	c.request = append(c.request, p...)
+	c.cond.Broadcast()
+}
+
+// closeRequest ends the bytes of the client, the mirror reading an EOF once it read the buffered ones.
+func (c *mirrorConn) closeRequest() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requestClosed = true
+	c.cond.Broadcast()
+}
+
+// awaitResponse waits for the mirror to respond the given number of bytes, to terminate its response, or for the timeout,
+// and returns its captured response, unless the mirror overflowed.
+func (c *mirrorConn) awaitResponse(size int, timeout time.Duration) ([]byte, bool) {
+	size = min(size, c.maxSize)
+
+	timer := time.AfterFunc(timeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.responseDone = true
+		c.cond.Broadcast()
+	})
+	defer timer.Stop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for !c.overflowed && !c.responseDone && len(c.response) < size {
+		c.cond.Wait()
+	}
+
+	return c.response, !c.overflowed
+}
+
+func (c *mirrorConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.request) == 0 && !c.requestClosed && !c.closed {
+		c.cond.Wait()
+	}
+
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+
+	if len(c.request) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, c.request)
+	c.request = c.request[n:]
+
+	return n, nil
+}
+
+func (c *mirrorConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+
+	c.response = appendBounded(c.response, p, c.maxSize)
+	c.cond.Broadcast()
+
+	return len(p), nil
+}
+
+// CloseWrite terminates the response of the mirror.
+func (c *mirrorConn) CloseWrite() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responseDone = true
+	c.cond.Broadcast()
+
+	return nil
+}
+
+func (c *mirrorConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	c.requestClosed = true
+	c.responseDone = true
+	c.cond.Broadcast()
+
+	return nil
+}
+
+func (c *mirrorConn) LocalAddr() net.Addr {
+	return c.client.LocalAddr()
+}
+
+func (c *mirrorConn) RemoteAddr() net.Addr {
+	return c.client.RemoteAddr()
+}
+
+// The deadlines are not supported, the connection being closed once the response of the mirror is compared.
+
+func (c *mirrorConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (c *mirrorConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *mirrorConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+// Attributes returns the attributes of the client connection.
+func (c *mirrorConn) Attributes() *ConnAttributes {
+	return GetConnAttributes(c.client)
+}
+
+// appendBounded appends the given bytes to the buffer, without the buffer exceeding the given size.
+func appendBounded(buffer, p []byte, maxSize int) []byte {
+	return append(buffer, p[:min(len(p), max(maxSize-len(buffer), 0))]...)
+}
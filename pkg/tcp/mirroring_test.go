@@ -0,0 +1,137 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// comparisonsCounter sends the result of each counted comparison.
+type comparisonsCounter struct {
+	results chan string
+	result  string
+}
+
+func (c comparisonsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "result" {
+			c.result = labelValues[i+1]
+		}
+	}
+
+	return c
+}
+
+func (c comparisonsCounter) Add(float64) {
+	c.results <- c.result
+}
+
+// respondingHandler reads a 4 bytes request, and writes the given response.
+func respondingHandler(response string) Handler {
+	return HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		request := make([]byte, 4)
+		if _, err := io.ReadFull(conn, request); err != nil {
+			return
+		}
+
+		_, _ = conn.Write([]byte(response))
+	})
+}
+
+func TestMirroring(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		mirrorResponse string
+		maxCompareSize int
+		expected       string
+	}{
+		{
+			desc:           "identical responses",
+			mirrorResponse: "pong!",
+			maxCompareSize: 64,
+			expected:       MirrorResultMatch,
+		},
+		{
+			desc:           "diverging responses",
+			mirrorResponse: "pang!",
+			maxCompareSize: 64,
+			expected:       MirrorResultDivergence,
+		},
+		{
+			desc:           "longer mirror response",
+			mirrorResponse: "pong!!",
+			maxCompareSize: 64,
+			expected:       MirrorResultDivergence,
+		},
+		{
+			desc:           "responses diverging beyond the compared bytes",
+			mirrorResponse: "pong?",
+			maxCompareSize: 4,
+			expected:       MirrorResultMatch,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			comparisons := comparisonsCounter{results: make(chan string, 1)}
+			mirroring := NewMirroring("foo@file", respondingHandler("pong!"), respondingHandler(test.mirrorResponse), 100, test.maxCompareSize, comparisons)
+
+			client, server := net.Pipe()
+			t.Cleanup(func() { _ = client.Close() })
+
+			go mirroring.ServeTCP(&pipeWriteCloser{Conn: server})
+
+			_, err := client.Write([]byte("ping"))
+			require.NoError(t, err)
+
+			// The client only gets the response of the service.
+			response, err := io.ReadAll(client)
+			require.NoError(t, err)
+			assert.Equal(t, "pong!", string(response))
+
+			select {
+			case result := <-comparisons.results:
+				assert.Equal(t, test.expected, result)
+			case <-time.After(time.Second):
+				t.Fatal("The responses were not compared")
+			}
+		})
+	}
+}
+
+func TestMirroring_mirrored(t *testing.T) {
+	mirroring := NewMirroring("foo@file", nil, nil, 25, 64, nil)
+
+	var mirrored []bool
+	for range 8 {
+		mirrored = append(mirrored, mirroring.mirrored())
+	}
+
+	assert.Equal(t, []bool{false, false, false, true, false, false, false, true}, mirrored)
+}
+
+func TestMirrorConn_overflow(t *testing.T) {
+	client, server := net.Pipe()
+	t.Cleanup(func() { _ = client.Close() })
+
+	conn := newMirrorConn(&pipeWriteCloser{Conn: server}, 4)
+
+	// The bytes of the client exceed the buffer not yet read by the mirror.
+	conn.feed([]byte("pi"))
+	conn.feed([]byte("ng!"))
+
+	_, err := conn.Read(make([]byte, 4))
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, ok := conn.awaitResponse(4, time.Second)
+	assert.False(t, ok)
+}
@@ -0,0 +1,47 @@
+package tcp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// PrefixFrame is a metadata frame written to the backend connections before the data of the client,
+// e.g. for a backend to know the routing context of a connection without parsing its SNI.
+// It is rendered from a template whose data are the attributes of the connection, such as its router.
+type PrefixFrame struct {
+	tmpl *template.Template
+}
+
+// NewPrefixFrame creates a new PrefixFrame from the given template.
+func NewPrefixFrame(text string) (*PrefixFrame, error) {
+	// The attributes which are not set on a connection are rendered empty.
+	tmpl, err := template.New("prefixFrame").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix frame template: %w", err)
+	}
+
+	// The template is also rendered once without attributes, not to only detect its errors with the first connections.
+	if err := tmpl.Execute(io.Discard, map[string]string{}); err != nil {
+		return nil, fmt.Errorf("invalid prefix frame template: %w", err)
+	}
+
+	return &PrefixFrame{tmpl: tmpl}, nil
+}
+
+// writeTo renders the frame from the attributes of the given connection, and writes it to the given backend connection.
+func (f *PrefixFrame) writeTo(connBackend io.Writer, conn WriteCloser) error {
+	data := map[string]string{}
+	if attributes := GetConnAttributes(conn); attributes != nil {
+		data = attributes.snapshot()
+	}
+
+	var frame bytes.Buffer
+	if err := f.tmpl.Execute(&frame, data); err != nil {
+		return fmt.Errorf("rendering prefix frame: %w", err)
+	}
+
+	_, err := connBackend.Write(frame.Bytes())
+	return err
+}
@@ -0,0 +1,95 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrefixFrame(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		template    string
+		expectedErr bool
+	}{
+		{
+			desc:     "attributes",
+			template: "tenant=acme router={{ .router }} sni={{ .sni }}\n",
+		},
+		{
+			desc:        "unclosed action",
+			template:    "router={{ .router\n",
+			expectedErr: true,
+		},
+		{
+			desc:        "unknown function",
+			template:    "router={{ upper .router }}\n",
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid call",
+			template:    "router={{ index .router 1 }}\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := NewPrefixFrame(test.template)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestProxyPrefixFrame(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	proxy, err := NewProxy(backendListener.Addr().String(), nil, false, tcpDialer{&net.Dialer{}, 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	frame, err := NewPrefixFrame("tenant=acme router={{ .router }} sni={{ .sni }}\n")
+	require.NoError(t, err)
+	proxy.SetPrefixFrame(frame)
+
+	client, server := net.Pipe()
+
+	conn := WithConnAttributes(&pipeWriteCloser{Conn: server})
+	GetConnAttributes(conn).Set(RouterAttribute, "db-router@kubernetescrd")
+
+	go proxy.ServeTCP(conn)
+
+	_, err = client.Write([]byte("client bytes"))
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	select {
+	case data := <-received:
+		// The frame is written before the data of the client, the sni attribute not being set for a non-TLS connection.
+		assert.Equal(t, "tenant=acme router=db-router@kubernetescrd sni=\nclient bytes", data)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the backend did not receive the connection data")
+	}
+}
@@ -1,27 +1,46 @@
 package tcp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"golang.org/x/net/proxy"
 )
 
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
 	address       string
 	proxyProtocol *dynamic.ProxyProtocol
+	halfClose     bool
 	dialer        Dialer
+
+	idleTimeout time.Duration
+	idleReaped  gokitmetrics.Counter
+
+	maxConnectionDuration time.Duration
+
+	noDelay *bool
+
+	prefixFrame *PrefixFrame
+
+	dialDuration metrics.ScalableHistogram
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, dialer Dialer) (*Proxy, error) {
+// When halfClose is true, the half-close of the connection by one of its peers is propagated to the other peer,
+// and the connection is only terminated once both peers have closed their writing capability.
+func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, halfClose bool, dialer Dialer) (*Proxy, error) {
 	if proxyProtocol != nil && (proxyProtocol.Version < 1 || proxyProtocol.Version > 2) {
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
@@ -29,10 +48,43 @@ func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, dialer Diale
 	return &Proxy{
 		address:       address,
 		proxyProtocol: proxyProtocol,
+		halfClose:     halfClose,
 		dialer:        dialer,
 	}, nil
 }
 
+// SetIdleTimeout sets the duration after which the connections on which no data is transferred in either direction are closed.
+// The reaped connections are counted by the given counter, if any, labeled by the router they are routed by.
+func (p *Proxy) SetIdleTimeout(timeout time.Duration, reaped gokitmetrics.Counter) {
+	p.idleTimeout = timeout
+	p.idleReaped = reaped
+}
+
+// SetMaxConnectionDuration sets the duration after which the connections are closed, whether data is transferred or not.
+// Unlike the idle timeout, it is not postponed by the activity of the connections,
+// and the connections are closed by whichever of the two expires first.
+func (p *Proxy) SetMaxConnectionDuration(duration time.Duration) {
+	p.maxConnectionDuration = duration
+}
+
+// SetNoDelay sets whether Nagle's algorithm is disabled (TCP_NODELAY) on both the client and the backend connections.
+// When it is not set, the option of the connections is left as is, Go disabling Nagle's algorithm by default.
+func (p *Proxy) SetNoDelay(noDelay bool) {
+	p.noDelay = &noDelay
+}
+
+// SetPrefixFrame sets the frame written to the backend connections before the data of the client,
+// after the PROXY protocol header if any.
+func (p *Proxy) SetPrefixFrame(frame *PrefixFrame) {
+	p.prefixFrame = frame
+}
+
+// SetDialDurationHistogram sets the histogram observing the duration of the successful dials to the backend,
+// which is expected to be already labeled by service and server.
+func (p *Proxy) SetDialDurationHistogram(histogram metrics.ScalableHistogram) {
+	p.dialDuration = histogram
+}
+
 // ServeTCP forwards the connection to a service.
 func (p *Proxy) ServeTCP(conn WriteCloser) {
 	log.Debug().
@@ -43,16 +95,31 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	connBackend, err := p.dialBackend()
+	connBackend, err := p.dialBackend(context.Background())
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
+		writeCloseReason(conn, CloseReasonBackendDown)
 		return
 	}
 
+	p.forward(conn, connBackend)
+}
+
+// forward forwards the connection to the given backend connection, until one of them is terminated.
+func (p *Proxy) forward(conn, connBackend WriteCloser) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 	errChan := make(chan error)
 
+	if p.noDelay != nil {
+		if err := setNoDelay(conn, *p.noDelay); err != nil {
+			log.Debug().Err(err).Msg("Error while setting TCP_NODELAY on client connection")
+		}
+		if err := setNoDelay(connBackend, *p.noDelay); err != nil {
+			log.Debug().Err(err).Msg("Error while setting TCP_NODELAY on backend connection")
+		}
+	}
+
 	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
 		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
 		if _, err := header.WriteTo(connBackend); err != nil {
@@ -61,11 +128,45 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 		}
 	}
 
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	if p.prefixFrame != nil {
+		if err := p.prefixFrame.writeTo(connBackend, conn); err != nil {
+			log.Error().Err(err).Msg("Error while writing prefix frame to backend connection")
+			return
+		}
+	}
+
+	var idle *idleTracker
+	if p.idleTimeout > 0 {
+		idle = newIdleTracker(p.idleTimeout, func() {
+			p.reapIdle(conn)
 
-	err = <-errChan
-	if err != nil {
+			_ = conn.Close()
+			_ = connBackend.Close()
+		})
+		defer idle.stop()
+	}
+
+	var expired atomic.Bool
+	if p.maxConnectionDuration > 0 {
+		timer := time.AfterFunc(p.maxConnectionDuration, func() {
+			expired.Store(true)
+
+			log.Debug().
+				Str("address", p.address).
+				Str("remoteAddr", conn.RemoteAddr().String()).
+				Msgf("Closing TCP connection open for more than %s", p.maxConnectionDuration)
+
+			_ = conn.Close()
+			_ = connBackend.Close()
+		})
+		defer timer.Stop()
+	}
+
+	go p.connCopy(conn, connBackend, idle, errChan)
+	go p.connCopy(connBackend, conn, idle, errChan)
+
+	err := <-errChan
+	if err != nil && !idle.isReaped() && !expired.Load() {
 		// Treat connection reset error during a read operation with a lower log level.
 		// This allows to not report an RST packet sent by the peer as an error,
 		// as it is an abrupt but possible end for the TCP session
@@ -79,17 +180,55 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	<-errChan
 }
 
-func (p Proxy) dialBackend() (WriteCloser, error) {
-	conn, err := p.dialer.Dial("tcp", p.address)
+// dialBackend dials the backend, the dial being canceled with the given context when the dialer supports it.
+func (p Proxy) dialBackend(ctx context.Context) (WriteCloser, error) {
+	start := time.Now()
+
+	var conn net.Conn
+	var err error
+	if contextDialer, ok := p.dialer.(proxy.ContextDialer); ok {
+		conn, err = contextDialer.DialContext(ctx, "tcp", p.address)
+	} else {
+		conn, err = p.dialer.Dial("tcp", p.address)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if p.dialDuration != nil {
+		p.dialDuration.ObserveFromStart(start)
+	}
+
 	return conn.(WriteCloser), nil
 }
 
-func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
-	_, err := io.Copy(dst, src)
+// reapIdle reports the given connection as closed for exceeding the idle timeout.
+func (p *Proxy) reapIdle(conn WriteCloser) {
+	var router string
+	if attributes := GetConnAttributes(conn); attributes != nil {
+		router, _ = attributes.Get(RouterAttribute)
+	}
+
+	log.Debug().
+		Str("address", p.address).
+		Str("remoteAddr", conn.RemoteAddr().String()).
+		Str("router", router).
+		Msgf("Closing TCP connection idle for more than %s", p.idleTimeout)
+
+	if p.idleReaped != nil {
+		p.idleReaped.With("router", router).Add(1)
+	}
+}
+
+func (p Proxy) connCopy(dst, src WriteCloser, idle *idleTracker, errCh chan error) {
+	var reader io.Reader = src
+	var writer io.Writer = dst
+	if idle != nil {
+		reader = activityReader{Reader: src, idle: idle}
+		writer = activityWriter{Writer: dst, idle: idle}
+	}
+
+	_, err := io.Copy(writer, reader)
 	errCh <- err
 
 	// Ends the connection with the dst connection peer.
@@ -107,6 +246,11 @@ func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
 		return
 	}
 
+	// The dst connection peer keeps on being able to write until it closes its own writing capability.
+	if p.halfClose {
+		return
+	}
+
 	if p.dialer.TerminationDelay() >= 0 {
 		err := dst.SetReadDeadline(time.Now().Add(p.dialer.TerminationDelay()))
 		if err != nil {
@@ -115,6 +259,26 @@ func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
 	}
 }
 
+// netConnWrapper is implemented by the connection wrappers exposing the connection they wrap,
+// for the options of the underlying socket to be set.
+type netConnWrapper interface {
+	NetConn() net.Conn
+}
+
+// setNoDelay sets the TCP_NODELAY option of the TCP connection underlying the given connection.
+func setNoDelay(conn net.Conn, noDelay bool) error {
+	for {
+		switch typedConn := conn.(type) {
+		case *net.TCPConn:
+			return typedConn.SetNoDelay(noDelay)
+		case netConnWrapper:
+			conn = typedConn.NetConn()
+		default:
+			return fmt.Errorf("no TCP connection underlying the %T connection", typedConn)
+		}
+	}
+}
+
 // isSocketNotConnectedError reports whether err is a socket not connected error.
 func isSocketNotConnectedError(err error) bool {
 	var oerr *net.OpError
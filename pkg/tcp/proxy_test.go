@@ -5,9 +5,13 @@ import (
 	"errors"
 	"io"
 	"net"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,7 +58,7 @@ func TestCloseWrite(t *testing.T) {
 
 	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
 
-	proxy, err := NewProxy(":"+port, nil, dialer)
+	proxy, err := NewProxy(":"+port, nil, false, dialer)
 	require.NoError(t, err)
 
 	proxyListener, err := net.Listen("tcp", ":0")
@@ -88,6 +92,282 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, "PONG", buffer.String())
 }
 
+func TestHalfClose(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		halfClose bool
+		expected  string
+	}{
+		{
+			desc:     "terminated after the termination delay",
+			expected: "",
+		},
+		{
+			desc:      "half-close propagated",
+			halfClose: true,
+			expected:  "PONG",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			backendListener, err := net.Listen("tcp", ":0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = backendListener.Close() })
+
+			// The backend only replies once the client has closed its writing capability,
+			// and after a longer duration than the termination delay.
+			go func() {
+				conn, err := backendListener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				if _, err = io.Copy(io.Discard, conn); err != nil {
+					return
+				}
+
+				time.Sleep(100 * time.Millisecond)
+				_, _ = conn.Write([]byte("PONG"))
+			}()
+
+			_, port, err := net.SplitHostPort(backendListener.Addr().String())
+			require.NoError(t, err)
+
+			dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+			proxy, err := NewProxy(":"+port, nil, test.halfClose, dialer)
+			require.NoError(t, err)
+
+			proxyListener, err := net.Listen("tcp", ":0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = proxyListener.Close() })
+
+			go func() {
+				conn, err := proxyListener.Accept()
+				if err != nil {
+					return
+				}
+				proxy.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			_, err = conn.Write([]byte("ping\n"))
+			require.NoError(t, err)
+
+			err = conn.(*net.TCPConn).CloseWrite()
+			require.NoError(t, err)
+
+			response, err := io.ReadAll(conn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(response))
+		})
+	}
+}
+
+// recordingCounter records the value of a counter by label values.
+type recordingCounter struct {
+	labelValues []string
+
+	mu     *sync.Mutex
+	values map[string]float64
+}
+
+func newRecordingCounter() *recordingCounter {
+	return &recordingCounter{mu: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (c *recordingCounter) With(labelValues ...string) gokitmetrics.Counter {
+	return &recordingCounter{
+		labelValues: append(slices.Clone(c.labelValues), labelValues...),
+		mu:          c.mu,
+		values:      c.values,
+	}
+}
+
+func (c *recordingCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[strings.Join(c.labelValues, ",")] += delta
+}
+
+func (c *recordingCounter) value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.values[strings.Join(labelValues, ",")]
+}
+
+func TestIdleTimeout(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	// The backend echoes the received data.
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	proxy, err := NewProxy(backendListener.Addr().String(), nil, false, dialer)
+	require.NoError(t, err)
+
+	idleTimeout := 200 * time.Millisecond
+	reaped := newRecordingCounter()
+	proxy.SetIdleTimeout(idleTimeout, reaped)
+
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = proxyListener.Close() })
+
+	go func() {
+		for {
+			conn, err := proxyListener.Accept()
+			if err != nil {
+				return
+			}
+
+			// The connections are routed by the foo router.
+			routed := WithConnAttributes(conn.(*net.TCPConn))
+			GetConnAttributes(routed).Set(RouterAttribute, "foo")
+
+			go proxy.ServeTCP(routed)
+		}
+	}()
+
+	// A connection closed by the client is not counted as reaped.
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// The activity of a connection postpones its reaping.
+	conn, err = net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	start := time.Now()
+	for range 4 {
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+
+		time.Sleep(idleTimeout / 2)
+	}
+	assert.Greater(t, time.Since(start), idleTimeout)
+
+	// Once idle, the connection is closed, and counted as reaped by its router.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*idleTimeout)))
+	_, err = io.ReadAll(conn)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1, reaped.value("router", "foo"), 0)
+}
+
+func TestMaxConnectionDuration(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	// The backend echoes the received data.
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	proxy, err := NewProxy(backendListener.Addr().String(), nil, false, dialer)
+	require.NoError(t, err)
+
+	idleTimeout := 200 * time.Millisecond
+	maxConnectionDuration := 3 * idleTimeout
+	reaped := newRecordingCounter()
+	proxy.SetIdleTimeout(idleTimeout, reaped)
+	proxy.SetMaxConnectionDuration(maxConnectionDuration)
+
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = proxyListener.Close() })
+
+	go func() {
+		for {
+			conn, err := proxyListener.Accept()
+			if err != nil {
+				return
+			}
+
+			routed := WithConnAttributes(conn.(*net.TCPConn))
+			GetConnAttributes(routed).Set(RouterAttribute, "foo")
+
+			go proxy.ServeTCP(routed)
+		}
+	}()
+
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// The activity of the connection keeps on resetting the idle timer, but not the max connection duration.
+	start := time.Now()
+	buf := make([]byte, 4)
+	for {
+		require.NoError(t, conn.SetDeadline(time.Now().Add(5*maxConnectionDuration)))
+
+		if _, err = conn.Write([]byte("ping")); err != nil {
+			break
+		}
+
+		if _, err = io.ReadFull(conn, buf); err != nil {
+			break
+		}
+		assert.Equal(t, "ping", string(buf))
+
+		time.Sleep(idleTimeout / 4)
+	}
+
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, maxConnectionDuration)
+	assert.Less(t, elapsed, 5*maxConnectionDuration)
+
+	// The connection is not counted as reaped for idleness.
+	assert.InDelta(t, 0, reaped.value("router", "foo"), 0)
+}
+
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -133,7 +413,7 @@ func TestProxyProtocol(t *testing.T) {
 
 			dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
 
-			proxy, err := NewProxy(":"+port, &dynamic.ProxyProtocol{Version: test.version}, dialer)
+			proxy, err := NewProxy(":"+port, &dynamic.ProxyProtocol{Version: test.version}, false, dialer)
 			require.NoError(t, err)
 
 			proxyListener, err := net.Listen("tcp", ":0")
@@ -0,0 +1,119 @@
+//go:build linux || freebsd || openbsd || darwin
+
+package tcp
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+	"k8s.io/utils/ptr"
+)
+
+func TestNoDelay(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		noDelay  *bool
+		expected bool
+	}{
+		{
+			desc:     "platform default",
+			expected: true,
+		},
+		{
+			desc:     "Nagle's algorithm disabled",
+			noDelay:  ptr.To(true),
+			expected: true,
+		},
+		{
+			desc:     "Nagle's algorithm enabled",
+			noDelay:  ptr.To(false),
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = backendListener.Close() })
+
+			// The backend echoes the received data.
+			go func() {
+				conn, err := backendListener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				_, _ = io.Copy(conn, conn)
+			}()
+
+			proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = proxyListener.Close() })
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			clientConn, err := proxyListener.Accept()
+			require.NoError(t, err)
+
+			backendConn, err := net.Dial("tcp", backendListener.Addr().String())
+			require.NoError(t, err)
+
+			proxy, err := NewProxy(backendListener.Addr().String(), nil, false, tcpDialer{&net.Dialer{}, 10 * time.Millisecond})
+			require.NoError(t, err)
+
+			if test.noDelay != nil {
+				proxy.SetNoDelay(*test.noDelay)
+			}
+
+			// The client connection is wrapped, for the option to be set on its underlying socket.
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				proxy.forward(WithConnAttributes(clientConn.(*net.TCPConn)), backendConn.(*net.TCPConn))
+			}()
+
+			// The options are set before forwarding the data.
+			_, err = conn.Write([]byte("ping"))
+			require.NoError(t, err)
+
+			buf := make([]byte, 4)
+			_, err = io.ReadFull(conn, buf)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, noDelayOf(t, clientConn))
+			assert.Equal(t, test.expected, noDelayOf(t, backendConn))
+
+			require.NoError(t, conn.Close())
+			<-done
+		})
+	}
+}
+
+// noDelayOf returns whether the TCP_NODELAY option is set on the socket of the given connection.
+func noDelayOf(t *testing.T, conn net.Conn) bool {
+	t.Helper()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	require.NoError(t, err)
+
+	var value int
+	var sockOptErr error
+	err = rawConn.Control(func(fd uintptr) {
+		value, sockOptErr = unix.GetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_NODELAY)
+	})
+	require.NoError(t, err)
+	require.NoError(t, sockOptErr)
+
+	return value != 0
+}
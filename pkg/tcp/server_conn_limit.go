@@ -0,0 +1,106 @@
+package tcp
+
+import (
+	"errors"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+// errAllServersAtLimit is returned by the load balancers when all their servers are at their connection limit.
+var errAllServersAtLimit = errors.New("all servers are at their connection limit")
+
+// connLimiter is implemented by the servers limiting their number of connections.
+// The load balancers acquire a connection from the server they select, which releases it once the connection is closed.
+type connLimiter interface {
+	// acquire reserves a connection to the server, and reports whether the server was below its limit.
+	acquire() bool
+	// atLimit reports whether the server is at its connection limit.
+	atLimit() bool
+}
+
+// ConnLimitedServer limits the number of connections forwarded concurrently to a server of a load balancer.
+// The load balancers skip the servers at their limit when selecting a server,
+// and only reject the connections when all their servers are at their limit.
+type ConnLimitedServer struct {
+	next     Handler
+	maxConns int
+	gauge    gokitmetrics.Gauge
+
+	mu    sync.Mutex
+	conns int
+}
+
+// NewConnLimitedServer creates a new ConnLimitedServer, forwarding at most maxConns connections concurrently to the next handler.
+// The given gauge, if any, is expected to be already labeled by service and server, and is set to the number of connections of the server.
+func NewConnLimitedServer(maxConns int, gauge gokitmetrics.Gauge, next Handler) *ConnLimitedServer {
+	return &ConnLimitedServer{
+		next:     next,
+		maxConns: maxConns,
+		gauge:    gauge,
+	}
+}
+
+// ServeTCP forwards the connection acquired by the load balancer to the server, and releases it once the connection is closed.
+func (s *ConnLimitedServer) ServeTCP(conn WriteCloser) {
+	defer s.release()
+
+	s.next.ServeTCP(conn)
+}
+
+func (s *ConnLimitedServer) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conns >= s.maxConns {
+		return false
+	}
+
+	s.conns++
+	s.observe()
+
+	return true
+}
+
+func (s *ConnLimitedServer) atLimit() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.conns >= s.maxConns
+}
+
+func (s *ConnLimitedServer) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conns--
+	s.observe()
+}
+
+// observe sets the gauge to the number of connections of the server, it must be called with the lock held.
+func (s *ConnLimitedServer) observe() {
+	if s.gauge != nil {
+		s.gauge.Set(float64(s.conns))
+	}
+}
+
+// acquireServer acquires a connection from the given server, if it limits its number of connections.
+func acquireServer(handler Handler) bool {
+	limiter, ok := handler.(connLimiter)
+	return !ok || limiter.acquire()
+}
+
+// isServerAtLimit reports whether the given server limits its number of connections, and is at its limit.
+func isServerAtLimit(handler Handler) bool {
+	limiter, ok := handler.(connLimiter)
+	return ok && limiter.atLimit()
+}
+
+// closeReasonOf returns the reason of the connections closed for the given load balancing error.
+func closeReasonOf(err error) CloseReason {
+	if errors.Is(err, errAllServersAtLimit) {
+		return CloseReasonLimitExceeded
+	}
+
+	return CloseReasonBackendDown
+}
@@ -0,0 +1,135 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestConnLimitedServer_loadBalancers(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		loadBalancer func(servers map[string]Handler) Handler
+	}{
+		{
+			desc: "round robin",
+			loadBalancer: func(servers map[string]Handler) Handler {
+				balancer := NewWRRLoadBalancer(nil, "")
+				for name, server := range servers {
+					balancer.AddNamedServer(name, server)
+				}
+				return balancer
+			},
+		},
+		{
+			desc: "consistent hashing",
+			loadBalancer: func(servers map[string]Handler) Handler {
+				balancer := NewConsistentHashLoadBalancer("")
+				for name, server := range servers {
+					balancer.AddServer(name+":8080", name, server)
+				}
+				return balancer
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The servers hold the connections until they are released.
+			started := make(chan string)
+			release := make(chan struct{})
+			newServer := func(name string) Handler {
+				return HandlerFunc(func(conn WriteCloser) {
+					started <- name
+					<-release
+					_ = conn.Close()
+				})
+			}
+
+			h1Conns := generic.NewGauge("h1")
+			h2Conns := generic.NewGauge("h2")
+			balancer := test.loadBalancer(map[string]Handler{
+				"h1": NewConnLimitedServer(1, h1Conns, newServer("h1")),
+				"h2": NewConnLimitedServer(3, h2Conns, newServer("h2")),
+			})
+
+			// The connections exceeding the limit of h1 go to h2.
+			var wg sync.WaitGroup
+			for range 4 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					server, client := net.Pipe()
+					defer client.Close()
+
+					balancer.ServeTCP(&pipeWriteCloser{Conn: server})
+				}()
+			}
+
+			served := make(map[string]int)
+			for range 4 {
+				served[<-started]++
+			}
+			assert.Equal(t, map[string]int{"h1": 1, "h2": 3}, served)
+			assert.InDelta(t, 1, h1Conns.Value(), 0)
+			assert.InDelta(t, 3, h2Conns.Value(), 0)
+
+			// Once all the servers are at their limit, the connections are closed as exceeding the limit.
+			server, client := net.Pipe()
+			t.Cleanup(func() { _ = client.Close() })
+
+			conn := WithConnAttributes(&pipeWriteCloser{Conn: server})
+			GetConnAttributes(conn).SetCloseReasonEncoder(CloseReasonFrames{CloseReasonLimitExceeded: []byte("ERR limit exceeded\r\n")}, conn)
+
+			go balancer.ServeTCP(conn)
+
+			data, err := io.ReadAll(client)
+			require.NoError(t, err)
+			assert.Equal(t, "ERR limit exceeded\r\n", string(data))
+
+			// The servers are available again once their connections are closed.
+			close(release)
+			wg.Wait()
+
+			assert.InDelta(t, 0, h1Conns.Value(), 0)
+			assert.InDelta(t, 0, h2Conns.Value(), 0)
+
+			go func() {
+				server, client := net.Pipe()
+				defer client.Close()
+
+				balancer.ServeTCP(&pipeWriteCloser{Conn: server})
+			}()
+			assert.Contains(t, []string{"h1", "h2"}, <-started)
+		})
+	}
+}
+
+func TestConnLimitedServer_stickyClientCertificate(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	served := make(chan string, 2)
+	balancer := NewWRRLoadBalancer(&dynamic.TCPSticky{ClientCertificate: true}, "")
+	for _, name := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(name, NewConnLimitedServer(1, nil, HandlerFunc(func(conn WriteCloser) {
+			served <- name
+			<-release
+		})))
+	}
+
+	// The sticky server of the client certificate being at its limit, the next connection goes to the other server.
+	go balancer.ServeTCP(newFakeTLSConn([]byte("client")))
+	go balancer.ServeTCP(newFakeTLSConn([]byte("client")))
+
+	assert.ElementsMatch(t, []string{"h1", "h2"}, []string{<-served, <-served})
+}
@@ -1,16 +1,209 @@
 package tcp
 
 import (
+	"bytes"
+	"context"
 	"crypto/tls"
+	"errors"
+	"net"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
 )
 
+// tlsHandshakeTimeout bounds the TLS handshakes run by the TCP handlers,
+// as they run after the read deadline of the entry point was removed.
+var tlsHandshakeTimeout = 10 * time.Second
+
+// TLSHandshaker is implemented by the TLS terminated connections.
+type TLSHandshaker interface {
+	HandshakeContext(ctx context.Context) error
+}
+
+// HandshakeTLS runs the TLS handshake of the given connection, if not already done, within a bounded time.
+func HandshakeTLS(conn TLSHandshaker) error {
+	ctx, cancel := context.WithTimeout(context.Background(), tlsHandshakeTimeout)
+	defer cancel()
+
+	return conn.HandshakeContext(ctx)
+}
+
 // TLSHandler handles TLS connections.
 type TLSHandler struct {
 	Next   Handler
 	Config *tls.Config
+	// Limiter, when set, limits the number of concurrent TLS handshakes.
+	Limiter *TLSHandshakeLimiter
+	// CertificateTracker, when set, closes the connections when their certificate expires or is rotated.
+	CertificateTracker *CertificateTracker
+	// HandshakeFailureHandler, when set, handles the raw connections whose TLS handshake fails,
+	// the bytes read during the handshake being replayed to it, instead of closing them.
+	HandshakeFailureHandler Handler
 }
 
 // ServeTCP terminates the TLS connection.
 func (t *TLSHandler) ServeTCP(conn WriteCloser) {
-	t.Next.ServeTCP(tls.Server(conn, t.Config))
+	config := t.Config
+
+	var tracked *certificateConn
+	if t.CertificateTracker != nil {
+		tracked = &certificateConn{}
+		config = t.CertificateTracker.serverConfig(t.Config, tracked)
+	}
+
+	// The bytes read during the handshake are recorded, to be replayed to the handshake failure handler.
+	serverConn := conn
+	var recording *recordingConn
+	if t.HandshakeFailureHandler != nil {
+		recording = &recordingConn{WriteCloser: conn, recorded: &bytes.Buffer{}}
+		serverConn = recording
+	}
+
+	tlsConn := tls.Server(serverConn, config)
+
+	// The handshake is completed before handling the connection when it is limited, to know the served certificate,
+	// or to handle the connection to the handshake failure handler.
+	if t.Limiter != nil || tracked != nil || recording != nil {
+		if err := t.handshake(tlsConn); err != nil {
+			log.Debug().Err(err).Msg("Error during TLS handshake")
+
+			if recording != nil {
+				t.HandshakeFailureHandler.ServeTCP(&replayConn{WriteCloser: conn, replay: recording.recorded.Bytes()})
+				return
+			}
+
+			_ = conn.Close()
+			return
+		}
+	}
+
+	if recording != nil {
+		recording.stop()
+	}
+
+	if tracked != nil {
+		tracked.conn = conn
+		if err := t.CertificateTracker.track(tracked); err != nil {
+			log.Debug().Err(err).Msg("Unable to track the TLS connection certificate")
+		}
+		defer t.CertificateTracker.release(tracked)
+	}
+
+	t.Next.ServeTCP(withTLSAttributes(tlsConn, conn))
+}
+
+// recordingConn records the bytes read from the connection until stopped.
+type recordingConn struct {
+	WriteCloser
+
+	recorded *bytes.Buffer
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if c.recorded != nil {
+		c.recorded.Write(p[:n])
+	}
+
+	return n, err
+}
+
+// stop stops recording the bytes read, it must not be called concurrently with Read.
+func (c *recordingConn) stop() {
+	c.recorded = nil
+}
+
+// replayConn replays the given bytes before reading from the connection.
+type replayConn struct {
+	WriteCloser
+
+	replay []byte
+}
+
+func (c *replayConn) Read(p []byte) (int, error) {
+	if len(c.replay) > 0 {
+		n := copy(p, c.replay)
+		c.replay = c.replay[n:]
+		return n, nil
+	}
+
+	return c.WriteCloser.Read(p)
+}
+
+// Attributes returns the attributes of the connection.
+func (c *replayConn) Attributes() *ConnAttributes {
+	return GetConnAttributes(c.WriteCloser)
+}
+
+// NetConn returns the wrapped connection.
+func (c *replayConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (t *TLSHandler) handshake(tlsConn *tls.Conn) error {
+	if t.Limiter != nil {
+		return t.Limiter.Handshake(tlsConn)
+	}
+
+	return HandshakeTLS(tlsConn)
+}
+
+// TLSHandshakeLimiter limits the number of concurrent TLS handshakes,
+// the handshakes exceeding the limit are queued until a slot is available, or until the queue timeout is reached.
+type TLSHandshakeLimiter struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+
+	inProgressGauge gokitmetrics.Gauge
+	queuedGauge     gokitmetrics.Gauge
+}
+
+// NewTLSHandshakeLimiter creates a new TLSHandshakeLimiter.
+// The gauges report the number of in progress, and queued, handshakes.
+func NewTLSHandshakeLimiter(maxConcurrent int, queueTimeout time.Duration, inProgressGauge, queuedGauge gokitmetrics.Gauge) *TLSHandshakeLimiter {
+	return &TLSHandshakeLimiter{
+		slots:           make(chan struct{}, maxConcurrent),
+		queueTimeout:    queueTimeout,
+		inProgressGauge: inProgressGauge,
+		queuedGauge:     queuedGauge,
+	}
+}
+
+// Handshake runs the TLS handshake of the given connection once a slot is available.
+func (l *TLSHandshakeLimiter) Handshake(conn *tls.Conn) error {
+	if err := l.acquire(); err != nil {
+		return err
+	}
+	defer l.release()
+
+	return HandshakeTLS(conn)
+}
+
+func (l *TLSHandshakeLimiter) acquire() error {
+	select {
+	case l.slots <- struct{}{}:
+		l.inProgressGauge.Add(1)
+		return nil
+	default:
+	}
+
+	l.queuedGauge.Add(1)
+	defer l.queuedGauge.Add(-1)
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.inProgressGauge.Add(1)
+		return nil
+	case <-timer.C:
+		return errors.New("timeout while waiting for a TLS handshake slot")
+	}
+}
+
+func (l *TLSHandshakeLimiter) release() {
+	<-l.slots
+	l.inProgressGauge.Add(-1)
 }
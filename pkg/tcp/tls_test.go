@@ -0,0 +1,188 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+func TestTLSHandshakeLimiter(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	serverConfig := &tls.Config{Certificates: []tls.Certificate{*cert}}
+
+	inProgress := generic.NewGauge("inProgress")
+	queued := generic.NewGauge("queued")
+	limiter := NewTLSHandshakeLimiter(1, 100*time.Millisecond, inProgress, queued)
+
+	// The first client never sends its ClientHello, so its handshake holds the only slot.
+	stuckServer, stuckClient := net.Pipe()
+	t.Cleanup(func() {
+		_ = stuckServer.Close()
+		_ = stuckClient.Close()
+	})
+
+	stuckErr := make(chan error, 1)
+	go func() {
+		stuckErr <- limiter.Handshake(tls.Server(stuckServer, serverConfig))
+	}()
+
+	require.Eventually(t, func() bool { return inProgress.Value() == 1 }, time.Second, 5*time.Millisecond)
+
+	// The second handshake exceeds the limit, and times out while queued.
+	queuedServer, queuedClient := net.Pipe()
+	t.Cleanup(func() {
+		_ = queuedServer.Close()
+		_ = queuedClient.Close()
+	})
+
+	queuedErr := make(chan error, 1)
+	go func() {
+		queuedErr <- limiter.Handshake(tls.Server(queuedServer, serverConfig))
+	}()
+
+	require.Eventually(t, func() bool { return queued.Value() == 1 }, time.Second, 5*time.Millisecond)
+
+	select {
+	case err := <-queuedErr:
+		assert.ErrorContains(t, err, "timeout while waiting for a TLS handshake slot")
+	case <-time.After(time.Second):
+		t.Fatal("queued handshake did not time out")
+	}
+	assert.InDelta(t, 0, queued.Value(), 0)
+
+	// Once the slot is released, a new handshake completes.
+	_ = stuckClient.Close()
+	<-stuckErr
+	assert.InDelta(t, 0, inProgress.Value(), 0)
+
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		_ = server.Close()
+		_ = client.Close()
+	})
+
+	go func() {
+		_ = tls.Client(client, &tls.Config{InsecureSkipVerify: true}).Handshake()
+	}()
+
+	require.NoError(t, limiter.Handshake(tls.Server(server, serverConfig)))
+	assert.InDelta(t, 0, inProgress.Value(), 0)
+}
+
+func TestTLSHandler_handshakeFailureHandler(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		fallback bool
+	}{
+		{
+			desc: "dropped without handshake failure handler",
+		},
+		{
+			desc:     "handled by the handshake failure handler",
+			fallback: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			received := make(chan string, 1)
+
+			handler := &TLSHandler{
+				Config: &tls.Config{Certificates: []tls.Certificate{*cert}},
+				// Without handshake failure handler, the handshake happens, and fails, on the first read.
+				Next: HandlerFunc(func(conn WriteCloser) {
+					defer conn.Close()
+
+					_, err := conn.Read(make([]byte, 1))
+					assert.Error(t, err)
+					assert.False(t, test.fallback)
+				}),
+			}
+
+			if test.fallback {
+				handler.HandshakeFailureHandler = HandlerFunc(func(conn WriteCloser) {
+					defer conn.Close()
+
+					buf := make([]byte, len("PING\r\n"))
+					if _, err := io.ReadFull(conn, buf); err != nil {
+						t.Error(err)
+						return
+					}
+					received <- string(buf)
+
+					_, _ = conn.Write([]byte("+PONG\r\n"))
+				})
+			}
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+
+				handler.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			// The raw payload fails the TLS handshake.
+			_, err = conn.Write([]byte("PING\r\n"))
+			require.NoError(t, err)
+
+			response, _ := io.ReadAll(conn)
+
+			if !test.fallback {
+				// The connection is closed without reaching the fallback.
+				assert.NotContains(t, string(response), "+PONG")
+				return
+			}
+
+			// The bytes read during the handshake are replayed to the handshake failure handler.
+			assert.Equal(t, "PING\r\n", <-received)
+			assert.Contains(t, string(response), "+PONG\r\n")
+		})
+	}
+}
+
+func TestHandshakeTLS_timeout(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	timeout := tlsHandshakeTimeout
+	tlsHandshakeTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { tlsHandshakeTimeout = timeout })
+
+	// The client never sends its ClientHello.
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- HandshakeTLS(tls.Server(serverConn, &tls.Config{Certificates: []tls.Certificate{*cert}}))
+	}()
+
+	select {
+	case err := <-errCh:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("TLS handshake not bounded")
+	}
+}
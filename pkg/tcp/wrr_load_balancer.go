@@ -1,41 +1,91 @@
 package tcp
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
 	"errors"
 	"sync"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
 type server struct {
 	Handler
+	name   string
 	weight int
 }
 
+// tlsConn is implemented by the TLS terminated connections.
+type tlsConn interface {
+	HandshakeContext(ctx context.Context) error
+	ConnectionState() tls.ConnectionState
+}
+
 // WRRLoadBalancer is a naive RoundRobin load balancer for TCP services.
 type WRRLoadBalancer struct {
 	servers       []server
 	lock          sync.Mutex
 	currentWeight int
 	index         int
+	// down holds the names of the servers reported as down by the health check.
+	down map[string]struct{}
+
+	stickyClientCertificate bool
+	hashSeed                string
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
-func NewWRRLoadBalancer() *WRRLoadBalancer {
+// The given hash seed, when not empty, is mixed into the client certificate fingerprints of the sticky connections,
+// so that the assignment of the clients to the servers is specific to the seed.
+func NewWRRLoadBalancer(sticky *dynamic.TCPSticky, hashSeed string) *WRRLoadBalancer {
 	return &WRRLoadBalancer{
-		index: -1,
+		index:                   -1,
+		down:                    make(map[string]struct{}),
+		stickyClientCertificate: sticky != nil && sticky.ClientCertificate,
+		hashSeed:                hashSeed,
 	}
 }
 
 // ServeTCP forwards the connection to the right service.
 func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
+	if b.stickyClientCertificate {
+		fingerprint, err := clientCertificateFingerprint(conn)
+		if err != nil {
+			log.Debug().Err(err).Msg("Error during TLS handshake")
+			conn.Close()
+			return
+		}
+
+		if fingerprint != nil {
+			if b.hashSeed != "" {
+				fingerprint = seededHash(b.hashSeed, fingerprint)
+			}
+
+			b.lock.Lock()
+			next, err := b.stickyNext(fingerprint)
+			b.lock.Unlock()
+
+			if err != nil {
+				log.Error().Err(err).Msg("Error during load balancing")
+				CloseWithReason(conn, closeReasonOf(err))
+				return
+			}
+
+			next.ServeTCP(conn)
+			return
+		}
+	}
+
 	b.lock.Lock()
 	next, err := b.next()
 	b.lock.Unlock()
 
 	if err != nil {
 		log.Error().Err(err).Msg("Error during load balancing")
-		conn.Close()
+		CloseWithReason(conn, closeReasonOf(err))
 		return
 	}
 
@@ -48,6 +98,15 @@ func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 	b.AddWeightServer(serverHandler, &w)
 }
 
+// AddNamedServer appends a server to the existing list with a name,
+// the name being used by the health check to report the server status.
+func (b *WRRLoadBalancer) AddNamedServer(name string, serverHandler Handler) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.servers = append(b.servers, server{Handler: serverHandler, name: name, weight: 1})
+}
+
 // AddWeightServer appends a server to the existing list with a weight.
 func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
 	b.lock.Lock()
@@ -60,10 +119,40 @@ func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
 	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
 }
 
+// SetStatus sets the status (up or down) of a named server.
+func (b *WRRLoadBalancer) SetStatus(ctx context.Context, childName string, up bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	if up {
+		delete(b.down, childName)
+		return
+	}
+
+	b.down[childName] = struct{}{}
+}
+
+// isUp reports whether the server is not reported as down by the health check.
+func (b *WRRLoadBalancer) isUp(s server) bool {
+	if s.name == "" {
+		return true
+	}
+
+	_, down := b.down[s.name]
+	return !down
+}
+
 func (b *WRRLoadBalancer) maxWeight() int {
 	max := -1
 	for _, s := range b.servers {
-		if s.weight > max {
+		if b.isUp(s) && s.weight > max {
 			max = s.weight
 		}
 	}
@@ -73,6 +162,10 @@ func (b *WRRLoadBalancer) maxWeight() int {
 func (b *WRRLoadBalancer) weightGcd() int {
 	divisor := -1
 	for _, s := range b.servers {
+		if !b.isUp(s) {
+			continue
+		}
+
 		if divisor == -1 {
 			divisor = s.weight
 		} else {
@@ -100,10 +193,19 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 
 	// Maximum weight across all enabled servers
 	max := b.maxWeight()
+	if max == -1 {
+		return nil, errors.New("all servers are down")
+	}
 	if max == 0 {
 		return nil, errors.New("all servers have 0 weight")
 	}
 
+	// The servers at their connection limit are skipped below,
+	// so at least one server has to be available for the loop to end.
+	if !b.hasAvailableServer() {
+		return nil, errAllServersAtLimit
+	}
+
 	// GCD across all enabled servers
 	gcd := b.weightGcd()
 
@@ -116,8 +218,82 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			}
 		}
 		srv := b.servers[b.index]
-		if srv.weight >= b.currentWeight {
+		if b.isUp(srv) && srv.weight >= b.currentWeight && acquireServer(srv.Handler) {
 			return srv, nil
 		}
 	}
 }
+
+// hasAvailableServer reports whether a server up, and of a positive weight, is below its connection limit.
+func (b *WRRLoadBalancer) hasAvailableServer() bool {
+	for _, s := range b.servers {
+		if b.isUp(s) && s.weight > 0 && !isServerAtLimit(s.Handler) {
+			return true
+		}
+	}
+	return false
+}
+
+// stickyNext returns the server associated with the given key,
+// the same key always leads to the same server as long as the servers do not change.
+// When the server is at its connection limit, the connection goes to the next server below its limit.
+func (b *WRRLoadBalancer) stickyNext(key []byte) (Handler, error) {
+	var totalWeight uint64
+	for _, s := range b.servers {
+		if b.isUp(s) && s.weight > 0 {
+			totalWeight += uint64(s.weight)
+		}
+	}
+
+	if totalWeight == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	slot := binary.BigEndian.Uint64(key) % totalWeight
+	for i, s := range b.servers {
+		if !b.isUp(s) || s.weight <= 0 {
+			continue
+		}
+
+		if slot < uint64(s.weight) {
+			return b.availableFrom(i)
+		}
+		slot -= uint64(s.weight)
+	}
+
+	return nil, errors.New("no servers in the pool")
+}
+
+// availableFrom returns the first server up, starting from the given index, which is below its connection limit.
+func (b *WRRLoadBalancer) availableFrom(index int) (Handler, error) {
+	for i := range len(b.servers) {
+		s := b.servers[(index+i)%len(b.servers)]
+		if b.isUp(s) && s.weight > 0 && acquireServer(s.Handler) {
+			return s, nil
+		}
+	}
+
+	return nil, errAllServersAtLimit
+}
+
+// clientCertificateFingerprint returns the SHA-256 fingerprint of the verified client certificate of the connection.
+// It returns nil if the connection is not TLS terminated, or if the client did not present a verified certificate.
+func clientCertificateFingerprint(conn WriteCloser) ([]byte, error) {
+	tlsConn, ok := conn.(tlsConn)
+	if !ok {
+		return nil, nil
+	}
+
+	// The handshake has to be done to get the client certificate.
+	if err := HandshakeTLS(tlsConn); err != nil {
+		return nil, err
+	}
+
+	state := tlsConn.ConnectionState()
+	if len(state.VerifiedChains) == 0 || len(state.PeerCertificates) == 0 {
+		return nil, nil
+	}
+
+	fingerprint := sha256.Sum256(state.PeerCertificates[0].Raw)
+	return fingerprint[:], nil
+}
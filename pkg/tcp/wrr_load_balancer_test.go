@@ -1,12 +1,17 @@
 package tcp
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 )
 
 type fakeConn struct {
@@ -52,6 +57,33 @@ func (f *fakeConn) CloseWrite() error {
 	panic("implement me")
 }
 
+type fakeTLSConn struct {
+	*fakeConn
+
+	state tls.ConnectionState
+}
+
+func (f *fakeTLSConn) HandshakeContext(_ context.Context) error {
+	return nil
+}
+
+func (f *fakeTLSConn) ConnectionState() tls.ConnectionState {
+	return f.state
+}
+
+func newFakeTLSConn(certRaw []byte) *fakeTLSConn {
+	conn := &fakeTLSConn{fakeConn: &fakeConn{writeCall: make(map[string]int)}}
+	if certRaw != nil {
+		cert := &x509.Certificate{Raw: certRaw}
+		conn.state = tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	return conn
+}
+
 func TestLoadBalancing(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -124,7 +156,7 @@ func TestLoadBalancing(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			balancer := NewWRRLoadBalancer()
+			balancer := NewWRRLoadBalancer(nil, "")
 			for server, weight := range test.serversWeight {
 				balancer.AddWeightServer(HandlerFunc(func(conn WriteCloser) {
 					_, err := conn.Write([]byte(server))
@@ -142,3 +174,101 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestStickyClientCertificate(t *testing.T) {
+	balancer := NewWRRLoadBalancer(&dynamic.TCPSticky{ClientCertificate: true}, "")
+	for _, server := range []string{"h1", "h2", "h3"} {
+		balancer.AddServer(HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}))
+	}
+
+	for _, certRaw := range []string{"cert1", "cert2", "cert3", "cert4"} {
+		conn := newFakeTLSConn([]byte(certRaw))
+		for range 10 {
+			balancer.ServeTCP(conn)
+		}
+
+		// All the connections with the same client certificate land on the same server.
+		assert.Len(t, conn.writeCall, 1)
+		for _, count := range conn.writeCall {
+			assert.Equal(t, 10, count)
+		}
+	}
+
+	// Without client certificate, the connections are load balanced.
+	conn := newFakeTLSConn(nil)
+	for range 3 {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h1": 1, "h2": 1, "h3": 1}, conn.writeCall)
+}
+
+func TestStickyClientCertificateWithHashSeed(t *testing.T) {
+	// serversOf returns the servers the connections of the given client certificates are forwarded to.
+	serversOf := func(hashSeed string, certsRaw []string) []string {
+		balancer := NewWRRLoadBalancer(&dynamic.TCPSticky{ClientCertificate: true}, hashSeed)
+		for _, server := range []string{"h1", "h2", "h3", "h4"} {
+			balancer.AddServer(HandlerFunc(func(conn WriteCloser) {
+				_, err := conn.Write([]byte(server))
+				require.NoError(t, err)
+			}))
+		}
+
+		var servers []string
+		for _, certRaw := range certsRaw {
+			conn := newFakeTLSConn([]byte(certRaw))
+			balancer.ServeTCP(conn)
+
+			require.Len(t, conn.writeCall, 1)
+			for server := range conn.writeCall {
+				servers = append(servers, server)
+			}
+		}
+
+		return servers
+	}
+
+	var certsRaw []string
+	for i := range 20 {
+		certsRaw = append(certsRaw, fmt.Sprintf("cert%d", i))
+	}
+
+	// The assignment is deterministic for a given seed, and differs across the seeds.
+	assert.Equal(t, serversOf("foo", certsRaw), serversOf("foo", certsRaw))
+	assert.NotEqual(t, serversOf("foo", certsRaw), serversOf("bar", certsRaw))
+	assert.NotEqual(t, serversOf("", certsRaw), serversOf("foo", certsRaw))
+}
+
+func TestLoadBalancingWithServerStatus(t *testing.T) {
+	balancer := NewWRRLoadBalancer(nil, "")
+	for _, server := range []string{"h1", "h2"} {
+		balancer.AddNamedServer(server, HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(server))
+			require.NoError(t, err)
+		}))
+	}
+
+	balancer.SetStatus(context.Background(), "h1", false)
+
+	conn := &fakeConn{writeCall: make(map[string]int)}
+	for range 4 {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h2": 4}, conn.writeCall)
+
+	balancer.SetStatus(context.Background(), "h2", false)
+
+	balancer.ServeTCP(conn)
+	assert.Equal(t, 1, conn.closeCall)
+
+	balancer.SetStatus(context.Background(), "h1", true)
+	balancer.SetStatus(context.Background(), "h2", true)
+
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	for range 4 {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2}, conn.writeCall)
+}
@@ -18,3 +18,15 @@ func GetVersion(connState *tls.ConnectionState) string {
 
 	return "unknown"
 }
+
+// GetVersionName returns the name of the given TLS version, as accepted by the minVersion and maxVersion TLS options,
+// or an empty string when the version is unset or unknown.
+func GetVersionName(version uint16) string {
+	for name, value := range MinVersion {
+		if value == version {
+			return name
+		}
+	}
+
+	return ""
+}